@@ -0,0 +1,281 @@
+package main
+
+import (
+	"e0e1-config/pkg/browers"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/dbeaver"
+	"e0e1-config/pkg/filezilla"
+	"e0e1-config/pkg/finalshell"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/navicat"
+	"e0e1-config/pkg/notepad"
+	"e0e1-config/pkg/remotecontrol"
+	"e0e1-config/pkg/search"
+	"e0e1-config/pkg/status"
+	"e0e1-config/pkg/winscp"
+	"e0e1-config/pkg/xshell"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// runCollect collect 子命令: e0e1-config collect [选项] [模块...]
+func runCollect(args []string) {
+	var opts collectOptions
+	fs := flag.NewFlagSet("collect", flag.ExitOnError)
+	opts.registerFlags(fs)
+	fs.Usage = func() {
+		i18n.Printf("用法: e0e1-config collect [选项] [模块...]\n模块: %s，默认全部\n\n选项:\n", strings.Join(modules, ", "))
+		fs.PrintDefaults()
+	}
+
+	if positional := parseFlags(fs, "collect", args); len(positional) > 0 {
+		opts.Modules = strings.Join(positional, ",")
+	}
+	if err := opts.validate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+	if err := opts.setupRegistry(); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	result, records := collect(opts)
+	writeResult(result, opts.Output)
+	printStatus(records)
+}
+
+// printStatus 状态汇总属于诊断信息，和日志一样输出到标准错误，JSON 日志时每个模块输出一条记录
+func printStatus(records []status.Record) {
+	if !logger.JSON() {
+		logger.Print(slog.LevelInfo, status.Table(records))
+		return
+	}
+	for _, r := range records {
+		args := []any{"module", r.Module, "status", string(r.Kind), "items", r.Items, "duration", r.Duration}
+		if r.Err != nil {
+			args = append(args, "error", r.Err.Error())
+		}
+		logger.Info(i18n.T("模块执行状态"), args...)
+	}
+}
+
+// runSearch search 子命令: e0e1-config search [选项] [路径]
+func runSearch(args []string) {
+	var opts searchOptions
+	var output string
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	opts.registerFlags(fs, "")
+	fs.StringVar(&output, "output", "", "输出结果到指定文件")
+	fs.Usage = func() {
+		fmt.Println(i18n.T("用法: e0e1-config search [选项] [路径]\n\n选项:"))
+		fs.PrintDefaults()
+	}
+
+	if positional := parseFlags(fs, "search", args); len(positional) > 0 {
+		opts.Path = positional[0]
+	}
+
+	var c collector
+	searchModule(&c, opts)
+	result, _ := c.wait(1)
+	writeResult(result, output)
+}
+
+func collect(opts collectOptions) (string, []status.Record) {
+	var c collector
+	selected := opts.selected()
+
+	if err := credential.LoadCommonPasswords(opts.CommonPasswords); err != nil {
+		logger.Warn(err.Error())
+	}
+
+	if selected["notepad"] {
+		c.add(i18n.T("记事本"), i18n.T("===== 记事本内容 =====\n"), notepad.GetNotepadContent)
+	}
+
+	if selected["todesk"] {
+		c.add("ToDesk", "", func() (string, int, error) { return remotecontrol.ScanRemoteControl("todesk") })
+	}
+
+	if selected["sunlogin"] {
+		c.add(i18n.T("向日葵"), "", func() (string, int, error) { return remotecontrol.ScanRemoteControl("sunlogin") })
+	}
+
+	if selected["dbeaver"] {
+		c.add("DBeaver", i18n.T("===== DBeaver信息 =====\n"), func() (string, int, error) {
+			return dbeaver.ScanDBeaver(opts.DBeaverConfig, opts.DBeaverSources, opts.DBeaverWorkspace)
+		})
+	}
+
+	if selected["finalshell"] {
+		c.add("FinalShell", i18n.T("===== FinalShell信息 =====\n"), func() (string, int, error) {
+			return finalshell.ScanFinalShell(opts.FinalShellPath)
+		})
+	}
+
+	// Xshell 和 Xftp 共用主密码状态，放在同一组中依次执行
+	xshell.SetMasterPassword(opts.XshellMasterPassword)
+	xshell.SetUser(opts.XshellUser, opts.XshellSID)
+
+	if selected["xshell"] {
+		c.addShared("netsarang", "Xshell", i18n.T("===== Xshell信息 =====\n"), func() (string, int, error) {
+			return xshell.ScanXshell(opts.XshellPath)
+		})
+	}
+
+	if selected["xftp"] {
+		c.addShared("netsarang", "Xftp", i18n.T("===== Xftp信息 =====\n"), func() (string, int, error) {
+			return xshell.ScanXftp(opts.XftpPath)
+		})
+	}
+
+	filezilla.SetMasterPassword(opts.FileZillaMasterPassword)
+
+	if selected["filezilla"] {
+		c.add("FileZilla", i18n.T("===== FileZilla信息 =====\n"), func() (string, int, error) {
+			return filezilla.ScanFileZilla(opts.FileZillaPath)
+		})
+	}
+
+	if selected["navicat"] || opts.NavicatNCX != "" {
+		c.add("Navicat", i18n.T("===== Navicat信息 =====\n"), func() (string, int, error) {
+			return navicat.ScanNavicat(opts.NavicatNCX, selected["navicat"], opts.NavicatVersion)
+		})
+	}
+
+	winscp.SetMasterPassword(opts.WinSCPMasterPassword)
+
+	if selected["winscp"] {
+		c.add("WinSCP", i18n.T("===== WinSCP信息 =====\n"), func() (string, int, error) { return winscp.ScanWinSCP(opts.WinSCPPath) })
+	}
+
+	if selected["search"] {
+		searchModule(&c, opts.Search)
+	}
+
+	if selected["browser"] {
+		c.add(i18n.T("浏览器"), "", func() (string, int, error) { return browserModule(opts) })
+	}
+
+	result, records := c.wait(opts.Concurrency)
+	if opts.ScoresOnly {
+		// 各模块的结果中含有明文密码，只保留分析和评分
+		result = ""
+	}
+	return result + analyzeCredentials(opts), records
+}
+
+// analyzeCredentials 对各模块解密出的凭据做关联分析和强度评估，密码按 -redact 脱敏
+func analyzeCredentials(opts collectOptions) string {
+	creds := credential.All()
+	if len(creds) == 0 {
+		return ""
+	}
+
+	var resultBuilder strings.Builder
+	analysis := credential.Analyze(creds)
+	if opts.Correlate {
+		resultBuilder.WriteString(analysis.String())
+	}
+	if opts.Strength || opts.ScoresOnly {
+		resultBuilder.WriteString(credential.Assess(analysis.Credentials).String())
+	}
+	return resultBuilder.String()
+}
+
+func searchModule(c *collector, opts searchOptions) {
+	header := i18n.T("===== 敏感配置信息搜索结果 =====\n") + i18n.Sprintf("搜索路径: %s\n", opts.Path)
+	c.add(i18n.T("敏感配置信息搜索"), header, func() (string, int, error) { return search.Search(opts.options()) })
+}
+
+// browserModule 浏览器结果中各类浏览器有单独的标题，不统计条目数量，
+// 明细只写入结果，控制台上的逐条输出属于调试日志
+func browserModule(opts collectOptions) (string, int, error) {
+	var resultBuilder strings.Builder
+	browers.SetFormat(opts.BrowserFormat)
+	browers.SetOutputDir(opts.BrowserOutDir)
+	browers.SetLimit(opts.BrowserLimit)
+	browers.SetFirefoxPassword(opts.FirefoxPassword)
+	if opts.ScoresOnly {
+		browers.DisablePrintOut()
+	}
+
+	kind := opts.Browser
+	if kind == "" {
+		kind = "all"
+	}
+	var chromiumResult string
+	var chromiumOutput string
+	var FireOutput string
+	var IEOutput string
+	var err error
+
+	if opts.FirefoxProfile != "" {
+		FireOutput, err = browers.GetFirefoxFromProfile(opts.FirefoxProfile)
+		if err != nil {
+			return "", -1, err
+		}
+		if opts.BrowserFormat != "" {
+			FireOutput += i18n.Sprintf("已处理 %s 中的Firefox数据，结果保存在 %s 目录\n", opts.FirefoxProfile, opts.BrowserOutDir)
+		}
+	} else if opts.BrowserName != "" && opts.BrowserPath != "" {
+		chromiumOutput, err = browers.SpecifyPath(opts.BrowserName, opts.BrowserPath)
+		if err != nil {
+			return "", -1, err
+		}
+		chromiumResult = chromiumOutput
+		if opts.BrowserFormat != "" {
+			chromiumResult += i18n.Sprintf("已处理 %s 浏览器数据，结果保存在 %s 目录\n", opts.BrowserName, opts.BrowserOutDir)
+		}
+	} else {
+		switch kind {
+		case "all":
+			chromiumOutput = browers.ChromiumKernel()
+			FireOutput, _ = browers.GetFirefox()
+			IEOutput, _ = browers.GetIE()
+			if opts.BrowserFormat != "" {
+				chromiumOutput += i18n.Sprintf("已处理所有支持的浏览器数据，结果保存在 %s 目录\n", opts.BrowserOutDir)
+			}
+		case "chromium":
+			chromiumOutput = browers.ChromiumKernel()
+			if opts.BrowserFormat != "" {
+				chromiumOutput += i18n.Sprintf("已处理所有Chromium内核浏览器数据，结果保存在 %s 目录\n", opts.BrowserOutDir)
+			}
+		case "firefox":
+			FireOutput, _ = browers.GetFirefox()
+			if opts.BrowserFormat != "" {
+				FireOutput += i18n.Sprintf("已处理所有Firefox浏览器数据，结果保存在 %s 目录\n", opts.BrowserOutDir)
+			}
+		case "ie":
+			IEOutput, _ = browers.GetIE()
+			if opts.BrowserFormat != "" {
+				IEOutput += i18n.Sprintf("已处理所有IE浏览器数据，结果保存在 %s 目录\n", opts.BrowserOutDir)
+			}
+		}
+		chromiumResult = chromiumOutput
+	}
+
+	if chromiumResult != "" && opts.BrowserFormat == "" {
+		resultBuilder.WriteString(i18n.T("===== Chromium浏览器信息 =====\n"))
+		resultBuilder.WriteString(chromiumResult)
+		resultBuilder.WriteString("\n")
+	}
+
+	if FireOutput != "" && opts.BrowserFormat == "" {
+		resultBuilder.WriteString(i18n.T("===== Firefox浏览器信息 =====\n"))
+		resultBuilder.WriteString(FireOutput)
+		resultBuilder.WriteString("\n")
+	}
+	if IEOutput != "" && opts.BrowserFormat == "" {
+		resultBuilder.WriteString(i18n.T("===== IE浏览器信息 =====\n"))
+		resultBuilder.WriteString(IEOutput)
+		resultBuilder.WriteString("\n")
+	}
+
+	return resultBuilder.String(), -1, nil
+}
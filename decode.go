@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"e0e1-config/pkg/decode"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+)
+
+// runDecode 处理 decode 子命令: e0e1-config decode [选项] <类型> [密文]
+func runDecode(args []string) {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	version := fs.String("version", "", "Navicat加密版本(11/12)或Xshell会话版本(如7.1)")
+	host := fs.String("host", "", "WinSCP会话的主机名")
+	user := fs.String("user", "", "WinSCP会话的用户名，或Xshell会话所属的Windows用户名")
+	sid := fs.String("sid", "", "Xshell会话所属用户的SID")
+	masterPassword := fs.String("master-password", "", "WinSCP/Xshell/FileZilla的主密码")
+	pubKey := fs.String("pubkey", "", "FileZilla Pass元素的pubkey属性，指定后按主密码方式解密")
+	key := fs.String("key", "", "DBeaver自定义AES密钥(hex)")
+	iv := fs.String("iv", "", "DBeaver自定义IV(hex)")
+	file := fs.String("file", "", "批量模式，从文件中逐行读取密文")
+	jsonOutput := fs.Bool("json", false, "以JSON格式输出")
+	outputFile := fs.String("output", "", "输出结果到指定文件")
+	fs.Usage = func() {
+		i18n.Printf("用法: e0e1-config decode [选项] <类型> [密文]\n类型: %s\n\n选项:\n", strings.Join(decode.Types, ", "))
+		fs.PrintDefaults()
+	}
+
+	positional := parseFlags(fs, "decode", args)
+
+	if len(positional) == 0 || (len(positional) < 2 && *file == "") {
+		fs.Usage()
+		return
+	}
+
+	typ := positional[0]
+	values := positional[1:]
+	if *file != "" {
+		fileValues, err := readValues(*file)
+		if err != nil {
+			logger.Error(i18n.Sprintf("读取密文文件失败: %v", err))
+			return
+		}
+		values = append(values, fileValues...)
+	}
+
+	opts := decode.Options{
+		Version:        *version,
+		Host:           *host,
+		User:           *user,
+		SID:            *sid,
+		MasterPassword: *masterPassword,
+		PubKey:         *pubKey,
+		Key:            *key,
+		IV:             *iv,
+	}
+
+	var results []decode.Result
+	for _, value := range values {
+		results = append(results, decode.Decode(typ, value, opts))
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			logger.Error(i18n.Sprintf("生成JSON失败: %v", err))
+			return
+		}
+		writeResult(string(data), *outputFile)
+		return
+	}
+
+	var result strings.Builder
+	for _, r := range results {
+		if r.Error != "" {
+			result.WriteString(i18n.Sprintf("[-] %s: %s\n    错误: %s\n", r.Type, r.Input, r.Error))
+			continue
+		}
+		result.WriteString(i18n.Sprintf("[+] %s: %s\n    明文: %s\n", r.Type, r.Input, r.Output))
+		if r.Info != "" {
+			result.WriteString(i18n.Sprintf("    说明: %s\n", r.Info))
+		}
+	}
+	writeResult(result.String(), *outputFile)
+}
+
+// readValues 每行一个密文，忽略空行和 # 开头的注释
+func readValues(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	return values, scanner.Err()
+}
@@ -1,6 +1,6 @@
 module e0e1-config
 
-go 1.20
+go 1.21
 
 replace golang.org/x/sys => golang.org/x/sys v0.15.0
 
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"e0e1-config/pkg/browers"
+	"e0e1-config/pkg/dbeaver"
+	"e0e1-config/pkg/filezilla"
+	"e0e1-config/pkg/finalshell"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/navicat"
+	"e0e1-config/pkg/notepad"
+	"e0e1-config/pkg/remotecontrol"
+	"e0e1-config/pkg/search"
+	"e0e1-config/pkg/winscp"
+	"e0e1-config/pkg/xshell"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runInventoryCommand inventory 子命令: e0e1-config inventory [选项] [模块...]
+func runInventoryCommand(args []string) {
+	var opts collectOptions
+	fs := flag.NewFlagSet("inventory", flag.ExitOnError)
+	opts.registerFlags(fs)
+	fs.Usage = func() {
+		i18n.Printf("用法: e0e1-config inventory [选项] [模块...]\n模块: %s，默认全部\n\n选项:\n", strings.Join(modules, ", "))
+		fs.PrintDefaults()
+	}
+
+	if positional := parseFlags(fs, "inventory", args); len(positional) > 0 {
+		opts.Modules = strings.Join(positional, ",")
+	}
+	if err := opts.validate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+	if err := opts.setupRegistry(); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	writeResult(runInventory(opts), opts.Output)
+}
+
+// runInventory 复用各模块的路径发现逻辑，只报告存在的应用和凭据存储及其数量，不解密任何内容
+func runInventory(opts collectOptions) string {
+	var result strings.Builder
+	selected := opts.selected()
+
+	add := func(module, name string, scan func() (string, error)) {
+		if !selected[module] {
+			return
+		}
+		logger.Info(i18n.Sprintf("正在清点%s...", name), "module", module)
+		output, err := scan()
+		if err != nil {
+			result.WriteString(fmt.Sprintf("[-] %s: %v\n\n", name, err))
+			return
+		}
+		result.WriteString(fmt.Sprintf("[+] %s\n", name))
+		result.WriteString(output)
+		result.WriteString("\n")
+	}
+
+	add("notepad", i18n.T("记事本"), notepad.InventoryNotepad)
+	add("todesk", "ToDesk", func() (string, error) { return remotecontrol.InventoryRemoteControl("todesk") })
+	add("sunlogin", i18n.T("向日葵"), func() (string, error) { return remotecontrol.InventoryRemoteControl("sunlogin") })
+	add("dbeaver", "DBeaver", func() (string, error) {
+		return dbeaver.InventoryDBeaver(opts.DBeaverConfig, opts.DBeaverSources, opts.DBeaverWorkspace)
+	})
+	add("finalshell", "FinalShell", func() (string, error) { return finalshell.InventoryFinalShell(opts.FinalShellPath) })
+	add("xshell", "Xshell", func() (string, error) { return xshell.InventoryXshell(opts.XshellPath) })
+	add("xftp", "Xftp", func() (string, error) { return xshell.InventoryXftp(opts.XftpPath) })
+	add("filezilla", "FileZilla", func() (string, error) { return filezilla.InventoryFileZilla(opts.FileZillaPath) })
+	add("navicat", "Navicat", navicat.InventoryNavicat)
+	add("winscp", "WinSCP", func() (string, error) { return winscp.InventoryWinSCP(opts.WinSCPPath) })
+	add("browser", i18n.T("浏览器"), func() (string, error) {
+		output := browers.InventoryBrowsers()
+		if output == "" {
+			return "", i18n.Errorf("未找到浏览器数据")
+		}
+		return output, nil
+	})
+	add("search", i18n.T("敏感配置信息搜索"), func() (string, error) { return search.InventorySearch(opts.Search.options()) })
+
+	return i18n.T("===== 清点结果(未解密) =====\n") + result.String()
+}
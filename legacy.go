@@ -0,0 +1,147 @@
+package main
+
+import (
+	"e0e1-config/pkg/help"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// legacyModules 旧版本的模块开关参数，对应 collect 的模块名
+var legacyModules = map[string]string{
+	"notepad":     "notepad",
+	"sunlogin":    "sunlogin",
+	"todesk":      "todesk",
+	"navicat-reg": "navicat",
+	"dbeaver":     "dbeaver",
+	"finalshell":  "finalshell",
+	"xshell":      "xshell",
+	"xftp":        "xftp",
+	"filezilla":   "filezilla",
+	"winscp":      "winscp",
+	"search":      "search",
+	"all":         "all",
+}
+
+// legacyRenamed 改名的旧参数
+var legacyRenamed = map[string]string{
+	"bromium":       "browser",
+	"browers-limit": "browser-limit",
+}
+
+// runLegacy 兼容不带子命令的旧参数，映射到 collect / inventory 的选项上
+func runLegacy(args []string) {
+	var opts collectOptions
+	fs := flag.NewFlagSet("e0e1-config", flag.ExitOnError)
+	opts.registerFlags(fs)
+
+	for name, module := range legacyModules {
+		fs.Var(moduleFlag{opts: &opts, module: module}, name, "已弃用，请使用 collect "+module)
+	}
+	fs.StringVar(&opts.Browser, "bromium", "", "已弃用，请使用 -browser")
+	fs.StringVar(&opts.BrowserLimit, "browers-limit", "2000", "已弃用，请使用 -browser-limit")
+	inventoryFlag := fs.Bool("inventory", false, "已弃用，请使用 inventory 子命令")
+	helpFlag := fs.Bool("help", false, "显示帮助信息")
+	fs.Usage = help.ShowHelp
+
+	parseFlags(fs, "collect", args)
+
+	// 旧版本中指定DBeaver文件或浏览器路径即会执行对应模块
+	given := commandLineFlags(args)
+	if given["dbeaver-config"] || given["dbeaver-sources"] || given["dbeaver-workspace"] {
+		opts.addModule("dbeaver")
+	}
+	if given["bromium"] || (given["browser-name"] && given["browser-path"]) || given["firefox-profile"] {
+		opts.addModule("browser")
+	}
+
+	if *helpFlag || (!*inventoryFlag && opts.Modules == "" && opts.NavicatNCX == "") {
+		help.ShowHelp()
+		return
+	}
+	if err := opts.validate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+
+	command := "collect"
+	if *inventoryFlag {
+		command = "inventory"
+	}
+	logger.Warn(i18n.Sprintf("不带子命令的参数已弃用，等价的新命令: %s", legacyEquivalent(fs, given, command, opts)))
+
+	if err := opts.setupRegistry(); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	if *inventoryFlag {
+		writeResult(runInventory(opts), opts.Output)
+	} else {
+		result, records := collect(opts)
+		writeResult(result, opts.Output)
+		printStatus(records)
+	}
+}
+
+// legacyEquivalent 根据实际使用的旧参数生成等价的子命令写法
+func legacyEquivalent(fs *flag.FlagSet, given map[string]bool, command string, opts collectOptions) string {
+	var selected []string
+	for module := range opts.moduleList() {
+		selected = append(selected, module)
+	}
+
+	var options []string
+
+	fs.Visit(func(f *flag.Flag) {
+		switch {
+		case !given[f.Name] || f.Name == "inventory" || f.Name == "help" || f.Name == "config":
+			return
+		case legacyModules[f.Name] != "":
+			return
+		}
+
+		name := f.Name
+		if renamed, ok := legacyRenamed[name]; ok {
+			name = renamed
+		}
+		if getter, ok := f.Value.(flag.Getter); ok {
+			if _, isBool := getter.Get().(bool); isBool {
+				options = append(options, "-"+name)
+				return
+			}
+		}
+		value := f.Value.String()
+		if value == "" || strings.ContainsAny(value, " \t") {
+			value = fmt.Sprintf("%q", value)
+		}
+		options = append(options, fmt.Sprintf("-%s %s", name, value))
+	})
+
+	sort.Strings(selected)
+	parts := append([]string{"e0e1-config", command}, options...)
+	return strings.Join(append(parts, selected...), " ")
+}
+
+// commandLineFlags 返回命令行中实际出现的参数名，不包括从配置文件读取的
+func commandLineFlags(args []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name = name[:idx]
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"e0e1-config/pkg/config"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/regsource"
+	"e0e1-config/pkg/search"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// modules collect / inventory / report 可选的模块，未指定时执行全部
+var modules = []string{"notepad", "todesk", "sunlogin", "dbeaver", "finalshell", "xshell", "xftp",
+	"filezilla", "navicat", "winscp", "browser", "search"}
+
+type collectOptions struct {
+	Modules string
+
+	NavicatNCX     string
+	NavicatVersion int
+
+	DBeaverConfig    string
+	DBeaverSources   string
+	DBeaverWorkspace string
+	FinalShellPath   string
+
+	XshellPath           string
+	XshellMasterPassword string
+	XshellUser           string
+	XshellSID            string
+	XftpPath             string
+
+	FileZillaPath           string
+	FileZillaMasterPassword string
+	WinSCPPath              string
+	WinSCPMasterPassword    string
+
+	Browser         string
+	BrowserName     string
+	BrowserPath     string
+	BrowserFormat   string
+	BrowserOutDir   string
+	BrowserLimit    string
+	FirefoxProfile  string
+	FirefoxPassword string
+
+	Search searchOptions
+
+	RegFile      string
+	HiveNtuser   string
+	HiveSystem   string
+	HiveSoftware string
+
+	Output      string
+	Concurrency int
+	Correlate   bool
+
+	Strength        bool
+	ScoresOnly      bool
+	CommonPasswords string
+	Redact          string
+}
+
+type searchOptions struct {
+	Path      string
+	Regex     string
+	UserOnly  bool
+	FileTypes string
+	ExtenOnly bool
+	SizeLimit int64
+	CharLimit int
+}
+
+func (o *collectOptions) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Modules, "modules", "", "要执行的模块，多个用逗号分隔，默认全部")
+
+	fs.StringVar(&o.NavicatNCX, "navicat-ncx", "", "对导出的Navicat-ncx文件进行解密")
+	fs.IntVar(&o.NavicatVersion, "navicat-version", 0, "指定Navicat密码加密版本(11/12以及更高版本)，默认0自动识别")
+
+	fs.StringVar(&o.DBeaverConfig, "dbeaver-config", "", "指定DBeaver的credentials-config.json文件路径")
+	fs.StringVar(&o.DBeaverSources, "dbeaver-sources", "", "指定DBeaver的data-sources.json文件路径")
+	fs.StringVar(&o.DBeaverWorkspace, "dbeaver-workspace", "", "指定DBeaver的工作区目录(如workspace6)，解析其中所有项目")
+	fs.StringVar(&o.FinalShellPath, "finalshell-path", "", "指定FinalShell的conn文件夹路径")
+
+	fs.StringVar(&o.XshellPath, "xshell-path", "", "自定义指定Xshell的Sessions文件夹路径")
+	fs.StringVar(&o.XshellMasterPassword, "xshell-master-password", "", "指定Xshell/Xftp的主密码，用于解密启用主密码的会话")
+	fs.StringVar(&o.XshellUser, "xshell-user", "", "指定会话所属的Windows用户名，用于离线解密")
+	fs.StringVar(&o.XshellSID, "xshell-sid", "", "指定会话所属用户的SID，用于离线解密")
+	fs.StringVar(&o.XftpPath, "xftp-path", "", "自定义指定Xftp的Sessions文件夹路径")
+
+	fs.StringVar(&o.FileZillaPath, "filezilla-path", "", "自定义指定FileZilla的配置文件夹路径")
+	fs.StringVar(&o.FileZillaMasterPassword, "filezilla-master-password", "", "指定FileZilla的主密码，用于解密受主密码保护的密码")
+	fs.StringVar(&o.WinSCPPath, "winscp-path", "", "自定义指定WinSCP的配置文件路径")
+	fs.StringVar(&o.WinSCPMasterPassword, "winscp-master-password", "", "指定WinSCP的主密码，用于解密启用主密码的配置")
+
+	fs.StringVar(&o.Browser, "browser", "", "指定要扫描的浏览器内核类型 (all, chromium, firefox, ie)，默认all")
+	fs.StringVar(&o.BrowserName, "browser-name", "", "指定浏览器名称")
+	fs.StringVar(&o.BrowserPath, "browser-path", "", "指定浏览器数据路径")
+	fs.StringVar(&o.BrowserFormat, "browser-format", "", "输出格式 (csv 或 json)，默认只输出到控制台")
+	fs.StringVar(&o.BrowserOutDir, "browser-outdir", "out", "指定浏览器数据保存目录")
+	fs.StringVar(&o.BrowserLimit, "browser-limit", "2000", "指定读取的数据行数，默认2000个数据")
+	fs.StringVar(&o.FirefoxProfile, "firefox-profile", "", "指定拷贝出来的Firefox配置目录进行离线解密")
+	fs.StringVar(&o.FirefoxPassword, "firefox-password", "", "指定Firefox的主密码(Primary Password)")
+
+	o.Search.registerFlags(fs, "search-")
+
+	fs.StringVar(&o.RegFile, "reg-file", "", "指定导出的.reg文件代替系统注册表，多个文件用逗号分隔")
+	fs.StringVar(&o.HiveNtuser, "hive-ntuser", "", "指定拷贝出来的NTUSER.DAT，作为HKEY_CURRENT_USER")
+	fs.StringVar(&o.HiveSystem, "hive-system", "", "指定拷贝出来的SYSTEM hive，作为HKEY_LOCAL_MACHINE\\SYSTEM")
+	fs.StringVar(&o.HiveSoftware, "hive-software", "", "指定拷贝出来的SOFTWARE hive，作为HKEY_LOCAL_MACHINE\\SOFTWARE")
+
+	fs.StringVar(&o.Output, "output", "", "输出结果到指定文件")
+	fs.IntVar(&o.Concurrency, "concurrency", 4, "同时执行的模块数量，1为依次执行")
+	fs.BoolVar(&o.Correlate, "correlate", true, "对解密出的凭据去重并分析跨主机、跨应用的密码复用")
+	fs.BoolVar(&o.Strength, "strength", true, "评估解密出的密码强度，并按应用统计")
+	fs.BoolVar(&o.ScoresOnly, "scores-only", false, "只输出凭据分析和强度评分，不输出各模块结果和明文密码")
+	fs.StringVar(&o.CommonPasswords, "common-passwords", "", "指定常见密码列表文件(每行一个)，追加到内置列表")
+	fs.StringVar(&o.Redact, "redact", credential.RedactNone, "分析结果和HTML报告中密码的脱敏方式 (none, partial, full)")
+}
+
+// registerFlags search 子命令中不带前缀，collect 中使用 search- 前缀
+func (o *searchOptions) registerFlags(fs *flag.FlagSet, prefix string) {
+	fs.StringVar(&o.Path, prefix+"path", ".", "指定搜索路径")
+	fs.StringVar(&o.Regex, prefix+"regex", "", "自定义正则表达式，多个表达式用逗号分隔")
+	fs.BoolVar(&o.UserOnly, prefix+"user-only", false, "仅使用用户提供的正则表达式")
+	fs.StringVar(&o.FileTypes, prefix+"file-types", "", "自定义文件类型列表")
+	fs.BoolVar(&o.ExtenOnly, prefix+"exten-only", false, "仅搜索指定扩展名的文件")
+	fs.Int64Var(&o.SizeLimit, prefix+"size-limit", 10*1024*1024, "文件大小限制(字节)")
+	fs.IntVar(&o.CharLimit, prefix+"char-limit", 1000, "匹配行字符数限制")
+}
+
+func (o searchOptions) options() search.SearchOptions {
+	var userRegexList []string
+	if o.Regex != "" {
+		userRegexList = strings.Split(o.Regex, ",")
+	}
+
+	return search.SearchOptions{
+		Path:               o.Path,
+		UserRegexList:      userRegexList,
+		UserOnlyFlag:       o.UserOnly,
+		CustomFileTypeList: o.FileTypes,
+		ExtenOnlyFlag:      o.ExtenOnly,
+		SizeLimit:          o.SizeLimit,
+		CharLimit:          o.CharLimit,
+	}
+}
+
+func (o *collectOptions) addModule(module string) {
+	if o.Modules == "" {
+		o.Modules = module
+	} else {
+		o.Modules += "," + module
+	}
+}
+
+// moduleList 返回 -modules 或位置参数指定的模块
+func (o collectOptions) moduleList() map[string]bool {
+	selected := make(map[string]bool)
+	for _, module := range strings.Split(o.Modules, ",") {
+		if module = strings.ToLower(strings.TrimSpace(module)); module != "" {
+			selected[module] = true
+		}
+	}
+	return selected
+}
+
+// selected 未指定任何模块或指定了all时返回全部模块，只指定 -navicat-ncx 时只解析NCX文件
+func (o collectOptions) selected() map[string]bool {
+	selected := o.moduleList()
+	if selected["all"] || (len(selected) == 0 && o.NavicatNCX == "") {
+		selected = make(map[string]bool)
+		for _, module := range modules {
+			selected[module] = true
+		}
+	}
+	return selected
+}
+
+// redactMode -scores-only 时总是完全隐藏密码
+func (o collectOptions) redactMode() string {
+	if o.ScoresOnly {
+		return credential.RedactFull
+	}
+	return o.Redact
+}
+
+func (o collectOptions) validate() error {
+	if o.Concurrency < 1 {
+		return i18n.Errorf("并发数必须大于0: %d", o.Concurrency)
+	}
+	if err := credential.SetRedact(o.redactMode()); err != nil {
+		return err
+	}
+	for module := range o.moduleList() {
+		if module == "all" {
+			continue
+		}
+		known := false
+		for _, name := range modules {
+			known = known || name == module
+		}
+		if !known {
+			return i18n.Errorf("未知模块: %s，可选: %s", module, strings.Join(modules, ", "))
+		}
+	}
+	return nil
+}
+
+// setupRegistry 指定了离线注册表时替换默认的注册表来源
+func (o collectOptions) setupRegistry() error {
+	if o.RegFile == "" && o.HiveNtuser == "" && o.HiveSystem == "" && o.HiveSoftware == "" {
+		return nil
+	}
+
+	var regFiles []string
+	if o.RegFile != "" {
+		regFiles = strings.Split(o.RegFile, ",")
+	}
+	source, err := regsource.Offline(regFiles, o.HiveNtuser, o.HiveSystem, o.HiveSoftware)
+	if err != nil {
+		return i18n.Errorf("加载离线注册表失败: %v", err)
+	}
+	regsource.SetDefault(source)
+	return nil
+}
+
+// parseFlags 先应用配置文件中的默认值再解析命令行，命令行参数优先；
+// 允许位置参数和选项交替出现，返回所有位置参数
+func parseFlags(fs *flag.FlagSet, section string, args []string) []string {
+	var cfg config.Config
+	path, explicit := findArg(args, "config")
+	if !explicit {
+		path = config.DefaultPath
+	}
+	if _, err := os.Stat(path); err == nil || explicit {
+		loaded, err := config.Load(path)
+		if err != nil {
+			logger.Error(i18n.Sprintf("加载配置文件失败: %v", err))
+			os.Exit(2)
+		}
+		cfg = loaded
+	}
+
+	// 语言需要在输出任何提示和 -h 帮助之前确定
+	if value, ok := findArg(args, "lang"); ok {
+		setLang(value)
+	} else if value, ok := cfg.Values(section)["lang"]; ok {
+		setLang(value)
+	}
+	localizeFlags(fs)
+	fs.String("config", "", i18n.Sprintf("指定配置文件，默认读取当前目录下的 %s", config.DefaultPath))
+	lang := fs.String("lang", i18n.Lang(), i18n.Sprintf("输出语言: %s", strings.Join(i18n.Langs, ", ")))
+	quiet := fs.Bool("q", false, i18n.T("只输出警告和错误"))
+	verbose := fs.Bool("v", false, i18n.T("输出调试信息"))
+	trace := fs.Bool("vv", false, i18n.T("输出更详细的调试信息"))
+	logFormat := fs.String("log-format", "text", i18n.Sprintf("日志格式: %s，日志输出到标准错误", strings.Join(logger.Formats, ", ")))
+	applyConfig(fs, cfg, section)
+
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	setLang(*lang)
+
+	level := 0
+	if *trace {
+		level = 2
+	} else if *verbose {
+		level = 1
+	}
+	if err := logger.Setup(logger.LevelFor(*quiet, level), *logFormat); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+	return positional
+}
+
+func setLang(value string) {
+	if err := i18n.SetLang(value); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+}
+
+// localizeFlags 参数说明在注册时还不知道输出语言，解析前统一翻译
+func localizeFlags(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Usage = i18n.T(f.Usage)
+	})
+}
+
+// findArg -config 和 -lang 需要在解析其它参数之前生效，所以单独查找
+func findArg(args []string, flagName string) (string, bool) {
+	for i, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if name == flagName && i+1 < len(args) {
+			return args[i+1], true
+		}
+		if strings.HasPrefix(name, flagName+"=") {
+			return strings.TrimPrefix(name, flagName+"="), true
+		}
+	}
+	return "", false
+}
+
+func applyConfig(fs *flag.FlagSet, cfg config.Config, section string) {
+	for key, value := range cfg.Values(section) {
+		if key == "config" {
+			continue
+		}
+		if fs.Lookup(key) == nil {
+			// 全局项可能只对其它子命令有效，只提示子命令section中的未知项
+			if _, ok := cfg[section][key]; ok {
+				logger.Warn(i18n.Sprintf("配置文件 [%s] 中的 %s 不是有效的参数，已忽略", section, key))
+			}
+			continue
+		}
+		if err := fs.Set(key, value); err != nil {
+			logger.Error(i18n.Sprintf("配置项 %s 的值无效: %v", key, err))
+			os.Exit(2)
+		}
+	}
+}
+
+// moduleFlag 旧版本的 -xshell、-winscp 等布尔参数，设置后追加到 -modules
+type moduleFlag struct {
+	opts   *collectOptions
+	module string
+}
+
+func (f moduleFlag) String() string { return "false" }
+
+func (f moduleFlag) IsBoolFlag() bool { return true }
+
+func (f moduleFlag) Set(value string) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		f.opts.addModule(f.module)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package browers
 
 import (
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
 	"encoding/base64"
 	jsonpkg "encoding/json"
 	"fmt"
@@ -18,14 +20,14 @@ func History(chromePath, browserName string) (string, error) {
 
 	historyTempFile, err := CreateTmpFile(chromePath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", chromePath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", chromePath), 1)
 		return "", err
 	}
 	defer RemoveFile(historyTempFile)
 
 	sqlDatabase, err := NewSQLiteHandler(historyTempFile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("解析SQLite文件失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("解析SQLite文件失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
@@ -81,14 +83,14 @@ func Download(chromePath, browserName string) (string, error) {
 
 	downloadTempFile, err := CreateTmpFile(chromePath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", chromePath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", chromePath), 1)
 		return "", err
 	}
 	defer RemoveFile(downloadTempFile)
 
 	sqlDatabase, err := NewSQLiteHandler(downloadTempFile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("解析SQLite文件失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("解析SQLite文件失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
@@ -141,7 +143,7 @@ func Cookies(chromeCookiePath, chromeStateFile, browserName string) (string, err
 	var resultBuilder strings.Builder
 	cookieDataTempFile, err := CreateTmpFile(chromeCookiePath)
 	if err != nil {
-		PrintFail("Not Found SystemKey OR Not Administrator Privileges!", 1)
+		PrintFail(i18n.T("Not Found SystemKey OR Not Administrator Privileges!"), 1)
 		return "", err
 	}
 	defer RemoveFile(cookieDataTempFile)
@@ -161,10 +163,10 @@ func Cookies(chromeCookiePath, chromeStateFile, browserName string) (string, err
 			if masterKeyErr == nil {
 				SystemKey = masterKey
 				systemKeyErr = nil
-				PrintVerbose("使用主密钥作为系统密钥")
+				PrintVerbose(i18n.T("使用主密钥作为系统密钥"))
 			} else {
 
-				PrintVerbose(fmt.Sprintf("获取系统密钥失败: %v，尝试其他解密方法", systemKeyErr))
+				PrintVerbose(i18n.Sprintf("获取系统密钥失败: %v，尝试其他解密方法", systemKeyErr))
 			}
 		}
 	}
@@ -177,7 +179,7 @@ func Cookies(chromeCookiePath, chromeStateFile, browserName string) (string, err
 
 	sqlDatabase, err := NewSQLiteHandler(cookieDataTempFile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("解析SQLite文件失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("解析SQLite文件失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
@@ -300,7 +302,7 @@ func Bookmark(chromeBookPath string) (string, error) {
 	var resultBuilder strings.Builder
 	tempFile, err := CreateTmpFile(chromeBookPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", chromeBookPath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", chromeBookPath), 1)
 		return "", err
 	}
 	defer RemoveFile(tempFile)
@@ -312,7 +314,7 @@ func Bookmark(chromeBookPath string) (string, error) {
 
 	var bookmarkMap map[string]interface{}
 	if err := jsonpkg.Unmarshal(bookmarkData, &bookmarkMap); err != nil {
-		PrintFail(fmt.Sprintf("Failed to parse bookmark data: %v", err), 1)
+		PrintFail(i18n.Sprintf("Failed to parse bookmark data: %v", err), 1)
 		return "", err
 	}
 
@@ -346,7 +348,7 @@ func Bookmark(chromeBookPath string) (string, error) {
 		}
 	}
 
-	resultBuilder.WriteString("Bookmark data extracted successfully\n")
+	resultBuilder.WriteString(i18n.T("Bookmark data extracted successfully\n"))
 	return resultBuilder.String(), nil
 }
 
@@ -389,14 +391,14 @@ func Logins(chromePath, chromeStateFile, browserName string) (string, error) {
 
 	loginTempFile, err := CreateTmpFile(chromePath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", chromePath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", chromePath), 1)
 		return "", err
 	}
 	defer RemoveFile(loginTempFile)
 
 	stateFileContent, err := ioutil.ReadFile(chromeStateFile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("读取状态文件失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("读取状态文件失败: %v", err), 1)
 		return "", err
 	}
 
@@ -410,17 +412,17 @@ func Logins(chromePath, chromeStateFile, browserName string) (string, error) {
 			if masterKeyErr == nil {
 				SystemKey = masterKey
 				systemKeyErr = nil
-				PrintVerbose("使用主密钥作为系统密钥")
+				PrintVerbose(i18n.T("使用主密钥作为系统密钥"))
 			} else {
 
-				PrintVerbose(fmt.Sprintf("获取系统密钥失败: %v，尝试其他解密方法", systemKeyErr))
+				PrintVerbose(i18n.Sprintf("获取系统密钥失败: %v，尝试其他解密方法", systemKeyErr))
 			}
 		}
 	}
 
 	sqlDatabase, err := NewSQLiteHandler(loginTempFile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("解析SQLite文件失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("解析SQLite文件失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
@@ -515,6 +517,7 @@ func Logins(chromePath, chromeStateFile, browserName string) (string, error) {
 			PrintSuccess(fmt.Sprintf("CreateDate: %s", TimeEpoch(creDate).String()), 1)
 
 			data = append(data, []string{url, username, password, TimeEpoch(creDate).String()})
+			credential.Add(credential.Credential{Source: browserName, Name: url, Host: url, User: username, Password: password})
 		}
 	}
 
@@ -580,16 +583,16 @@ func GetChromium(name []string) (string, error) {
 			if len(existingPaths) > 0 {
 				browserInfo := fmt.Sprintf("========================== %s (%s) ==========================\n", name[0], userName)
 				resultBuilder.WriteString(browserInfo)
-				fmt.Printf("========================== %s (%s) ==========================\n", name[0], userName)
+				PrintNormal(strings.TrimSpace(browserInfo))
 
 				if PathExists(userChromeLoginDataPath) && PathExists(userChromeStatePath) {
-					fmt.Printf("[+] Get %s Login Data", name[0])
+					PrintVerbose(i18n.Sprintf("Get %s Login Data", name[0]))
 					loginResult, _ := Logins(userChromeLoginDataPath, userChromeStatePath, name[0])
 					resultBuilder.WriteString(loginResult)
 				}
 
 				if PathExists(userChromeBookmarkPath) {
-					PrintVerbose(fmt.Sprintf("Get %s Bookmarks", name[0]))
+					PrintVerbose(i18n.Sprintf("Get %s Bookmarks", name[0]))
 					bookmarkResult, _ := Bookmark(userChromeBookmarkPath)
 					resultBuilder.WriteString(bookmarkResult)
 
@@ -601,11 +604,11 @@ func GetChromium(name []string) (string, error) {
 						if !PathExists(cookiePath) {
 							cookiePath = fmt.Sprintf("%s%s\\Network\\Cookies", dir, name[1])
 							if !PathExists(cookiePath) {
-								return fmt.Errorf("Cookie file not found")
+								return i18n.Errorf("Cookie file not found")
 							}
 						}
 
-						PrintVerbose(fmt.Sprintf("Get %s Cookie", name[0]))
+						PrintVerbose(i18n.Sprintf("Get %s Cookie", name[0]))
 						cookieResult, err := Cookies(cookiePath, userChromeStatePath, name[0])
 						if err == nil {
 							resultBuilder.WriteString(cookieResult)
@@ -614,19 +617,19 @@ func GetChromium(name []string) (string, error) {
 					}
 
 					if err := try(); err != nil {
-						PrintFail("Not Found SystemKey OR Not Administrator Privileges!", 1)
+						PrintFail(i18n.T("Not Found SystemKey OR Not Administrator Privileges!"), 1)
 
 					}
 				}
 
 				if PathExists(userChromeHistoryPath) {
-					PrintVerbose(fmt.Sprintf("Get %s History", name[0]))
+					PrintVerbose(i18n.Sprintf("Get %s History", name[0]))
 					historyResult, _ := History(userChromeHistoryPath, name[0])
 					resultBuilder.WriteString(historyResult)
 				}
 
 				if PathExists(userChromeHistoryPath) {
-					PrintVerbose(fmt.Sprintf("Get %s Downloads", name[0]))
+					PrintVerbose(i18n.Sprintf("Get %s Downloads", name[0]))
 					downloadResult, _ := Download(userChromeHistoryPath, name[0])
 					resultBuilder.WriteString(downloadResult)
 				}
@@ -651,18 +654,18 @@ func GetChromium(name []string) (string, error) {
 		existingPaths := FileExists(chromePaths)
 
 		if len(existingPaths) > 0 {
-			browserInfo := fmt.Sprintf("========================== %s (Current User) ==========================\n", name[0])
+			browserInfo := i18n.Sprintf("========================== %s (Current User) ==========================\n", name[0])
 			resultBuilder.WriteString(browserInfo)
-			fmt.Printf("========================== %s (Current User) ==========================\n", name[0])
+			PrintNormal(strings.TrimSpace(browserInfo))
 
 			if PathExists(userChromeLoginDataPath) && PathExists(userChromeStatePath) {
-				PrintVerbose(fmt.Sprintf("Get %s Login Data", name[0]))
+				PrintVerbose(i18n.Sprintf("Get %s Login Data", name[0]))
 				loginResult, _ := Logins(userChromeLoginDataPath, userChromeStatePath, name[0])
 				resultBuilder.WriteString(loginResult)
 			}
 
 			if PathExists(userChromeBookmarkPath) {
-				PrintVerbose(fmt.Sprintf("Get %s Bookmarks", name[0]))
+				PrintVerbose(i18n.Sprintf("Get %s Bookmarks", name[0]))
 				bookmarkResult, _ := Bookmark(userChromeBookmarkPath)
 				resultBuilder.WriteString(bookmarkResult)
 			}
@@ -673,11 +676,11 @@ func GetChromium(name []string) (string, error) {
 					if !PathExists(cookiePath) {
 						cookiePath = fmt.Sprintf("%s%s\\Network\\Cookies", os.Getenv("USERPROFILE"), name[1])
 						if !PathExists(cookiePath) {
-							return fmt.Errorf("Cookie file not found")
+							return i18n.Errorf("Cookie file not found")
 						}
 					}
 
-					PrintVerbose(fmt.Sprintf("Get %s Cookie", name[0]))
+					PrintVerbose(i18n.Sprintf("Get %s Cookie", name[0]))
 					cookieResult, err := Cookies(cookiePath, userChromeStatePath, name[0])
 					if err == nil {
 						resultBuilder.WriteString(cookieResult)
@@ -686,19 +689,19 @@ func GetChromium(name []string) (string, error) {
 				}
 
 				if err := try(); err != nil {
-					PrintFail("Not Found SystemKey OR Not Administrator Privileges!", 1)
+					PrintFail(i18n.T("Not Found SystemKey OR Not Administrator Privileges!"), 1)
 
 				}
 			}
 
 			if PathExists(userChromeHistoryPath) {
-				PrintVerbose(fmt.Sprintf("Get %s History", name[0]))
+				PrintVerbose(i18n.Sprintf("Get %s History", name[0]))
 				historyResult, _ := History(userChromeHistoryPath, name[0])
 				resultBuilder.WriteString(historyResult)
 			}
 
 			if PathExists(userChromeHistoryPath) {
-				PrintVerbose(fmt.Sprintf("Get %s Downloads", name[0]))
+				PrintVerbose(i18n.Sprintf("Get %s Downloads", name[0]))
 				downloadResult, _ := Download(userChromeHistoryPath, name[0])
 				resultBuilder.WriteString(downloadResult)
 			}
@@ -709,28 +712,30 @@ func GetChromium(name []string) (string, error) {
 	return resultBuilder.String(), nil
 }
 
+// chromiumBrowsers 浏览器名称以及相对于用户目录的默认Profile路径
+var chromiumBrowsers = [][]string{
+	{"Chrome", "\\AppData\\Local\\Google\\Chrome\\User Data\\Default"},
+	{"Chrome Beta", "\\AppData\\Local\\Google\\Chrome Beta\\User Data\\Default"},
+	{"Chromium", "\\AppData\\Local\\Chromium\\User Data\\Default"},
+	{"Edge", "\\AppData\\Local\\Microsoft\\Edge\\User Data\\Default"},
+	{"360 Speed", "\\AppData\\Local\\360chrome\\Chrome\\User Data\\Default"},
+	{"360 Speed X", "\\AppData\\Local\\360ChromeX\\Chrome\\User Data\\Default"},
+	{"Brave", "\\AppData\\Local\\BraveSoftware\\Brave-Browser\\User Data\\Default"},
+	{"QQ", "\\AppData\\Local\\Tencent\\QQBrowser\\User Data\\Default"},
+	{"Opera", "\\AppData\\Roaming\\Opera Software\\Opera Stable"},
+	{"OperaGX", "\\AppData\\Roaming\\Opera Software\\Opera GX Stable"},
+	{"Vivaldi", "\\AppData\\Local\\Vivaldi\\User Data\\Default"},
+	{"CocCoc", "\\AppData\\Local\\CocCoc\\Browser\\User Data\\Default"},
+	{"Yandex", "\\AppData\\Local\\Yandex\\YandexBrowser\\User Data\\Default"},
+	{"DCBrowser", "\\AppData\\Local\\DCBrowser\\User Data\\Default"},
+	{"Old Sogou", "\\AppData\\Roaming\\SogouExplorer\\Webkit\\Default"},
+	{"New Sogou", "\\AppData\\Local\\Sogou\\SogouExplorer\\User Data\\Default"},
+}
+
 func ChromiumKernel() string {
 	var resultBuilder strings.Builder
-	browsers := [][]string{
-		{"Chrome", "\\AppData\\Local\\Google\\Chrome\\User Data\\Default"},
-		{"Chrome Beta", "\\AppData\\Local\\Google\\Chrome Beta\\User Data\\Default"},
-		{"Chromium", "\\AppData\\Local\\Chromium\\User Data\\Default"},
-		{"Edge", "\\AppData\\Local\\Microsoft\\Edge\\User Data\\Default"},
-		{"360 Speed", "\\AppData\\Local\\360chrome\\Chrome\\User Data\\Default"},
-		{"360 Speed X", "\\AppData\\Local\\360ChromeX\\Chrome\\User Data\\Default"},
-		{"Brave", "\\AppData\\Local\\BraveSoftware\\Brave-Browser\\User Data\\Default"},
-		{"QQ", "\\AppData\\Local\\Tencent\\QQBrowser\\User Data\\Default"},
-		{"Opera", "\\AppData\\Roaming\\Opera Software\\Opera Stable"},
-		{"OperaGX", "\\AppData\\Roaming\\Opera Software\\Opera GX Stable"},
-		{"Vivaldi", "\\AppData\\Local\\Vivaldi\\User Data\\Default"},
-		{"CocCoc", "\\AppData\\Local\\CocCoc\\Browser\\User Data\\Default"},
-		{"Yandex", "\\AppData\\Local\\Yandex\\YandexBrowser\\User Data\\Default"},
-		{"DCBrowser", "\\AppData\\Local\\DCBrowser\\User Data\\Default"},
-		{"Old Sogou", "\\AppData\\Roaming\\SogouExplorer\\Webkit\\Default"},
-		{"New Sogou", "\\AppData\\Local\\Sogou\\SogouExplorer\\User Data\\Default"},
-	}
-
-	for _, browser := range browsers {
+
+	for _, browser := range chromiumBrowsers {
 		result, _ := GetChromium(browser)
 		resultBuilder.WriteString(result)
 	}
@@ -759,18 +764,18 @@ func SpecifyPath(browserName, path string) (string, error) {
 	existingPaths := FileExists(chromePaths)
 
 	if len(existingPaths) > 0 {
-		browserInfo := fmt.Sprintf("========================== %s (指定路径) ==========================\n", browserName)
+		browserInfo := i18n.Sprintf("========================== %s (指定路径) ==========================\n", browserName)
 		resultBuilder.WriteString(browserInfo)
-		fmt.Printf("========================== %s (指定路径) ==========================\n", browserName)
+		PrintNormal(strings.TrimSpace(browserInfo))
 
 		if PathExists(userChromeLoginDataPath) && PathExists(userChromeStatePath) {
-			PrintVerbose(fmt.Sprintf("Get %s Login Data", browserName))
+			PrintVerbose(i18n.Sprintf("Get %s Login Data", browserName))
 			loginResult, _ := Logins(userChromeLoginDataPath, userChromeStatePath, browserName)
 			resultBuilder.WriteString(loginResult)
 		}
 
 		if PathExists(userChromeBookmarkPath) {
-			PrintVerbose(fmt.Sprintf("Get %s Bookmarks", browserName))
+			PrintVerbose(i18n.Sprintf("Get %s Bookmarks", browserName))
 			bookmarkResult, _ := Bookmark(userChromeBookmarkPath)
 			resultBuilder.WriteString(bookmarkResult)
 		}
@@ -781,11 +786,11 @@ func SpecifyPath(browserName, path string) (string, error) {
 				if !PathExists(cookiePath) {
 					cookiePath = fmt.Sprintf("%s\\Network\\Cookies", path)
 					if !PathExists(cookiePath) {
-						return fmt.Errorf("Cookie file not found")
+						return i18n.Errorf("Cookie file not found")
 					}
 				}
 
-				PrintVerbose(fmt.Sprintf("Get %s Cookie", browserName))
+				PrintVerbose(i18n.Sprintf("Get %s Cookie", browserName))
 				cookieResult, err := Cookies(cookiePath, userChromeStatePath, browserName)
 				if err == nil {
 					resultBuilder.WriteString(cookieResult)
@@ -794,25 +799,25 @@ func SpecifyPath(browserName, path string) (string, error) {
 			}
 
 			if err := try(); err != nil {
-				PrintFail("Not Found SystemKey OR Not Administrator Privileges!", 1)
+				PrintFail(i18n.T("Not Found SystemKey OR Not Administrator Privileges!"), 1)
 
 			}
 		}
 
 		if PathExists(userChromeHistoryPath) {
-			PrintVerbose(fmt.Sprintf("Get %s History", browserName))
+			PrintVerbose(i18n.Sprintf("Get %s History", browserName))
 			historyResult, _ := History(userChromeHistoryPath, browserName)
 			resultBuilder.WriteString(historyResult)
 		}
 
 		if PathExists(userChromeHistoryPath) {
-			PrintVerbose(fmt.Sprintf("Get %s Downloads", browserName))
+			PrintVerbose(i18n.Sprintf("Get %s Downloads", browserName))
 			downloadResult, _ := Download(userChromeHistoryPath, browserName)
 			resultBuilder.WriteString(downloadResult)
 		}
 
 	} else {
-		return "", fmt.Errorf("指定路径 %s 下未找到有效的浏览器数据文件", path)
+		return "", i18n.Errorf("指定路径 %s 下未找到有效的浏览器数据文件", path)
 	}
 
 	return resultBuilder.String(), nil
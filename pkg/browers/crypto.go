@@ -8,6 +8,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha1"
 	"crypto/sha256"
+	"e0e1-config/pkg/i18n"
 	"encoding/asn1"
 	"encoding/base64"
 	"errors"
@@ -17,8 +18,6 @@ import (
 	"os"
 	"regexp"
 	"strings"
-	"syscall"
-	"unsafe"
 )
 
 type AesGcm struct{}
@@ -56,7 +55,7 @@ func (a *AesGcm) Decrypt(key, iv, aad, cipherText, authTag []byte) ([]byte, erro
 
 func GetMasterKey(filePath string) ([]byte, error) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, errors.New("file does not exist")
+		return nil, i18n.New("file does not exist")
 	}
 
 	fileContent, err := ioutil.ReadFile(filePath)
@@ -153,7 +152,7 @@ func DecryptWithSystemDPAPI(stateFilePath string) ([]byte, error) {
 		}
 	}
 
-	return nil, errors.New("无法从状态文件中获取加密密钥")
+	return nil, i18n.New("无法从状态文件中获取加密密钥")
 }
 
 func DecryptWithUserDPAPI(systemKey []byte, stateFilePath string) ([]byte, error) {
@@ -279,63 +278,6 @@ func DecryptData(encryptedData []byte, masterKey []byte) (string, error) {
 	}
 }
 
-func decryptDPAPI(encryptedData []byte) ([]byte, error) {
-	return decryptDPAPIWithFlags(encryptedData, 0)
-}
-
-func decryptDPAPIWithFlags(encryptedData []byte, flags uint32) ([]byte, error) {
-	var outBlob dataBlob
-	var inBlob dataBlob
-
-	inBlob.cbData = uint32(len(encryptedData))
-	if len(encryptedData) == 0 {
-		return nil, errors.New("empty encrypted data")
-	}
-
-	inBlob.pbData = uintptr(unsafe.Pointer(&encryptedData[0]))
-
-	procDecryptData.Call(
-		uintptr(unsafe.Pointer(&inBlob)),
-		0,
-		0,
-		0,
-		0,
-		uintptr(flags),
-		uintptr(unsafe.Pointer(&outBlob)),
-	)
-
-	if outBlob.cbData == 0 {
-		return nil, errors.New("decryption failed")
-	}
-
-	decryptedData := make([]byte, outBlob.cbData)
-	copyMemory(decryptedData, outBlob.pbData, outBlob.cbData)
-
-	localFree.Call(outBlob.pbData)
-
-	return decryptedData, nil
-}
-
-type dataBlob struct {
-	cbData uint32
-	pbData uintptr
-}
-
-var (
-	dllCrypt32  = syscall.NewLazyDLL("Crypt32.dll")
-	dllKernel32 = syscall.NewLazyDLL("Kernel32.dll")
-
-	procDecryptData = dllCrypt32.NewProc("CryptUnprotectData")
-	procEncryptData = dllCrypt32.NewProc("CryptProtectData")
-	localFree       = dllKernel32.NewProc("LocalFree")
-)
-
-func copyMemory(dest []byte, src uintptr, length uint32) {
-	for i := uint32(0); i < length; i++ {
-		dest[i] = *(*byte)(unsafe.Pointer(src + uintptr(i)))
-	}
-}
-
 var ErrCiphertextLengthIsInvalid = errors.New("ciphertext length is invalid")
 
 func AES128CBCDecrypt(key, iv, ciphertext []byte) ([]byte, error) {
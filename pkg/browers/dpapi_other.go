@@ -0,0 +1,15 @@
+//go:build !windows
+
+package browers
+
+import "e0e1-config/pkg/i18n"
+
+var errDPAPIUnsupported = i18n.New("DPAPI is only available on Windows")
+
+func decryptDPAPI(encryptedData []byte) ([]byte, error) {
+	return nil, errDPAPIUnsupported
+}
+
+func decryptDPAPIWithFlags(encryptedData []byte, flags uint32) ([]byte, error) {
+	return nil, errDPAPIUnsupported
+}
@@ -0,0 +1,64 @@
+package browers
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+func decryptDPAPI(encryptedData []byte) ([]byte, error) {
+	return decryptDPAPIWithFlags(encryptedData, 0)
+}
+
+func decryptDPAPIWithFlags(encryptedData []byte, flags uint32) ([]byte, error) {
+	var outBlob dataBlob
+	var inBlob dataBlob
+
+	inBlob.cbData = uint32(len(encryptedData))
+	if len(encryptedData) == 0 {
+		return nil, errors.New("empty encrypted data")
+	}
+
+	inBlob.pbData = uintptr(unsafe.Pointer(&encryptedData[0]))
+
+	procDecryptData.Call(
+		uintptr(unsafe.Pointer(&inBlob)),
+		0,
+		0,
+		0,
+		0,
+		uintptr(flags),
+		uintptr(unsafe.Pointer(&outBlob)),
+	)
+
+	if outBlob.cbData == 0 {
+		return nil, errors.New("decryption failed")
+	}
+
+	decryptedData := make([]byte, outBlob.cbData)
+	copyMemory(decryptedData, outBlob.pbData, outBlob.cbData)
+
+	localFree.Call(outBlob.pbData)
+
+	return decryptedData, nil
+}
+
+type dataBlob struct {
+	cbData uint32
+	pbData uintptr
+}
+
+var (
+	dllCrypt32  = syscall.NewLazyDLL("Crypt32.dll")
+	dllKernel32 = syscall.NewLazyDLL("Kernel32.dll")
+
+	procDecryptData = dllCrypt32.NewProc("CryptUnprotectData")
+	procEncryptData = dllCrypt32.NewProc("CryptProtectData")
+	localFree       = dllKernel32.NewProc("LocalFree")
+)
+
+func copyMemory(dest []byte, src uintptr, length uint32) {
+	for i := uint32(0); i < length; i++ {
+		dest[i] = *(*byte)(unsafe.Pointer(src + uintptr(i)))
+	}
+}
@@ -3,6 +3,8 @@ package browers
 import (
 	"bytes"
 	"database/sql"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
 	"encoding/base64"
 	jsonpkg "encoding/json"
 	"errors"
@@ -12,12 +14,22 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
-	ErrProfilePathNotFound = errors.New("profile path not found")
+	ErrProfilePathNotFound  = i18n.New("profile path not found")
+	ErrPrimaryPasswordSet   = i18n.New("primary password is set, supply it with -firefox-password")
+	ErrPrimaryPasswordWrong = i18n.New("primary password is incorrect")
+	ErrKey4DBCorrupt        = errors.New("key4.db is corrupt or unsupported")
 )
 
+var FirefoxPassword string
+
+func SetFirefoxPassword(password string) {
+	FirefoxPassword = password
+}
+
 type FirefoxProfile struct {
 	name        string
 	profilePath string
@@ -58,42 +70,10 @@ func GetFirefox() (string, error) {
 
 			browserInfo := fmt.Sprintf("========================== %s (%s) ==========================\n", name[0], userName)
 			resultBuilder.WriteString(browserInfo)
-			fmt.Printf("========================== %s (%s) ==========================\n", name[0], userName)
+			PrintNormal(strings.TrimSpace(browserInfo))
 
 			for _, profile := range profiles {
-				profileInfo := fmt.Sprintf("Profile: %s\n", profile.name)
-				resultBuilder.WriteString(profileInfo)
-				PrintSuccess(fmt.Sprintf("Profile: %s", profile.name), 1)
-
-				if PathExists(profile.itemPaths["logins.json"]) && PathExists(profile.itemPaths["key4.db"]) {
-					PrintVerbose(fmt.Sprintf("Get %s Login Data", name[0]))
-					loginResult, _ := FirefoxLogins(profile, name[0])
-					resultBuilder.WriteString(loginResult)
-				}
-
-				if PathExists(profile.itemPaths["places.sqlite"]) {
-					PrintVerbose(fmt.Sprintf("Get %s Bookmarks", name[0]))
-					bookmarkResult, _ := FirefoxBookmarks(profile, name[0])
-					resultBuilder.WriteString(bookmarkResult)
-				}
-
-				if PathExists(profile.itemPaths["cookies.sqlite"]) && PathExists(profile.itemPaths["key4.db"]) {
-					PrintVerbose(fmt.Sprintf("Get %s Cookie", name[0]))
-					cookieResult, _ := FirefoxCookies(profile, name[0])
-					resultBuilder.WriteString(cookieResult)
-				}
-
-				if PathExists(profile.itemPaths["places.sqlite"]) {
-					PrintVerbose(fmt.Sprintf("Get %s History", name[0]))
-					historyResult, _ := FirefoxHistory(profile, name[0])
-					resultBuilder.WriteString(historyResult)
-				}
-
-				if PathExists(profile.itemPaths["places.sqlite"]) {
-					PrintVerbose(fmt.Sprintf("Get %s Downloads", name[0]))
-					downloadResult, _ := FirefoxDownloads(profile, name[0])
-					resultBuilder.WriteString(downloadResult)
-				}
+				resultBuilder.WriteString(scanFirefoxProfile(profile, name[0]))
 			}
 		}
 	} else {
@@ -104,50 +84,140 @@ func GetFirefox() (string, error) {
 			return "", err
 		}
 
-		browserInfo := fmt.Sprintf("========================== %s (Current User) ==========================\n", name[0])
+		browserInfo := i18n.Sprintf("========================== %s (Current User) ==========================\n", name[0])
 		resultBuilder.WriteString(browserInfo)
-		fmt.Printf("========================== %s (Current User) ==========================\n", name[0])
+		PrintNormal(strings.TrimSpace(browserInfo))
 
 		for _, profile := range profiles {
-			profileInfo := fmt.Sprintf("Profile: %s\n", profile.name)
-			resultBuilder.WriteString(profileInfo)
-			PrintSuccess(fmt.Sprintf("Profile: %s", profile.name), 1)
-
-			if PathExists(profile.itemPaths["logins.json"]) && PathExists(profile.itemPaths["key4.db"]) {
-				PrintVerbose(fmt.Sprintf("Get %s Login Data", name[0]))
-				loginResult, _ := FirefoxLogins(profile, name[0])
-				resultBuilder.WriteString(loginResult)
-			}
+			resultBuilder.WriteString(scanFirefoxProfile(profile, name[0]))
+		}
+	}
 
-			if PathExists(profile.itemPaths["places.sqlite"]) {
-				PrintVerbose(fmt.Sprintf("Get %s Bookmarks", name[0]))
-				bookmarkResult, _ := FirefoxBookmarks(profile, name[0])
-				resultBuilder.WriteString(bookmarkResult)
-			}
+	return resultBuilder.String(), nil
+}
 
-			if PathExists(profile.itemPaths["cookies.sqlite"]) && PathExists(profile.itemPaths["key4.db"]) {
-				PrintVerbose(fmt.Sprintf("Get %s Cookie", name[0]))
-				cookieResult, _ := FirefoxCookies(profile, name[0])
-				resultBuilder.WriteString(cookieResult)
-			}
+// GetFirefoxFromProfile 解析拷贝出来的Firefox配置目录，不依赖本机的用户目录和DPAPI，
+// profileDir 既可以是单个profile目录，也可以是包含多个profile的Profiles目录
+func GetFirefoxFromProfile(profileDir string) (string, error) {
+	var resultBuilder strings.Builder
+	var name = []string{"Firefox", ""}
+	BrowserName = name[0]
+	if Format == "csv" || Format == "json" {
+		PrintOut = false
+	}
 
-			if PathExists(profile.itemPaths["places.sqlite"]) {
-				PrintVerbose(fmt.Sprintf("Get %s History", name[0]))
-				historyResult, _ := FirefoxHistory(profile, name[0])
-				resultBuilder.WriteString(historyResult)
-			}
+	if !PathExists(profileDir) {
+		return "", ErrProfilePathNotFound
+	}
 
-			if PathExists(profile.itemPaths["places.sqlite"]) {
-				PrintVerbose(fmt.Sprintf("Get %s Downloads", name[0]))
-				downloadResult, _ := FirefoxDownloads(profile, name[0])
-				resultBuilder.WriteString(downloadResult)
-			}
+	var profiles []FirefoxProfile
+	if isFirefoxProfileDir(profileDir) {
+		profiles = []FirefoxProfile{newFirefoxProfile(filepath.Base(profileDir), profileDir)}
+	} else {
+		var err error
+		profiles, err = getFirefoxProfiles(profileDir)
+		if err != nil {
+			return "", err
 		}
 	}
 
+	if len(profiles) == 0 {
+		return "", i18n.Errorf("%s 中未找到Firefox配置文件", profileDir)
+	}
+
+	browserInfo := fmt.Sprintf("========================== %s (%s) ==========================\n", name[0], profileDir)
+	resultBuilder.WriteString(browserInfo)
+	PrintNormal(strings.TrimSpace(browserInfo))
+
+	for _, profile := range profiles {
+		resultBuilder.WriteString(scanFirefoxProfile(profile, name[0]))
+	}
+
 	return resultBuilder.String(), nil
 }
 
+func scanFirefoxProfile(profile FirefoxProfile, browserName string) string {
+	var resultBuilder strings.Builder
+
+	profileInfo := fmt.Sprintf("Profile: %s\n", profile.name)
+	resultBuilder.WriteString(profileInfo)
+	PrintSuccess(fmt.Sprintf("Profile: %s", profile.name), 1)
+
+	if PathExists(profile.itemPaths["logins.json"]) && PathExists(profile.itemPaths["key4.db"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Login Data", browserName))
+		loginResult, err := FirefoxLogins(profile, browserName)
+		if err != nil {
+			resultBuilder.WriteString(i18n.Sprintf("获取登录数据失败: %v\n", err))
+		}
+		resultBuilder.WriteString(loginResult)
+	}
+
+	if PathExists(profile.itemPaths["places.sqlite"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Bookmarks", browserName))
+		bookmarkResult, _ := FirefoxBookmarks(profile, browserName)
+		resultBuilder.WriteString(bookmarkResult)
+	}
+
+	if PathExists(profile.itemPaths["cookies.sqlite"]) && PathExists(profile.itemPaths["key4.db"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Cookie", browserName))
+		cookieResult, _ := FirefoxCookies(profile, browserName)
+		resultBuilder.WriteString(cookieResult)
+	}
+
+	if PathExists(profile.itemPaths["places.sqlite"]) {
+		PrintVerbose(i18n.Sprintf("Get %s History", browserName))
+		historyResult, _ := FirefoxHistory(profile, browserName)
+		resultBuilder.WriteString(historyResult)
+	}
+
+	if PathExists(profile.itemPaths["places.sqlite"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Downloads", browserName))
+		downloadResult, _ := FirefoxDownloads(profile, browserName)
+		resultBuilder.WriteString(downloadResult)
+	}
+
+	if PathExists(profile.itemPaths["sessionstore.jsonlz4"]) || PathExists(profile.itemPaths["recovery.jsonlz4"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Sessions", browserName))
+		sessionResult, _ := FirefoxSessions(profile, browserName)
+		resultBuilder.WriteString(sessionResult)
+	}
+
+	if PathExists(profile.itemPaths["formhistory.sqlite"]) {
+		PrintVerbose(i18n.Sprintf("Get %s Form History", browserName))
+		formResult, _ := FirefoxFormHistory(profile, browserName)
+		resultBuilder.WriteString(formResult)
+	}
+
+	return resultBuilder.String()
+}
+
+func isFirefoxProfileDir(path string) bool {
+	for _, item := range []string{"key4.db", "logins.json", "places.sqlite", "cookies.sqlite", "sessionstore.jsonlz4", "formhistory.sqlite"} {
+		if PathExists(filepath.Join(path, item)) {
+			return true
+		}
+	}
+	return false
+}
+
+func newFirefoxProfile(name, path string) FirefoxProfile {
+	profile := FirefoxProfile{
+		name:        name,
+		profilePath: path,
+		itemPaths:   make(map[string]string),
+	}
+
+	profile.itemPaths["key4.db"] = filepath.Join(path, "key4.db")
+	profile.itemPaths["logins.json"] = filepath.Join(path, "logins.json")
+	profile.itemPaths["cookies.sqlite"] = filepath.Join(path, "cookies.sqlite")
+	profile.itemPaths["places.sqlite"] = filepath.Join(path, "places.sqlite")
+	profile.itemPaths["formhistory.sqlite"] = filepath.Join(path, "formhistory.sqlite")
+	profile.itemPaths["sessionstore.jsonlz4"] = filepath.Join(path, "sessionstore.jsonlz4")
+	profile.itemPaths["recovery.jsonlz4"] = filepath.Join(path, "sessionstore-backups", "recovery.jsonlz4")
+
+	return profile
+}
+
 func getFirefoxProfiles(profilesPath string) ([]FirefoxProfile, error) {
 	if !PathExists(profilesPath) {
 		return nil, ErrProfilePathNotFound
@@ -161,19 +231,7 @@ func getFirefoxProfiles(profilesPath string) ([]FirefoxProfile, error) {
 		}
 
 		if info.IsDir() && strings.Contains(info.Name(), ".default") {
-
-			profile := FirefoxProfile{
-				name:        info.Name(),
-				profilePath: path,
-				itemPaths:   make(map[string]string),
-			}
-
-			profile.itemPaths["key4.db"] = filepath.Join(path, "key4.db")
-			profile.itemPaths["logins.json"] = filepath.Join(path, "logins.json")
-			profile.itemPaths["cookies.sqlite"] = filepath.Join(path, "cookies.sqlite")
-			profile.itemPaths["places.sqlite"] = filepath.Join(path, "places.sqlite")
-
-			profiles = append(profiles, profile)
+			profiles = append(profiles, newFirefoxProfile(info.Name(), path))
 		}
 
 		return nil
@@ -190,21 +248,21 @@ func GetFirefoxMasterKey(profile FirefoxProfile) ([]byte, error) {
 	keyDbPath := profile.itemPaths["key4.db"]
 	tempFilename, err := CreateTmpFile(keyDbPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", keyDbPath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", keyDbPath), 1)
 		return []byte(""), err
 	}
 	defer RemoveFile(tempFilename)
 
 	sqlDatabase, err := NewSQLiteHandler(tempFilename)
 	if err != nil {
-		return nil, fmt.Errorf("open key4.db error: %w", err)
+		return nil, fmt.Errorf("%w: open key4.db error: %v", ErrKey4DBCorrupt, err)
 	}
 	defer sqlDatabase.Close()
 
 	var metaItem1, metaItem2 []byte
 
 	if !sqlDatabase.ReadTable("metaData") {
-		return nil, fmt.Errorf("read metaData table error")
+		return nil, fmt.Errorf("%w: read metaData table error", ErrKey4DBCorrupt)
 	}
 
 	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
@@ -216,82 +274,104 @@ func GetFirefoxMasterKey(profile FirefoxProfile) ([]byte, error) {
 			var err error
 			metaItem1, err = base64.StdEncoding.DecodeString(item1Base64)
 			if err != nil {
-				return nil, fmt.Errorf("decode item1 error: %w", err)
+				return nil, fmt.Errorf("%w: decode item1 error: %v", ErrKey4DBCorrupt, err)
 			}
 
 			metaItem2, err = base64.StdEncoding.DecodeString(item2Base64)
 			if err != nil {
-				return nil, fmt.Errorf("decode item2 error: %w", err)
+				return nil, fmt.Errorf("%w: decode item2 error: %v", ErrKey4DBCorrupt, err)
 			}
 			break
 		}
 	}
 
 	if metaItem1 == nil || metaItem2 == nil {
-		return nil, fmt.Errorf("password record not found in metaData")
+		return nil, fmt.Errorf("%w: password record not found in metaData", ErrKey4DBCorrupt)
 	}
 
 	var nssA11, nssA102 []byte
 
 	if !sqlDatabase.ReadTable("nssPrivate") {
-		return nil, fmt.Errorf("read nssPrivate table error")
+		return nil, fmt.Errorf("%w: read nssPrivate table error", ErrKey4DBCorrupt)
 	}
 
-	if sqlDatabase.GetRowCount() > 0 {
-		a11Base64 := sqlDatabase.GetValue(0, "a11")
-		a102Base64 := sqlDatabase.GetValue(0, "a102")
+	if sqlDatabase.GetRowCount() == 0 {
+		return nil, fmt.Errorf("%w: no records in nssPrivate table", ErrKey4DBCorrupt)
+	}
 
-		var err error
-		nssA11, err = base64.StdEncoding.DecodeString(a11Base64)
+	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
+		a11Base64 := sqlDatabase.GetValue(i, "a11")
+		a102Base64 := sqlDatabase.GetValue(i, "a102")
+
+		a11, err := base64.StdEncoding.DecodeString(a11Base64)
 		if err != nil {
-			return nil, fmt.Errorf("decode a11 error: %w", err)
+			continue
 		}
 
-		nssA102, err = base64.StdEncoding.DecodeString(a102Base64)
+		a102, err := base64.StdEncoding.DecodeString(a102Base64)
 		if err != nil {
-			return nil, fmt.Errorf("decode a102 error: %w", err)
+			continue
+		}
+
+		nssA11, nssA102 = a11, a102
+		if bytes.Equal(a102, firefoxKeyID) {
+			break
 		}
-	} else {
-		return nil, fmt.Errorf("no records in nssPrivate table")
 	}
 
-	return processFirefoxMasterKey(metaItem1, metaItem2, nssA11, nssA102)
+	if nssA11 == nil {
+		return nil, fmt.Errorf("%w: decode nssPrivate a11/a102 error", ErrKey4DBCorrupt)
+	}
+
+	return processFirefoxMasterKey(metaItem1, metaItem2, nssA11, nssA102, FirefoxPassword)
 }
 
-func processFirefoxMasterKey(metaItem1, metaItem2, nssA11, nssA102 []byte) ([]byte, error) {
+// firefoxKeyID 是key4.db中3DES登录密钥对应的CKA_ID
+var firefoxKeyID = []byte{248, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+
+// firefoxPasswordSalt NSS在派生密钥时使用 globalSalt + 主密码，
+// PBKDF2-SHA256(metaPBE) 和旧版 SHA1-3DES(nssPBE) 两种路径都是如此
+func firefoxPasswordSalt(globalSalt []byte, password string) []byte {
+	salt := make([]byte, 0, len(globalSalt)+len(password))
+	salt = append(salt, globalSalt...)
+	return append(salt, password...)
+}
+
+func processFirefoxMasterKey(metaItem1, metaItem2, nssA11, nssA102 []byte, password string) ([]byte, error) {
 
 	metaPBE, err := NewASN1PBE(metaItem2)
 	if err != nil {
-		return nil, fmt.Errorf("error creating ASN1PBE from metaItem2: %w", err)
+		return nil, fmt.Errorf("%w: error creating ASN1PBE from metaItem2: %v", ErrKey4DBCorrupt, err)
 	}
 
-	flag, err := metaPBE.Decrypt(metaItem1)
-	if err != nil {
-		return nil, fmt.Errorf("error decrypting master key: %w", err)
-	}
+	passwordSalt := firefoxPasswordSalt(metaItem1, password)
+
+	flag, err := metaPBE.Decrypt(passwordSalt)
 
 	const passwordCheck = "password-check"
-	if !bytes.Contains(flag, []byte(passwordCheck)) {
-		return nil, errors.New("flag verification failed: password-check not found")
+	if err != nil || !bytes.Contains(flag, []byte(passwordCheck)) {
+		if password == "" {
+			return nil, ErrPrimaryPasswordSet
+		}
+		return nil, ErrPrimaryPasswordWrong
 	}
 
-	keyLin := []byte{248, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
-	if !bytes.Equal(nssA102, keyLin) {
-		return nil, errors.New("master key verification failed: nssA102 not equal to expected value")
+	if !bytes.Equal(nssA102, firefoxKeyID) {
+		return nil, fmt.Errorf("%w: master key verification failed: nssA102 not equal to expected value", ErrKey4DBCorrupt)
 	}
 
 	nssA11PBE, err := NewASN1PBE(nssA11)
 	if err != nil {
-		return nil, fmt.Errorf("error creating ASN1PBE from nssA11: %w", err)
+		return nil, fmt.Errorf("%w: error creating ASN1PBE from nssA11: %v", ErrKey4DBCorrupt, err)
 	}
 
-	finallyKey, err := nssA11PBE.Decrypt(metaItem1)
+	finallyKey, err := nssA11PBE.Decrypt(passwordSalt)
 	if err != nil {
-		return nil, fmt.Errorf("error decrypting final key: %w", err)
+		return nil, fmt.Errorf("%w: error decrypting final key: %v", ErrKey4DBCorrupt, err)
 	}
 
 	if len(finallyKey) < 24 {
-		return nil, errors.New("length of final key is less than 24 bytes")
+		return nil, fmt.Errorf("%w: length of final key is less than 24 bytes", ErrKey4DBCorrupt)
 	}
 
 	return finallyKey[:24], nil
@@ -304,20 +384,20 @@ func FirefoxLogins(profile FirefoxProfile, browserName string) (string, error) {
 
 	masterKey, err := GetFirefoxMasterKey(profile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("获取主密钥失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("获取主密钥失败: %v", err), 1)
 		return "", err
 	}
 
 	loginsPath := profile.itemPaths["logins.json"]
 	loginsData, err := ioutil.ReadFile(loginsPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("读取登录数据失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("读取登录数据失败: %v", err), 1)
 		return "", err
 	}
 
 	var loginsJSON map[string]interface{}
 	if err := jsonpkg.Unmarshal(loginsData, &loginsJSON); err != nil {
-		PrintFail(fmt.Sprintf("解析登录数据失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("解析登录数据失败: %v", err), 1)
 		return "", err
 	}
 
@@ -366,6 +446,7 @@ func FirefoxLogins(profile FirefoxProfile, browserName string) (string, error) {
 			PrintSuccess(fmt.Sprintf("CreateDate: %s", timeCreatedStr), 1)
 
 			data = append(data, []string{hostname, decryptedUsername, decryptedPassword, timeCreatedStr})
+			credential.Add(credential.Credential{Source: browserName, Name: hostname, Host: hostname, User: decryptedUsername, Password: decryptedPassword})
 		}
 	}
 
@@ -408,20 +489,20 @@ func FirefoxCookies(profile FirefoxProfile, browserName string) (string, error)
 	cookiePath := profile.itemPaths["cookies.sqlite"]
 	tempFilename, err := CreateTmpFile(cookiePath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", cookiePath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", cookiePath), 1)
 		return "", err
 	}
 	defer RemoveFile(tempFilename)
 
 	masterKey, err := GetFirefoxMasterKey(profile)
 	if err != nil {
-		PrintFail(fmt.Sprintf("获取主密钥失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("获取主密钥失败: %v", err), 1)
 		return "", err
 	}
 
 	sqlDatabase, err := NewSQLiteHandler(tempFilename)
 	if err != nil {
-		PrintFail(fmt.Sprintf("打开 Cookie 数据库失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("打开 Cookie 数据库失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
@@ -433,8 +514,8 @@ func FirefoxCookies(profile FirefoxProfile, browserName string) (string, error)
 	data := [][]string{}
 
 	if !sqlDatabase.ReadTable("moz_cookies") {
-		PrintFail("没有找到 Cookie 数据", 1)
-		return "", fmt.Errorf("no cookie data found")
+		PrintFail(i18n.T("没有找到 Cookie 数据"), 1)
+		return "", i18n.Errorf("no cookie data found")
 	}
 
 	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
@@ -533,21 +614,21 @@ func FirefoxHistory(profile FirefoxProfile, browserName string) (string, error)
 	placesPath := profile.itemPaths["places.sqlite"]
 	tempFilename, err := CreateTmpFile(placesPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", placesPath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", placesPath), 1)
 		return "", err
 	}
 	defer RemoveFile(tempFilename)
 
 	sqlDatabase, err := NewSQLiteHandler(tempFilename)
 	if err != nil {
-		PrintFail(fmt.Sprintf("打开历史记录数据库失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("打开历史记录数据库失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
 
 	if !sqlDatabase.ReadTable("moz_places") {
-		PrintFail("没有找到历史记录数据", 1)
-		return "", fmt.Errorf("no history data found")
+		PrintFail(i18n.T("没有找到历史记录数据"), 1)
+		return "", i18n.Errorf("no history data found")
 	}
 
 	placeMap := make(map[string]struct {
@@ -567,8 +648,8 @@ func FirefoxHistory(profile FirefoxProfile, browserName string) (string, error)
 	}
 
 	if !sqlDatabase.ReadTable("moz_historyvisits") {
-		PrintFail("没有找到访问历史数据", 1)
-		return "", fmt.Errorf("no visit history data found")
+		PrintFail(i18n.T("没有找到访问历史数据"), 1)
+		return "", i18n.Errorf("no visit history data found")
 	}
 
 	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
@@ -626,22 +707,22 @@ func FirefoxDownloads(profile FirefoxProfile, browserName string) (string, error
 	placesPath := profile.itemPaths["places.sqlite"]
 	tempFilename, err := CreateTmpFile(placesPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", placesPath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", placesPath), 1)
 		return "", err
 	}
 	defer RemoveFile(tempFilename)
 
 	sqlDatabase, err := NewSQLiteHandler(tempFilename)
 	if err != nil {
-		PrintFail(fmt.Sprintf("打开下载记录数据库失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("打开下载记录数据库失败: %v", err), 1)
 		return "", err
 	}
 	defer sqlDatabase.Close()
 
 	var annoAttributeId string
 	if !sqlDatabase.ReadTable("moz_anno_attributes") {
-		PrintFail("没有找到属性数据", 1)
-		return "", fmt.Errorf("no attribute data found")
+		PrintFail(i18n.T("没有找到属性数据"), 1)
+		return "", i18n.Errorf("no attribute data found")
 	}
 
 	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
@@ -653,13 +734,13 @@ func FirefoxDownloads(profile FirefoxProfile, browserName string) (string, error
 	}
 
 	if annoAttributeId == "" {
-		PrintFail("没有找到下载属性ID", 1)
-		return "", fmt.Errorf("download attribute ID not found")
+		PrintFail(i18n.T("没有找到下载属性ID"), 1)
+		return "", i18n.Errorf("download attribute ID not found")
 	}
 
 	if !sqlDatabase.ReadTable("moz_annos") {
-		PrintFail("没有找到注释数据", 1)
-		return "", fmt.Errorf("no annotation data found")
+		PrintFail(i18n.T("没有找到注释数据"), 1)
+		return "", i18n.Errorf("no annotation data found")
 	}
 
 	annoMap := make(map[string]struct {
@@ -682,8 +763,8 @@ func FirefoxDownloads(profile FirefoxProfile, browserName string) (string, error
 	}
 
 	if !sqlDatabase.ReadTable("moz_places") {
-		PrintFail("没有找到地址数据", 1)
-		return "", fmt.Errorf("no places data found")
+		PrintFail(i18n.T("没有找到地址数据"), 1)
+		return "", i18n.Errorf("no places data found")
 	}
 
 	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
@@ -744,14 +825,14 @@ func FirefoxBookmarks(profile FirefoxProfile, browserName string) (string, error
 	placesPath := profile.itemPaths["places.sqlite"]
 	tempFilename, err := CreateTmpFile(placesPath)
 	if err != nil {
-		PrintFail(fmt.Sprintf("%s Not Found!", placesPath), 1)
+		PrintFail(i18n.Sprintf("%s Not Found!", placesPath), 1)
 		return "", err
 	}
 	defer RemoveFile(tempFilename)
 
 	db, err := sql.Open("sqlite", tempFilename)
 	if err != nil {
-		PrintFail(fmt.Sprintf("打开书签数据库失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("打开书签数据库失败: %v", err), 1)
 		return "", err
 	}
 	defer db.Close()
@@ -761,7 +842,7 @@ func FirefoxBookmarks(profile FirefoxProfile, browserName string) (string, error
                           JOIN moz_places p ON b.fk = p.id 
                           WHERE b.type = 1 AND p.url NOT LIKE 'place:%'`)
 	if err != nil {
-		PrintFail(fmt.Sprintf("查询书签失败: %v", err), 1)
+		PrintFail(i18n.Sprintf("查询书签失败: %v", err), 1)
 		return "", err
 	}
 	defer rows.Close()
@@ -776,7 +857,7 @@ func FirefoxBookmarks(profile FirefoxProfile, browserName string) (string, error
 
 		folderPath, err := getBookmarkFolderPath(db, parent)
 		if err != nil {
-			folderPath = "未知文件夹"
+			folderPath = i18n.T("未知文件夹")
 		}
 
 		bookmarkInfo := fmt.Sprintf("    ---------------------------------------------------------\n")
@@ -814,6 +895,183 @@ func FirefoxBookmarks(profile FirefoxProfile, browserName string) (string, error
 	return resultBuilder.String(), nil
 }
 
+type firefoxSession struct {
+	Windows []struct {
+		Tabs []struct {
+			Entries []struct {
+				URL   string `json:"url"`
+				Title string `json:"title"`
+			} `json:"entries"`
+			Index        int   `json:"index"`
+			LastAccessed int64 `json:"lastAccessed"`
+		} `json:"tabs"`
+	} `json:"windows"`
+	Session struct {
+		LastUpdate int64 `json:"lastUpdate"`
+	} `json:"session"`
+}
+
+// FirefoxSessions 解析 sessionstore.jsonlz4(正常关闭) 和 recovery.jsonlz4(运行中/崩溃)，输出打开的标签页
+func FirefoxSessions(profile FirefoxProfile, browserName string) (string, error) {
+	var resultBuilder strings.Builder
+	header := []string{"SOURCE", "WINDOW", "URL", "TITLE", "LastAccessed"}
+	data := [][]string{}
+
+	// Firefox运行时两个文件通常同时存在，内容重复，只使用第一个能解析的，运行中的 recovery 优先
+	for _, item := range []string{"recovery.jsonlz4", "sessionstore.jsonlz4"} {
+		sessionPath := profile.itemPaths[item]
+		if !PathExists(sessionPath) {
+			continue
+		}
+
+		sessionData, err := ReadMozLz4File(sessionPath)
+		if err != nil {
+			PrintFail(i18n.Sprintf("解压 %s 失败: %v", item, err), 1)
+			continue
+		}
+
+		var session firefoxSession
+		if err := jsonpkg.Unmarshal(sessionData, &session); err != nil {
+			PrintFail(i18n.Sprintf("解析 %s 失败: %v", item, err), 1)
+			continue
+		}
+
+		for windowIndex, window := range session.Windows {
+			for _, tab := range window.Tabs {
+				if len(tab.Entries) == 0 {
+					continue
+				}
+
+				// index 从1开始，指向标签页当前显示的历史项
+				current := tab.Index - 1
+				if current < 0 || current >= len(tab.Entries) {
+					current = len(tab.Entries) - 1
+				}
+				entry := tab.Entries[current]
+
+				lastAccessed := session.Session.LastUpdate
+				if tab.LastAccessed > 0 {
+					lastAccessed = tab.LastAccessed
+				}
+				lastAccessedStr := time.UnixMilli(lastAccessed).String()
+				windowStr := strconv.Itoa(windowIndex + 1)
+
+				sessionInfo := fmt.Sprintf("    ---------------------------------------------------------\n")
+				sessionInfo += fmt.Sprintf("SOURCE: %s\n", item)
+				sessionInfo += fmt.Sprintf("WINDOW: %s\n", windowStr)
+				sessionInfo += fmt.Sprintf("URL: %s\n", entry.URL)
+				sessionInfo += fmt.Sprintf("TITLE: %s\n", entry.Title)
+				sessionInfo += fmt.Sprintf("LastAccessed: %s\n", lastAccessedStr)
+
+				resultBuilder.WriteString(sessionInfo)
+				PrintNormal("    ---------------------------------------------------------")
+				PrintSuccess(fmt.Sprintf("SOURCE: %s", item), 1)
+				PrintSuccess(fmt.Sprintf("WINDOW: %s", windowStr), 1)
+				PrintSuccess(fmt.Sprintf("URL: %s", entry.URL), 1)
+				PrintSuccess(fmt.Sprintf("TITLE: %s", entry.Title), 1)
+				PrintSuccess(fmt.Sprintf("LastAccessed: %s", lastAccessedStr), 1)
+
+				data = append(data, []string{item, windowStr, entry.URL, entry.Title, lastAccessedStr})
+			}
+		}
+		break
+	}
+
+	if Format == "json" || Format == "csv" {
+		fileName := filepath.Join(OutputDir, browserName+"_session")
+
+		if err := os.MkdirAll(OutputDir, 0755); err != nil {
+			return resultBuilder.String(), err
+		}
+
+		if Format == "json" {
+			if err := WriteJSON(header, data, fileName); err != nil {
+				return resultBuilder.String(), err
+			}
+		} else {
+			if err := WriteCSV(header, data, fileName); err != nil {
+				return resultBuilder.String(), err
+			}
+		}
+	}
+
+	return resultBuilder.String(), nil
+}
+
+func FirefoxFormHistory(profile FirefoxProfile, browserName string) (string, error) {
+	var resultBuilder strings.Builder
+	header := []string{"FIELDNAME", "VALUE", "TimesUsed", "FirstUsed", "LastUsed"}
+	data := [][]string{}
+
+	formPath := profile.itemPaths["formhistory.sqlite"]
+	tempFilename, err := CreateTmpFile(formPath)
+	if err != nil {
+		PrintFail(i18n.Sprintf("%s Not Found!", formPath), 1)
+		return "", err
+	}
+	defer RemoveFile(tempFilename)
+
+	sqlDatabase, err := NewSQLiteHandler(tempFilename)
+	if err != nil {
+		PrintFail(i18n.Sprintf("打开表单历史数据库失败: %v", err), 1)
+		return "", err
+	}
+	defer sqlDatabase.Close()
+
+	if !sqlDatabase.ReadTable("moz_formhistory") {
+		PrintFail(i18n.T("没有找到表单历史数据"), 1)
+		return "", i18n.Errorf("no form history data found")
+	}
+
+	for i := 0; i < sqlDatabase.GetRowCount(); i++ {
+		fieldName := sqlDatabase.GetValue(i, "fieldname")
+		value := sqlDatabase.GetValue(i, "value")
+		timesUsed := sqlDatabase.GetValue(i, "timesUsed")
+
+		firstUsed, _ := strconv.ParseInt(sqlDatabase.GetValue(i, "firstUsed"), 10, 64)
+		lastUsed, _ := strconv.ParseInt(sqlDatabase.GetValue(i, "lastUsed"), 10, 64)
+		firstUsedStr := time.UnixMicro(firstUsed).String()
+		lastUsedStr := time.UnixMicro(lastUsed).String()
+
+		formInfo := fmt.Sprintf("    ---------------------------------------------------------\n")
+		formInfo += fmt.Sprintf("FIELDNAME: %s\n", fieldName)
+		formInfo += fmt.Sprintf("VALUE: %s\n", value)
+		formInfo += fmt.Sprintf("TimesUsed: %s\n", timesUsed)
+		formInfo += fmt.Sprintf("FirstUsed: %s\n", firstUsedStr)
+		formInfo += fmt.Sprintf("LastUsed: %s\n", lastUsedStr)
+
+		resultBuilder.WriteString(formInfo)
+		PrintNormal("    ---------------------------------------------------------")
+		PrintSuccess(fmt.Sprintf("FIELDNAME: %s", fieldName), 1)
+		PrintSuccess(fmt.Sprintf("VALUE: %s", value), 1)
+		PrintSuccess(fmt.Sprintf("TimesUsed: %s", timesUsed), 1)
+		PrintSuccess(fmt.Sprintf("FirstUsed: %s", firstUsedStr), 1)
+		PrintSuccess(fmt.Sprintf("LastUsed: %s", lastUsedStr), 1)
+
+		data = append(data, []string{fieldName, value, timesUsed, firstUsedStr, lastUsedStr})
+	}
+
+	if Format == "json" || Format == "csv" {
+		fileName := filepath.Join(OutputDir, browserName+"_formhistory")
+
+		if err := os.MkdirAll(OutputDir, 0755); err != nil {
+			return resultBuilder.String(), err
+		}
+
+		if Format == "json" {
+			if err := WriteJSON(header, data, fileName); err != nil {
+				return resultBuilder.String(), err
+			}
+		} else {
+			if err := WriteCSV(header, data, fileName); err != nil {
+				return resultBuilder.String(), err
+			}
+		}
+	}
+
+	return resultBuilder.String(), nil
+}
+
 func getBookmarkFolderPath(db *sql.DB, parentID int) (string, error) {
 	var path []string
 	currentID := parentID
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package browers
+
+import "e0e1-config/pkg/i18n"
+
+func GetIE() (string, error) {
+	return "", i18n.New("IE is only available on Windows")
+}
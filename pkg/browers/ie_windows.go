@@ -9,6 +9,8 @@ import (
 	"time"
 	"unsafe"
 
+	"e0e1-config/pkg/i18n"
+
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
@@ -192,7 +194,7 @@ func GetVaultElementValue(vaultElementPtr uintptr) interface{} {
 
 func IE_history() (string, error) {
 	var resultBuilder strings.Builder
-	PrintVerbose("获取IE历史记录")
+	PrintVerbose(i18n.T("获取IE历史记录"))
 
 	header := []string{"URL"}
 	data := [][]string{}
@@ -241,7 +243,7 @@ func IE_history() (string, error) {
 
 func IE_books() (string, error) {
 	var resultBuilder strings.Builder
-	PrintVerbose("获取IE书签")
+	PrintVerbose(i18n.T("获取IE书签"))
 
 	header := []string{"URL", "TITLE"}
 	data := [][]string{}
@@ -306,7 +308,7 @@ func IE_books() (string, error) {
 
 func GetLogins() (string, error) {
 	var resultBuilder strings.Builder
-	PrintVerbose("获取IE凭据")
+	PrintVerbose(i18n.T("获取IE凭据"))
 
 	header := []string{"Vault Type", "Resource", "Identity", "Credential", "LastModified", "PackageSid"}
 	data := [][]string{}
@@ -324,7 +326,7 @@ func GetLogins() (string, error) {
 	var vaultGuidPtr uintptr
 	err := VaultEnumerateVaults(0, &vaultCount, &vaultGuidPtr)
 	if err != nil {
-		return "", fmt.Errorf("无法枚举保管库: %v", err)
+		return "", i18n.Errorf("无法枚举保管库: %v", err)
 	}
 
 	vaultSchema := map[string]string{
@@ -488,26 +490,26 @@ func GetLogins() (string, error) {
 
 func GetIE() (string, error) {
 	var resultBuilder strings.Builder
-	resultBuilder.WriteString("========================== IE (Current User) ==========================\n")
-	fmt.Println("========================== IE (Current User) ==========================")
+	resultBuilder.WriteString(i18n.T("========================== IE (Current User) ==========================\n"))
+	PrintNormal(i18n.T("========================== IE (Current User) =========================="))
 
 	loginResult, err := GetLogins()
 	if err != nil {
-		fmt.Printf("获取IE凭据失败: %v\n", err)
+		PrintFail(i18n.Sprintf("获取IE凭据失败: %v", err), 0)
 	} else {
 		resultBuilder.WriteString(loginResult)
 	}
 
 	bookmarkResult, err := IE_books()
 	if err != nil {
-		fmt.Printf("获取IE书签失败: %v\n", err)
+		PrintFail(i18n.Sprintf("获取IE书签失败: %v", err), 0)
 	} else {
 		resultBuilder.WriteString(bookmarkResult)
 	}
 
 	historyResult, err := IE_history()
 	if err != nil {
-		fmt.Printf("获取IE历史记录失败: %v\n", err)
+		PrintFail(i18n.Sprintf("获取IE历史记录失败: %v", err), 0)
 	} else {
 		resultBuilder.WriteString(historyResult)
 	}
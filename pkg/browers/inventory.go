@@ -0,0 +1,81 @@
+package browers
+
+import (
+	"e0e1-config/pkg/i18n"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type userProfile struct {
+	Name string
+	Dir  string
+}
+
+// userProfileDirs 与 GetChromium / GetFirefox 相同：管理员权限下枚举所有用户目录，否则只返回当前用户
+func userProfileDirs() []userProfile {
+	if !IsHighIntegrity() {
+		return []userProfile{{Name: "Current User", Dir: os.Getenv("USERPROFILE")}}
+	}
+
+	var dirs []userProfile
+	userFolder := fmt.Sprintf("%s\\Users\\", os.Getenv("SystemDrive"))
+	matches, _ := filepath.Glob(filepath.Join(userFolder, "*"))
+	for _, dir := range matches {
+		if strings.Contains(dir, "All Users") || strings.Contains(dir, "Public") || strings.Contains(dir, "Default") {
+			continue
+		}
+		parts := strings.Split(dir, "\\")
+		dirs = append(dirs, userProfile{Name: parts[len(parts)-1], Dir: dir})
+	}
+	return dirs
+}
+
+// InventoryBrowsers 只检查各浏览器数据文件是否存在，不复制数据库也不解密
+func InventoryBrowsers() string {
+	var result strings.Builder
+
+	for _, user := range userProfileDirs() {
+		userName, dir := user.Name, user.Dir
+		for _, browser := range chromiumBrowsers {
+			profilePath := dir + browser[1]
+			statePath := dir + strings.Replace(browser[1], "\\Default", "", 1) + "\\Local State"
+
+			var items []string
+			for _, item := range []string{"Login Data", "Cookies", "Network\\Cookies", "History", "Bookmarks"} {
+				if PathExists(profilePath + "\\" + item) {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				continue
+			}
+			if PathExists(statePath) {
+				items = append(items, "Local State")
+			}
+			result.WriteString(fmt.Sprintf("%s (%s): %s\n", browser[0], userName, profilePath))
+			result.WriteString(i18n.Sprintf("  数据文件: %s\n", strings.Join(items, ", ")))
+		}
+
+		profiles, err := getFirefoxProfiles(dir + "\\AppData\\Roaming\\Mozilla\\Firefox\\Profiles")
+		if err != nil {
+			continue
+		}
+		for _, profile := range profiles {
+			var items []string
+			for _, item := range []string{"key4.db", "logins.json", "cookies.sqlite", "places.sqlite"} {
+				if PathExists(profile.itemPaths[item]) {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				continue
+			}
+			result.WriteString(fmt.Sprintf("Firefox (%s): %s\n", userName, profile.profilePath))
+			result.WriteString(i18n.Sprintf("  数据文件: %s\n", strings.Join(items, ", ")))
+		}
+	}
+
+	return result.String()
+}
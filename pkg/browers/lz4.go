@@ -0,0 +1,117 @@
+package browers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+)
+
+var (
+	ErrMozLz4Magic   = errors.New("not a mozLz4 file")
+	ErrLz4Corrupt    = errors.New("lz4 block is corrupt")
+	ErrMozLz4Size    = errors.New("mozLz4 decompressed size is too large")
+	mozLz4Magic      = []byte("mozLz40\x00")
+	mozLz4HeaderSize = len(mozLz4Magic) + 4
+)
+
+// LZ4 的压缩率最高约为255倍，头部的解压后长度不可信，超过该比例或上限的直接拒绝
+const (
+	lz4MaxRatio   = 255
+	mozLz4MaxSize = 256 << 20
+)
+
+// ReadMozLz4File 读取Firefox的 .jsonlz4/.mozlz4/.baklz4 文件并解压
+func ReadMozLz4File(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMozLz4(data)
+}
+
+// DecodeMozLz4 格式为 8字节magic + 4字节小端解压后长度 + LZ4 block
+func DecodeMozLz4(data []byte) ([]byte, error) {
+	if len(data) < mozLz4HeaderSize || !bytes.Equal(data[:len(mozLz4Magic)], mozLz4Magic) {
+		return nil, ErrMozLz4Magic
+	}
+
+	size := int(binary.LittleEndian.Uint32(data[len(mozLz4Magic):mozLz4HeaderSize]))
+	block := data[mozLz4HeaderSize:]
+	if size > mozLz4MaxSize || size > len(block)*lz4MaxRatio {
+		return nil, ErrMozLz4Size
+	}
+	return decodeLz4Block(block, size)
+}
+
+func decodeLz4Block(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		literalLen := int(token >> 4)
+		if literalLen == 15 {
+			for {
+				if i >= len(src) {
+					return nil, ErrLz4Corrupt
+				}
+				b := src[i]
+				i++
+				literalLen += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+
+		if i+literalLen > len(src) {
+			return nil, ErrLz4Corrupt
+		}
+		dst = append(dst, src[i:i+literalLen]...)
+		i += literalLen
+
+		// 最后一个序列只有字面量，没有match部分
+		if i >= len(src) {
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, ErrLz4Corrupt
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i : i+2]))
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, ErrLz4Corrupt
+		}
+
+		matchLen := int(token & 0x0F)
+		if matchLen == 15 {
+			for {
+				if i >= len(src) {
+					return nil, ErrLz4Corrupt
+				}
+				b := src[i]
+				i++
+				matchLen += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		matchLen += 4
+
+		// match可能与输出重叠，需要逐字节复制
+		start := len(dst) - offset
+		for j := 0; j < matchLen; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+
+	if size > 0 && len(dst) != size {
+		return nil, ErrLz4Corrupt
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,111 @@
+package browers
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sessionJSON 和 sessionBlock 由 lz4 命令行工具压缩得到
+const (
+	sessionJSON  = `{"windows":[{"tabs":[{"entries":[{"url":"https://example.com/a","title":"Example A"}],"index":1},{"entries":[{"url":"https://example.com/b","title":"Example B"}],"index":1}]}]}`
+	sessionBlock = "f2027b2277696e646f7773223a5b7b22746162090062656e747269650c00f21875726c223a2268747470733a2f2f6578616d706c652e636f6d2f61222c227469746c65223a22451800ff022041227d5d2c22696e646578223a317d2c4c00151f624c000019424c00507d5d7d5d7d"
+)
+
+func mozLz4(size int, block []byte) []byte {
+	header := make([]byte, 4)
+	binary.LittleEndian.PutUint32(header, uint32(size))
+	return append(append(append([]byte(nil), mozLz4Magic...), header...), block...)
+}
+
+// literalBlock 只包含字面量的LZ4 block，用于构造测试文件
+func literalBlock(data []byte) []byte {
+	n := len(data)
+	if n < 15 {
+		return append([]byte{byte(n << 4)}, data...)
+	}
+	block := []byte{0xF0}
+	for n -= 15; n >= 255; n -= 255 {
+		block = append(block, 255)
+	}
+	block = append(block, byte(n))
+	return append(block, data...)
+}
+
+func TestDecodeMozLz4(t *testing.T) {
+	block, _ := hex.DecodeString(sessionBlock)
+	long := strings.Repeat("x", 300)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"lz4 tool output", mozLz4(len(sessionJSON), block), sessionJSON},
+		{"overlapping match", mozLz4(13, []byte{0x35, 'a', 'b', 'c', 3, 0, 0x10, '!'}), "abcabcabcabc!"},
+		{"long literal", mozLz4(len(long), literalBlock([]byte(long))), long},
+		{"unknown size", mozLz4(0, literalBlock([]byte("abc"))), "abc"},
+	}
+	for _, tt := range tests {
+		got, err := DecodeMozLz4(tt.data)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: DecodeMozLz4() = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeMozLz4Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"no magic", []byte("mozLz40"), ErrMozLz4Magic},
+		{"wrong magic", append([]byte("mozLz41\x00"), 3, 0, 0, 0, 0x30, 'a', 'b', 'c'), ErrMozLz4Magic},
+		{"huge size", mozLz4(0xFFFFFFFF, literalBlock([]byte("abc"))), ErrMozLz4Size},
+		{"size above ratio", mozLz4(4*lz4MaxRatio+1, literalBlock([]byte("abc"))), ErrMozLz4Size},
+		{"size mismatch", mozLz4(4, literalBlock([]byte("abc"))), ErrLz4Corrupt},
+		{"truncated literal", mozLz4(5, []byte{0x50, 'a', 'b'}), ErrLz4Corrupt},
+		{"zero offset", mozLz4(8, []byte{0x10, 'a', 0, 0}), ErrLz4Corrupt},
+		{"offset before start", mozLz4(8, []byte{0x10, 'a', 2, 0}), ErrLz4Corrupt},
+		{"truncated offset", mozLz4(8, []byte{0x10, 'a', 1}), ErrLz4Corrupt},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeMozLz4(tt.data); err != tt.want {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// Firefox运行时 recovery.jsonlz4 和 sessionstore.jsonlz4 同时存在，标签页只应输出一次
+func TestFirefoxSessionsNoDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	profile := FirefoxProfile{name: "test", profilePath: dir, itemPaths: map[string]string{}}
+	for _, item := range []string{"recovery.jsonlz4", "sessionstore.jsonlz4"} {
+		path := filepath.Join(dir, item)
+		if err := os.WriteFile(path, mozLz4(len(sessionJSON), literalBlock([]byte(sessionJSON))), 0644); err != nil {
+			t.Fatal(err)
+		}
+		profile.itemPaths[item] = path
+	}
+
+	oldFormat := Format
+	Format = ""
+	defer func() { Format = oldFormat }()
+
+	result, err := FirefoxSessions(profile, "Firefox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+		if n := strings.Count(result, "URL: "+url+"\n"); n != 1 {
+			t.Errorf("%s appears %d times, want 1\n%s", url, n, result)
+		}
+	}
+	if !strings.Contains(result, "SOURCE: recovery.jsonlz4") {
+		t.Errorf("expected recovery.jsonlz4 to be preferred\n%s", result)
+	}
+}
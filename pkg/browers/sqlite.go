@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"time"
 
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+
 	_ "github.com/glebarez/sqlite"
 )
 
@@ -20,12 +23,12 @@ func NewSQLiteHandler(filePath string) (*SQLiteHandler, error) {
 
 	db, err := sql.Open("sqlite", filePath)
 	if err != nil {
-		return nil, fmt.Errorf("打开SQLite数据库失败: %v", err)
+		return nil, i18n.Errorf("打开SQLite数据库失败: %v", err)
 	}
 
 	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("连接SQLite数据库失败: %v", err)
+		return nil, i18n.Errorf("连接SQLite数据库失败: %v", err)
 	}
 
 	return &SQLiteHandler{
@@ -46,7 +49,7 @@ func (h *SQLiteHandler) GetTableNames() []string {
 	query := `SELECT name FROM sqlite_master WHERE type='table' ORDER BY name`
 	rows, err := h.db.Query(query)
 	if err != nil {
-		fmt.Printf("获取表名列表时出错: %v\n", err)
+		PrintFail(i18n.Sprintf("获取表名列表时出错: %v", err), 1)
 		return tables
 	}
 	defer rows.Close()
@@ -70,19 +73,19 @@ func (h *SQLiteHandler) ReadTable(tableName string) bool {
 	checkTableQuery := `SELECT count(*) FROM sqlite_master WHERE type='table' AND name=?`
 	err := h.db.QueryRow(checkTableQuery, tableName).Scan(&tableExists)
 	if err != nil {
-		fmt.Printf("检查表 %s 是否存在时出错: %v\n", tableName, err)
+		PrintFail(i18n.Sprintf("检查表 %s 是否存在时出错: %v", tableName, err), 1)
 		return false
 	}
 
 	if tableExists == 0 {
-		fmt.Printf("[-] 没有查询到%s该信息\n", tableName)
+		logger.Debug(i18n.Sprintf("[-] 没有查询到%s该信息", tableName), "browser", BrowserName)
 		return false
 	}
 
 	pragmaQuery := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
 	pragmaRows, err := h.db.Query(pragmaQuery)
 	if err != nil {
-		fmt.Printf("获取表 %s 结构时出错: %v\n", tableName, err)
+		PrintFail(i18n.Sprintf("获取表 %s 结构时出错: %v", tableName, err), 1)
 		return false
 	}
 	defer pragmaRows.Close()
@@ -94,14 +97,14 @@ func (h *SQLiteHandler) ReadTable(tableName string) bool {
 		var notNull, pk int
 		var dfltValue interface{}
 		if err := pragmaRows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
-			fmt.Printf("扫描表结构时出错: %v\n", err)
+			PrintFail(i18n.Sprintf("扫描表结构时出错: %v", err), 1)
 			continue
 		}
 		h.fieldNames = append(h.fieldNames, name)
 	}
 
 	if len(h.fieldNames) == 0 {
-		fmt.Printf("表 %s 没有字段\n", tableName)
+		PrintFail(i18n.Sprintf("表 %s 没有字段", tableName), 1)
 		return false
 	}
 
@@ -110,14 +113,14 @@ func (h *SQLiteHandler) ReadTable(tableName string) bool {
 	dataQuery := fmt.Sprintf("SELECT * FROM %s LIMIT %s", tableName, browerlimit)
 	dataRows, err := h.db.Query(dataQuery)
 	if err != nil {
-		fmt.Printf("查询表 %s 数据时出错: %v\n", tableName, err)
+		PrintFail(i18n.Sprintf("查询表 %s 数据时出错: %v", tableName, err), 1)
 		return false
 	}
 	defer dataRows.Close()
 
 	columns, err := dataRows.Columns()
 	if err != nil {
-		fmt.Printf("获取列信息时出错: %v\n", err)
+		PrintFail(i18n.Sprintf("获取列信息时出错: %v", err), 1)
 		return false
 	}
 
@@ -130,7 +133,7 @@ func (h *SQLiteHandler) ReadTable(tableName string) bool {
 	rowCount := 0
 	for dataRows.Next() {
 		if err := dataRows.Scan(valuePtrs...); err != nil {
-			fmt.Printf("扫描行数据时出错: %v\n", err)
+			PrintFail(i18n.Sprintf("扫描行数据时出错: %v", err), 1)
 			continue
 		}
 
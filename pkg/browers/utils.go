@@ -1,13 +1,12 @@
 package browers
 
 import (
+	"e0e1-config/pkg/logger"
 	"encoding/csv"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 )
 
@@ -30,6 +29,11 @@ func SetFormat(format string) {
 	}
 }
 
+// DisablePrintOut 不在控制台输出读取到的明细，只保留返回的结果
+func DisablePrintOut() {
+	PrintOut = false
+}
+
 func SetOutputDir(dir string) {
 	OutputDir = dir
 }
@@ -144,28 +148,28 @@ func WriteJSON(header []string, data [][]string, fileName string) error {
 	return ioutil.WriteFile(fileName+".json", jsonBytes, 0644)
 }
 
+// PrintNormal 读取到的明细已经包含在返回的结果中，这里只作为调试日志输出到标准错误
 func PrintNormal(message string) {
 	if PrintOut {
-		fmt.Println(message)
+		logger.Debug(message, "browser", BrowserName)
 	}
 }
 
+// PrintSuccess 同 PrintNormal，indent 是原来控制台输出的缩进层级，日志中不再使用
 func PrintSuccess(message string, indent int) {
 	if PrintOut {
-		indentStr := strings.Repeat("  ", indent)
-		fmt.Printf("%s[+] %s\n", indentStr, message)
+		logger.Debug(message, "browser", BrowserName)
 	}
 }
 
+// PrintFail 失败信息属于诊断信息，写入日志而不是结果
 func PrintFail(message string, indent int) {
-	if PrintOut {
-		indentStr := strings.Repeat("  ", indent)
-		fmt.Printf("%s[-] %s\n", indentStr, message)
-	}
+	logger.Warn(message, "browser", BrowserName)
 }
 
+// PrintVerbose 输出当前正在读取的数据类型
 func PrintVerbose(message string) {
-	fmt.Printf("[*] %s\n", message)
+	logger.Info(message)
 }
 
 func IsTrueFalse(value string) string {
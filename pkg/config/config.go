@@ -0,0 +1,159 @@
+package config
+
+import (
+	"e0e1-config/pkg/i18n"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultPath 未指定 -config 时尝试加载的配置文件
+const DefaultPath = "e0e1-config.toml"
+
+// Config 保存配置文件中的取值，section为空的项对所有子命令生效，
+// [collect]、[search] 等section中的项只对同名子命令生效
+type Config map[string]map[string]string
+
+// Load 读取TOML格式的配置文件，只支持 key = value 和 [section]，
+// 值可以是字符串、数字、布尔值或字符串数组，数组会用逗号拼接
+func Load(path string) (Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("读取配置文件失败: %v", err)
+	}
+	return Parse(string(content))
+}
+
+func Parse(content string) (Config, error) {
+	config := Config{"": make(map[string]string)}
+	section := ""
+
+	for i, line := range strings.Split(strings.TrimPrefix(content, "\ufeff"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if _, ok := config[section]; !ok {
+				config[section] = make(map[string]string)
+			}
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			return nil, i18n.Errorf("第%d行格式错误: %s", i+1, line)
+		}
+
+		key := normalizeKey(line[:idx])
+		value, err := parseValue(strings.TrimSpace(line[idx+1:]))
+		if err != nil {
+			return nil, i18n.Errorf("第%d行 %s 的值错误: %v", i+1, key, err)
+		}
+		config[section][key] = value
+	}
+
+	return config, nil
+}
+
+// Values 返回对指定子命令生效的配置项，子命令section中的值覆盖全局值
+func (c Config) Values(section string) map[string]string {
+	values := make(map[string]string)
+	for key, value := range c[""] {
+		values[key] = value
+	}
+	for key, value := range c[strings.ToLower(section)] {
+		values[key] = value
+	}
+	return values
+}
+
+// normalizeKey 配置项名称与命令行参数名一致，同时允许使用下划线
+func normalizeKey(key string) string {
+	key = strings.Trim(strings.TrimSpace(key), `"`)
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+func parseValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := closingQuote(raw)
+		if end < 0 {
+			return "", i18n.Errorf("字符串缺少结束引号")
+		}
+		return strconv.Unquote(raw[:end+1])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", i18n.Errorf("字符串缺少结束引号")
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, "["):
+		end := strings.LastIndex(raw, "]")
+		if end < 0 {
+			return "", i18n.Errorf("数组缺少结束括号")
+		}
+		var items []string
+		for _, item := range splitArray(raw[1:end]) {
+			value, err := parseValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, value)
+		}
+		return strings.Join(items, ","), nil
+	}
+
+	// 未加引号的值允许行尾注释
+	if idx := strings.Index(raw, "#"); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	return raw, nil
+}
+
+// closingQuote 返回基本字符串结束引号的位置，跳过转义字符
+func closingQuote(raw string) int {
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func splitArray(raw string) []string {
+	var items []string
+	var current strings.Builder
+	var quote byte
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			current.WriteByte(c)
+			if c == '\\' && quote == '"' && i+1 < len(raw) {
+				i++
+				current.WriteByte(raw[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			current.WriteByte(c)
+		case c == ',':
+			items = append(items, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if item := strings.TrimSpace(current.String()); item != "" {
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := "\ufeff# 全局配置\r\n" +
+		"lang = \"en\"\r\n" +
+		"Log_Level = debug # 行尾注释\r\n" +
+		"\"output\" = 'C:\\out\\result.txt'\r\n" +
+		"threads = 8\r\n" +
+		"\r\n" +
+		"[ Collect ]\r\n" +
+		"modules = [\"navicat\", 'winscp', \"a,b\", ]\r\n" +
+		"password = \"p\\\"w # not a comment\" # comment\r\n" +
+		"verbose = true\r\n" +
+		"[search]\r\n" +
+		"regex = [\"\\\\d+\\\\]\", 'x']\r\n"
+
+	config, err := Parse(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		"": {
+			"lang":      "en",
+			"log-level": "debug",
+			"output":    `C:\out\result.txt`,
+			"threads":   "8",
+		},
+		"collect": {
+			"modules":  "navicat,winscp,a,b",
+			"password": `p"w # not a comment`,
+			"verbose":  "true",
+		},
+		"search": {
+			"regex": `\d+\],x`,
+		},
+	}
+	for section, values := range want {
+		for key, value := range values {
+			if got, ok := config[section][key]; !ok || got != value {
+				t.Errorf("[%s] %s = %q, want %q", section, key, got, value)
+			}
+		}
+		if len(config[section]) != len(values) {
+			t.Errorf("[%s] has %d keys, want %d: %v", section, len(config[section]), len(values), config[section])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing equals":       "lang en",
+		"missing key":          "= en",
+		"unterminated string":  `lang = "en`,
+		"unterminated literal": "lang = 'en",
+		"unterminated array":   `modules = ["a", "b"`,
+		"bad item in array":    `modules = ["a]`,
+		"bad escape":           `lang = "\q"`,
+	}
+	for name, content := range tests {
+		if config, err := Parse("[collect]\n" + content); err == nil {
+			t.Errorf("%s: expected error, got %v", name, config)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	config, err := Parse("lang = zh\noutput = a.txt\n[collect]\noutput = b.txt\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := config.Values("Collect")
+	if values["lang"] != "zh" || values["output"] != "b.txt" {
+		t.Errorf("Values(collect) = %v", values)
+	}
+	if values := config.Values("search"); values["output"] != "a.txt" {
+		t.Errorf("Values(search) = %v", values)
+	}
+	// 返回的是副本，修改不影响配置本身
+	values["lang"] = "en"
+	if config[""]["lang"] != "zh" {
+		t.Error("Values() should return a copy")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := os.WriteFile(path, []byte("[collect]\nmodules = ['xshell']\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Values("collect")["modules"]; got != "xshell" {
+		t.Errorf("modules = %q", got)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
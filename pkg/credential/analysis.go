@@ -0,0 +1,141 @@
+package credential
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"e0e1-config/pkg/i18n"
+)
+
+// Reuse 同一个密码出现在多个主机或应用中
+type Reuse struct {
+	Password    string       `json:"password"`
+	Credentials []Credential `json:"credentials"`
+	Hosts       []string     `json:"hosts"`
+	Sources     []string     `json:"sources"`
+}
+
+// Account 同一个用户名对应的主机和应用
+type Account struct {
+	User      string   `json:"user"`
+	Hosts     []string `json:"hosts"`
+	Sources   []string `json:"sources"`
+	Passwords int      `json:"passwords"`
+}
+
+// Analysis 凭据去重、密码复用和用户名关联的结果
+type Analysis struct {
+	Total       int          `json:"total"`
+	Unique      int          `json:"unique"`
+	Duplicates  int          `json:"duplicates"`
+	Credentials []Credential `json:"credentials"`
+	Reused      []Reuse      `json:"reused"`
+	Users       []Account    `json:"users"`
+}
+
+// Analyze 去掉来源、主机、端口、用户、密码完全相同的重复项，再按密码和用户名分组
+func Analyze(creds []Credential) Analysis {
+	a := Analysis{Total: len(creds)}
+
+	seen := make(map[string]bool)
+	for _, c := range creds {
+		if seen[c.key()] {
+			continue
+		}
+		seen[c.key()] = true
+		a.Credentials = append(a.Credentials, c)
+	}
+	sort.SliceStable(a.Credentials, func(i, j int) bool {
+		return less(a.Credentials[i], a.Credentials[j])
+	})
+	a.Unique = len(a.Credentials)
+	a.Duplicates = a.Total - a.Unique
+
+	byPassword := make(map[string][]Credential)
+	byUser := make(map[string][]Credential)
+	for _, c := range a.Credentials {
+		byPassword[c.Password] = append(byPassword[c.Password], c)
+		if c.User != "" {
+			byUser[c.User] = append(byUser[c.User], c)
+		}
+	}
+
+	// 密码在两个以上不同的主机或应用中出现才算复用
+	for password, group := range byPassword {
+		hosts, sources := collectTargets(group)
+		if len(group) < 2 || (len(hosts) < 2 && len(sources) < 2) {
+			continue
+		}
+		a.Reused = append(a.Reused, Reuse{Password: password, Credentials: group, Hosts: hosts, Sources: sources})
+	}
+	sort.Slice(a.Reused, func(i, j int) bool {
+		if len(a.Reused[i].Credentials) != len(a.Reused[j].Credentials) {
+			return len(a.Reused[i].Credentials) > len(a.Reused[j].Credentials)
+		}
+		return a.Reused[i].Password < a.Reused[j].Password
+	})
+
+	for user, group := range byUser {
+		hosts, sources := collectTargets(group)
+		passwords := make(map[string]bool)
+		for _, c := range group {
+			passwords[c.Password] = true
+		}
+		a.Users = append(a.Users, Account{User: user, Hosts: hosts, Sources: sources, Passwords: len(passwords)})
+	}
+	sort.Slice(a.Users, func(i, j int) bool {
+		if len(a.Users[i].Hosts) != len(a.Users[j].Hosts) {
+			return len(a.Users[i].Hosts) > len(a.Users[j].Hosts)
+		}
+		return a.Users[i].User < a.Users[j].User
+	})
+
+	return a
+}
+
+func collectTargets(group []Credential) ([]string, []string) {
+	hostSet := make(map[string]bool)
+	sourceSet := make(map[string]bool)
+	for _, c := range group {
+		if c.Host != "" {
+			hostSet[c.Host] = true
+		}
+		sourceSet[c.Source] = true
+	}
+	return sortedKeys(hostSet), sortedKeys(sourceSet)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String 文本格式的关联分析结果，附加在采集结果末尾
+func (a Analysis) String() string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("===== 凭据关联分析 =====\n"))
+	sb.WriteString(i18n.Sprintf("凭据总数: %d，去重后: %d，重复: %d\n", a.Total, a.Unique, a.Duplicates))
+
+	sb.WriteString(i18n.Sprintf("\n[密码复用] 共 %d 组\n", len(a.Reused)))
+	for _, r := range a.Reused {
+		sb.WriteString(i18n.Sprintf("密码: %s  (%d 处，主机: %d，应用: %s)\n", Display(r.Password), len(r.Credentials), len(r.Hosts), strings.Join(r.Sources, ", ")))
+		for _, c := range r.Credentials {
+			fmt.Fprintf(&sb, "    %-12s %s\n", c.Source, c.Target())
+		}
+	}
+
+	sb.WriteString(i18n.Sprintf("\n[用户名关联] 共 %d 个用户名\n", len(a.Users)))
+	for _, u := range a.Users {
+		sb.WriteString(i18n.Sprintf("用户名: %s  (主机: %d，不同密码: %d，应用: %s)\n", u.User, len(u.Hosts), u.Passwords, strings.Join(u.Sources, ", ")))
+		if len(u.Hosts) > 0 {
+			fmt.Fprintf(&sb, "    %s\n", strings.Join(u.Hosts, ", "))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
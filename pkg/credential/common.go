@@ -0,0 +1,29 @@
+package credential
+
+// commonPasswords 内置的常见弱口令，按使用频率排序，序号越小越容易被猜到，可用 -common-passwords 追加
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "123123", "111111", "abc123", "1234567",
+	"dragon", "1q2w3e4r", "sunshine", "654321", "master", "1234", "football", "1234567890", "000000", "computer",
+	"666666", "superman", "michael", "internet", "iloveyou", "daniel", "1qaz2wsx", "monkey", "shadow", "jessica",
+	"letmein", "baseball", "whatever", "princess", "abcd1234", "123321", "starwars", "121212", "thomas", "zxcvbnm",
+	"trustno1", "killer", "welcome", "jordan", "aaaaaa", "123qwe", "freedom", "password1", "charlie", "batman",
+	"jennifer", "7777777", "michelle", "diamond", "oliver", "mercedes", "benjamin", "11111111", "snoopy", "samantha",
+	"victoria", "matrix", "george", "alexander", "secret", "cookie", "asdfgh", "987654321", "123abc", "orange",
+	"fuckyou", "asdf1234", "pepper", "hunter", "silver", "joshua", "banana", "1q2w3e", "chelsea", "1234qwer",
+	"summer", "qwertyuiop", "phoenix", "andrew", "q1w2e3r4", "elephant", "rainbow", "mustang", "merlin", "london",
+	"garfield", "robert", "chocolate", "112233", "samsung", "qazwsx", "matthew", "buster", "jonathan", "ginger",
+	"flower", "555555", "test", "caroline", "amanda", "maverick", "midnight", "martin", "junior", "88888888",
+	"anthony", "jasmine", "creative", "patrick", "mickey", "123", "qwerty123", "cocacola", "chicken", "passw0rd",
+	"forever", "william", "nicole", "hello", "yellow", "nirvana", "justin", "friends", "cheese", "tigger",
+	"mother", "liverpool", "blink182", "asdfghjkl", "andrea", "spider", "scooter", "richard", "soccer", "rachel",
+	"purple", "morgan", "melissa", "jackson", "arsenal", "222222", "qwe123", "gabriel", "ferrari", "jasper",
+	"danielle", "bandit", "angela", "scorpion", "prince", "maggie", "austin", "veronica", "nicholas", "monster",
+	"dexter", "carlos", "thunder", "success", "hannah", "ashley", "1qazxsw2", "loveyou", "pokemon", "qwerty1",
+	"admin", "admin123", "admin888", "administrator", "root", "toor", "root123", "qwe123456", "guest", "changeme",
+	"default", "oracle", "system", "manager", "postgres", "mysql", "sa", "redis", "tomcat", "ftp",
+	"user", "test123", "test1234", "p@ssw0rd", "p@ssword", "pass", "pass123", "pass1234", "passwd", "password123",
+	"admin@123", "root@123", "abc@123", "a123456", "aa123456", "a12345678", "woaini", "woaini1314", "5201314", "1314520",
+	"888888", "8888888", "147258369", "147258", "159357", "qq123456", "zxc123", "zxcvbn", "asd123", "1qaz@wsx",
+	"welcome1", "welcome123", "qwer1234", "huawei", "huawei123", "huawei@123", "changeme123", "server", "backup", "ubuntu",
+	"centos", "raspberry", "vagrant",
+}
@@ -0,0 +1,152 @@
+package credential
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"e0e1-config/pkg/i18n"
+)
+
+// Credential 各模块解密出的一条凭据，只记录成功恢复明文密码的条目
+type Credential struct {
+	Source   string `json:"source"`
+	Name     string `json:"name,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Port     string `json:"port,omitempty"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"password"`
+}
+
+// 密码的脱敏方式
+const (
+	RedactNone    = "none"
+	RedactPartial = "partial"
+	RedactFull    = "full"
+)
+
+// RedactModes 支持的脱敏方式
+var RedactModes = []string{RedactNone, RedactPartial, RedactFull}
+
+var (
+	mu     sync.Mutex
+	store  []Credential
+	redact = RedactNone
+)
+
+// SetRedact 设置输出分析结果时密码的脱敏方式，partial 只保留首尾字符，full 完全隐藏
+func SetRedact(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = RedactNone
+	}
+	for _, m := range RedactModes {
+		if m == mode {
+			redact = mode
+			return nil
+		}
+	}
+	return i18n.Errorf("不支持的脱敏方式: %s，可选: %s", mode, strings.Join(RedactModes, ", "))
+}
+
+// Redacting 当前是否对密码脱敏
+func Redacting() bool {
+	return redact != RedactNone
+}
+
+// Display 按脱敏方式返回用于显示的密码
+func Display(password string) string {
+	return Mask(password, redact)
+}
+
+// Mask 按指定的脱敏方式处理敏感内容，partial 时较短的内容也完全隐藏
+func Mask(secret, mode string) string {
+	switch mode {
+	case RedactFull:
+		return i18n.T("[已隐藏]")
+	case RedactPartial:
+		runes := []rune(secret)
+		if len(runes) < 6 {
+			return strings.Repeat("*", len(runes))
+		}
+		return string(runes[0]) + strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-1])
+	}
+	return secret
+}
+
+// Add 记录一条凭据，模块可能并发执行，所以加锁；密码为空时忽略
+func Add(c Credential) {
+	c.Password = strings.TrimSpace(c.Password)
+	if c.Password == "" {
+		return
+	}
+	c.Host = NormalizeHost(c.Host)
+	c.User = strings.TrimSpace(c.User)
+
+	mu.Lock()
+	defer mu.Unlock()
+	store = append(store, c)
+}
+
+// All 返回已记录的凭据，按来源、主机、用户排序，与模块执行顺序无关
+func All() []Credential {
+	mu.Lock()
+	creds := append([]Credential(nil), store...)
+	mu.Unlock()
+
+	sort.SliceStable(creds, func(i, j int) bool {
+		return less(creds[i], creds[j])
+	})
+	return creds
+}
+
+// Reset 清空已记录的凭据
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	store = nil
+}
+
+// NormalizeHost 浏览器保存的是URL，只保留主机名，其它来源统一转为小写
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil && u.Host != "" {
+			host = u.Hostname()
+		}
+	} else if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+func less(a, b Credential) bool {
+	for _, pair := range [][2]string{{a.Source, b.Source}, {a.Host, b.Host}, {a.Port, b.Port},
+		{a.User, b.User}, {a.Name, b.Name}, {a.Password, b.Password}} {
+		if pair[0] != pair[1] {
+			return pair[0] < pair[1]
+		}
+	}
+	return false
+}
+
+func (c Credential) key() string {
+	return strings.Join([]string{c.Source, c.Host, c.Port, c.User, c.Password}, "\x00")
+}
+
+// Target 用于显示的 用户@主机:端口
+func (c Credential) Target() string {
+	target := c.Host
+	if target == "" {
+		target = c.Name
+	}
+	if c.Port != "" {
+		target += ":" + c.Port
+	}
+	if c.User != "" {
+		target = c.User + "@" + target
+	}
+	return target
+}
@@ -0,0 +1,519 @@
+package credential
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+)
+
+// Issue 密码不符合策略的原因，取值是稳定的英文标识，显示时再翻译
+type Issue string
+
+const (
+	IssueShort   Issue = "short"
+	IssueClasses Issue = "classes"
+	IssueCommon  Issue = "common"
+	IssueUser    Issue = "user"
+	IssueHost    Issue = "host"
+	IssueWeak    Issue = "weak"
+)
+
+var issueLabels = map[Issue]string{
+	IssueShort:   "长度不足8位",
+	IssueClasses: "字符类型少于3种",
+	IssueCommon:  "常见密码",
+	IssueUser:    "包含用户名",
+	IssueHost:    "包含主机名",
+	IssueWeak:    "容易被猜到",
+}
+
+func (i Issue) String() string {
+	if label, ok := issueLabels[i]; ok {
+		return i18n.T(label)
+	}
+	return string(i)
+}
+
+// levelLabels 强度等级0-4，与zxcvbn的score含义相同
+var levelLabels = []string{"极弱", "弱", "一般", "强", "很强"}
+
+// LevelName 强度等级的显示名称
+func LevelName(level int) string {
+	if level < 0 || level >= len(levelLabels) {
+		return fmt.Sprint(level)
+	}
+	return i18n.T(levelLabels[level])
+}
+
+// Score 单个密码的强度评估，不包含密码本身，可以单独输出
+type Score struct {
+	Length  int     `json:"length"`
+	Classes int     `json:"classes"`
+	Guesses float64 `json:"guesses_log10"`
+	Level   int     `json:"level"`
+	Issues  []Issue `json:"issues,omitempty"`
+}
+
+// Compliant 是否符合密码策略
+func (s Score) Compliant() bool {
+	return len(s.Issues) == 0
+}
+
+func (s Score) has(issue Issue) bool {
+	for _, i := range s.Issues {
+		if i == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// Assessment 一条凭据及其强度评估
+type Assessment struct {
+	Credential Credential `json:"credential"`
+	Score      Score      `json:"score"`
+}
+
+// Stats 按应用汇总的强度统计
+type Stats struct {
+	Source    string  `json:"source"`
+	Total     int     `json:"total"`
+	AvgLength float64 `json:"avg_length"`
+	Weak      int     `json:"weak"`
+	Common    int     `json:"common"`
+	User      int     `json:"contains_user"`
+	Host      int     `json:"contains_host"`
+	Compliant int     `json:"compliant"`
+	Levels    [5]int  `json:"levels"`
+}
+
+// StrengthReport 所有凭据的强度评估结果
+type StrengthReport struct {
+	Items []Assessment `json:"items"`
+	Stats []Stats      `json:"stats"`
+	Total Stats        `json:"total"`
+}
+
+var dictionary = make(map[string]int)
+
+func init() {
+	for i, password := range commonPasswords {
+		addCommon(password, i+1)
+	}
+}
+
+func addCommon(password string, rank int) {
+	password = strings.ToLower(strings.TrimSpace(password))
+	if password == "" {
+		return
+	}
+	if old, ok := dictionary[password]; !ok || rank < old {
+		dictionary[password] = rank
+	}
+}
+
+// LoadCommonPasswords 从文件追加常见密码，每行一个，按行号作为使用频率排名
+func LoadCommonPasswords(path string) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return i18n.Errorf("读取常见密码列表失败: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	rank := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rank++
+		addCommon(line, rank)
+	}
+	if err := scanner.Err(); err != nil {
+		return i18n.Errorf("读取常见密码列表失败: %v", err)
+	}
+	return nil
+}
+
+// Evaluate 评估密码强度，用户名和主机名用于检查密码中是否包含它们
+func Evaluate(password, user, host string) Score {
+	runes := []rune(password)
+	score := Score{Length: len(runes), Classes: countClasses(runes)}
+
+	guesses := estimateGuesses(password, userTokens(user), hostTokens(host))
+	score.Guesses = math.Round(math.Log10(guesses)*100) / 100
+	score.Level = levelFor(guesses)
+
+	if score.Length < 8 {
+		score.Issues = append(score.Issues, IssueShort)
+	}
+	if score.Classes < 3 {
+		score.Issues = append(score.Issues, IssueClasses)
+	}
+	if isCommon(password) {
+		score.Issues = append(score.Issues, IssueCommon)
+	}
+	normalized := unleet(strings.ToLower(password))
+	if containsAny(normalized, userTokens(user)) {
+		score.Issues = append(score.Issues, IssueUser)
+	}
+	if containsAny(normalized, hostTokens(host)) {
+		score.Issues = append(score.Issues, IssueHost)
+	}
+	if score.Level < 3 {
+		score.Issues = append(score.Issues, IssueWeak)
+	}
+	return score
+}
+
+// Assess 评估每条凭据并按应用汇总
+func Assess(creds []Credential) StrengthReport {
+	var report StrengthReport
+	bySource := make(map[string]*Stats)
+	var sources []string
+
+	report.Total.Source = i18n.T("合计")
+	for _, c := range creds {
+		score := Evaluate(c.Password, c.User, c.Host)
+		report.Items = append(report.Items, Assessment{Credential: c, Score: score})
+
+		stats, ok := bySource[c.Source]
+		if !ok {
+			stats = &Stats{Source: c.Source}
+			bySource[c.Source] = stats
+			sources = append(sources, c.Source)
+		}
+		stats.add(score)
+		report.Total.add(score)
+	}
+
+	sort.Strings(sources)
+	for _, source := range sources {
+		report.Stats = append(report.Stats, bySource[source].finish())
+	}
+	report.Total = report.Total.finish()
+	return report
+}
+
+func (s *Stats) add(score Score) {
+	s.Total++
+	s.AvgLength += float64(score.Length)
+	s.Levels[score.Level]++
+	if score.Level <= 1 {
+		s.Weak++
+	}
+	if score.has(IssueCommon) {
+		s.Common++
+	}
+	if score.has(IssueUser) {
+		s.User++
+	}
+	if score.has(IssueHost) {
+		s.Host++
+	}
+	if score.Compliant() {
+		s.Compliant++
+	}
+}
+
+func (s *Stats) finish() Stats {
+	if s.Total > 0 {
+		s.AvgLength = math.Round(s.AvgLength/float64(s.Total)*10) / 10
+	}
+	return *s
+}
+
+func countClasses(runes []rune) int {
+	var lower, upper, digit, symbol int
+	for _, r := range runes {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// cardinality 暴力破解时每个字符的取值范围
+func cardinality(runes []rune) float64 {
+	var size float64
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < 128:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	for _, class := range []struct {
+		present bool
+		size    float64
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.present {
+			size += class.size
+		}
+	}
+	return math.Max(size, 10)
+}
+
+// levelFor 按zxcvbn的阈值把猜测次数转换为0-4的等级
+func levelFor(guesses float64) int {
+	switch {
+	case guesses < 1e3:
+		return 0
+	case guesses < 1e6:
+		return 1
+	case guesses < 1e8:
+		return 2
+	case guesses < 1e10:
+		return 3
+	}
+	return 4
+}
+
+var leetReplacer = strings.NewReplacer("@", "a", "4", "a", "0", "o", "1", "i", "!", "i", "3", "e", "$", "s", "5", "s", "7", "t", "+", "t")
+
+func unleet(s string) string {
+	return leetReplacer.Replace(s)
+}
+
+// isCommon 忽略大小写、常见的字符替换以及末尾追加的数字和符号
+func isCommon(password string) bool {
+	lower := strings.ToLower(password)
+	if _, ok := lookup(lower); ok {
+		return true
+	}
+	base := strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	if len(base) >= 4 && base != lower {
+		if _, ok := lookup(base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// genericHostTokens 不作为主机名特征的通用域名片段
+var genericHostTokens = map[string]bool{"www": true, "com": true, "net": true, "org": true, "local": true, "lan": true, "localhost": true}
+
+func userTokens(user string) []string {
+	user = strings.ToLower(user)
+	if i := strings.IndexAny(user, `@\`); i >= 0 {
+		// user@domain 和 DOMAIN\user 只比较用户名部分
+		if user[i] == '@' {
+			user = user[:i]
+		} else {
+			user = user[i+1:]
+		}
+	}
+	if len(user) < 3 {
+		return nil
+	}
+	return []string{user, reverse(user)}
+}
+
+func hostTokens(host string) []string {
+	var tokens []string
+	for _, part := range strings.FieldsFunc(strings.ToLower(host), func(r rune) bool { return r == '.' || r == '-' || r == '_' }) {
+		if len(part) >= 3 && !genericHostTokens[part] && strings.Trim(part, "0123456789") != "" {
+			tokens = append(tokens, part)
+		}
+	}
+	return tokens
+}
+
+func containsAny(s string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(s, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// keyboardRows 常见的键盘连续按键
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890", "!@#$%^&*()", "1qaz2wsx3edc4rfv5tgb", "zaq1xsw2cde3vfr4"}
+
+// estimateGuesses 参考zxcvbn的思路，从左到右把密码拆成常见密码、用户名/主机名、键盘序列、连续字符、
+// 重复字符和普通字符，分别估算猜测次数后相乘，结果不超过暴力破解的次数
+func estimateGuesses(password string, user, host []string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 1
+	}
+	bruteforce := math.Pow(cardinality(runes), float64(len(runes)))
+
+	if rank, ok := lookup(strings.ToLower(password)); ok {
+		return math.Min(variations(password, float64(rank)), bruteforce)
+	}
+
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	tokens := append(append([]string(nil), user...), host...)
+	total := 1.0
+	for i := 0; i < len(runes); {
+		length, guesses := matchAt(lower, runes, i, tokens)
+		total *= guesses
+		i += length
+	}
+	return math.Max(1, math.Min(total, bruteforce))
+}
+
+// lookup 在常见密码中查找，同时尝试还原字符替换后的形式
+func lookup(word string) (int, bool) {
+	if rank, ok := dictionary[word]; ok {
+		return rank, true
+	}
+	rank, ok := dictionary[unleet(word)]
+	return rank, ok
+}
+
+// variations 大小写和字符替换会让字典攻击多尝试几次
+func variations(password string, guesses float64) float64 {
+	lower := strings.ToLower(password)
+	if lower != password {
+		guesses *= 2
+	}
+	if _, ok := dictionary[lower]; !ok && unleet(lower) != lower {
+		guesses *= 2
+	}
+	return guesses
+}
+
+// matchAt 返回从位置i开始匹配到的长度和猜测次数，没有匹配到模式时按单个字符暴力破解计算，
+// lowerRunes 是逐字符转为小写的密码
+func matchAt(lowerRunes, runes []rune, i int, tokens []string) (int, float64) {
+	// 最长的字典词
+	for end := len(lowerRunes); end-i >= 4; end-- {
+		word := string(lowerRunes[i:end])
+		if rank, ok := lookup(word); ok {
+			return end - i, variations(string(runes[i:end]), float64(rank))
+		}
+		for _, token := range tokens {
+			if word == token || unleet(word) == token {
+				return end - i, variations(string(runes[i:end]), 10)
+			}
+		}
+	}
+
+	if n := repeatLength(lowerRunes, i); n >= 3 {
+		return n, cardinality(runes[i:i+1]) * float64(n)
+	}
+	if n := sequenceLength(lowerRunes, i); n >= 3 {
+		base := 26.0
+		if unicode.IsDigit(runes[i]) {
+			base = 10
+		}
+		return n, base * float64(n)
+	}
+	rest := string(lowerRunes[i:])
+	for _, row := range keyboardRows {
+		if n := commonPrefix(rest, row); n >= 4 {
+			return n, 50 * float64(n)
+		}
+	}
+	return 1, cardinality(runes)
+}
+
+func repeatLength(runes []rune, i int) int {
+	n := 1
+	for i+n < len(runes) && runes[i+n] == runes[i] {
+		n++
+	}
+	return n
+}
+
+func sequenceLength(runes []rune, i int) int {
+	if i+1 >= len(runes) {
+		return 1
+	}
+	step := runes[i+1] - runes[i]
+	if step != 1 && step != -1 {
+		return 1
+	}
+	n := 2
+	for i+n < len(runes) && runes[i+n]-runes[i+n-1] == step {
+		n++
+	}
+	return n
+}
+
+// commonPrefix s 的开头在键盘序列 row 中连续出现的长度
+func commonPrefix(s, row string) int {
+	best := 0
+	for start := 0; start < len(row); start++ {
+		n := 0
+		for n < len(s) && start+n < len(row) && s[n] == row[start+n] {
+			n++
+		}
+		if n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// String 文本格式的强度评估，先输出按应用的统计，再输出每条凭据的评分
+func (r StrengthReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("===== 密码强度评估 =====\n"))
+	sb.WriteString(i18n.T("策略: 至少8位、至少3种字符类型、不是常见密码、不包含用户名或主机名、强度不低于\"强\"\n\n"))
+
+	rows := [][]string{{i18n.T("应用"), i18n.T("数量"), i18n.T("平均长度"), i18n.T("弱密码"), i18n.T("常见密码"),
+		i18n.T("含用户名"), i18n.T("含主机名"), i18n.T("符合策略")}}
+	for _, s := range append(r.Stats, r.Total) {
+		rows = append(rows, []string{s.Source, fmt.Sprint(s.Total), fmt.Sprintf("%.1f", s.AvgLength), fmt.Sprint(s.Weak),
+			fmt.Sprint(s.Common), fmt.Sprint(s.User), fmt.Sprint(s.Host), fmt.Sprintf("%d/%d", s.Compliant, s.Total)})
+	}
+	sb.WriteString(status.FormatRows(rows))
+	sb.WriteString("\n")
+
+	rows = [][]string{{i18n.T("应用"), i18n.T("目标"), i18n.T("密码"), i18n.T("长度"), i18n.T("字符类型"),
+		i18n.T("强度"), i18n.T("猜测次数"), i18n.T("问题")}}
+	for _, item := range r.Items {
+		var issues []string
+		for _, issue := range item.Score.Issues {
+			issues = append(issues, issue.String())
+		}
+		rows = append(rows, []string{item.Credential.Source, item.Credential.Target(), Display(item.Credential.Password),
+			fmt.Sprint(item.Score.Length), fmt.Sprint(item.Score.Classes),
+			fmt.Sprintf("%d %s", item.Score.Level, LevelName(item.Score.Level)),
+			fmt.Sprintf("10^%.1f", item.Score.Guesses), strings.Join(issues, ", ")})
+	}
+	sb.WriteString(status.FormatRows(rows))
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,149 @@
+package credential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		guesses float64
+		level   int
+	}{
+		{1, 0},
+		{999, 0},
+		{1e3, 1},
+		{1e6 - 1, 1},
+		{1e6, 2},
+		{1e8 - 1, 2},
+		{1e8, 3},
+		{1e10 - 1, 3},
+		{1e10, 4},
+		{1e20, 4},
+	}
+	for _, tt := range tests {
+		if got := levelFor(tt.guesses); got != tt.level {
+			t.Errorf("levelFor(%g) = %d, want %d", tt.guesses, got, tt.level)
+		}
+	}
+}
+
+func TestIsCommon(t *testing.T) {
+	tests := map[string]bool{
+		"password":      true,
+		"PASSWORD":      true,
+		"p@ssw0rd":      true,
+		"P@ssw0rd!":     true,
+		"Password2024":  true,
+		"dragon!!":      true,
+		"admin123":      true,
+		"abc1":          false,
+		"zqxw1":         false,
+		"Tr0ub4dor&3xq": false,
+	}
+	for password, want := range tests {
+		if got := isCommon(password); got != want {
+			t.Errorf("isCommon(%q) = %v, want %v", password, got, want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		user     string
+		host     string
+		issues   []Issue
+	}{
+		{"common and short", "123456", "", "", []Issue{IssueShort, IssueClasses, IssueCommon, IssueWeak}},
+		{"common leet", "P@ssw0rd!", "", "", []Issue{IssueCommon, IssueWeak}},
+		{"contains user", "Deploy#2024!x", "CORP\\deploy", "", []Issue{IssueUser}},
+		{"contains reversed user", "yolped#2024!X", "deploy@corp.com", "", []Issue{IssueUser}},
+		{"contains host", "Zx!prod-Vault9q", "", "db-prod.example.com", []Issue{IssueHost}},
+		{"generic host parts ignored", "Zx!local-Vault9q", "", "www.local", nil},
+		{"strong", "correct-Horse7-battery!Q", "root", "10.0.0.1", nil},
+	}
+	for _, tt := range tests {
+		score := Evaluate(tt.password, tt.user, tt.host)
+		if !sameIssues(score.Issues, tt.issues) {
+			t.Errorf("%s: Evaluate(%q) issues = %v, want %v (score %+v)", tt.name, tt.password, score.Issues, tt.issues, score)
+		}
+		if score.Compliant() != (len(tt.issues) == 0) {
+			t.Errorf("%s: Compliant() = %v", tt.name, score.Compliant())
+		}
+	}
+
+	score := Evaluate("aB3$", "", "")
+	if score.Length != 4 || score.Classes != 4 || score.Level != 2 {
+		t.Errorf("Evaluate(aB3$) = %+v", score)
+	}
+	if score := Evaluate("", "", ""); score.Level != 0 || score.Guesses != 0 {
+		t.Errorf("Evaluate(\"\") = %+v", score)
+	}
+}
+
+func sameIssues(got, want []Issue) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAssess(t *testing.T) {
+	report := Assess([]Credential{
+		{Source: "winscp", Password: "123456"},
+		{Source: "navicat", Password: "correct-Horse7-battery!Q"},
+		{Source: "winscp", Password: "correct-Horse7-battery!Q"},
+	})
+	if len(report.Items) != 3 || len(report.Stats) != 2 {
+		t.Fatalf("Assess() = %+v", report)
+	}
+	if report.Stats[0].Source != "navicat" || report.Stats[1].Source != "winscp" {
+		t.Errorf("stats should be sorted by source: %+v", report.Stats)
+	}
+	winscp := report.Stats[1]
+	if winscp.Total != 2 || winscp.Weak != 1 || winscp.Common != 1 || winscp.Compliant != 1 || winscp.AvgLength != 15 {
+		t.Errorf("winscp stats = %+v", winscp)
+	}
+	if report.Total.Total != 3 || report.Total.Compliant != 2 || report.Total.Levels[0] != 1 {
+		t.Errorf("total stats = %+v", report.Total)
+	}
+}
+
+func TestLoadCommonPasswords(t *testing.T) {
+	if err := LoadCommonPasswords(""); err != nil {
+		t.Errorf("empty path: %v", err)
+	}
+	if err := LoadCommonPasswords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	const password = "Zebra-Giraffe-Quokka"
+	if isCommon(password) {
+		t.Fatalf("%q should not be common before loading", password)
+	}
+	path := filepath.Join(t.TempDir(), "common.txt")
+	if err := os.WriteFile(path, []byte("# 注释\n\n  zebra-giraffe-quokka  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadCommonPasswords(path); err != nil {
+		t.Fatal(err)
+	}
+	if !isCommon(password) || !isCommon(password+"2024") {
+		t.Errorf("%q should be common after loading", password)
+	}
+	if rank := dictionary["zebra-giraffe-quokka"]; rank != 1 {
+		t.Errorf("rank = %d, want 1", rank)
+	}
+	// 已有的常见密码保留更靠前的排名
+	if rank := dictionary["123456"]; rank != 1 {
+		t.Errorf("rank of 123456 = %d, want 1", rank)
+	}
+}
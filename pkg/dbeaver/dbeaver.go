@@ -1,14 +1,19 @@
 package dbeaver
 
 import (
+	"bufio"
 	"crypto/aes"
 	"crypto/cipher"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -17,10 +22,62 @@ const (
 	DefaultIVHex  = "00000000000000000000000000000000"
 )
 
+var ErrBadPadding = status.New(status.ErrDecryptFailed, "PKCS7填充校验失败，密钥不正确")
+
+type Project struct {
+	Name string
+	Dir  string
+}
+
+type Credential struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type DataSource struct {
+	ID           string
+	Name         string
+	SavePassword bool
+	Provider     string
+	Driver       string
+	Folder       string
+	Host         string
+	Port         string
+	Database     string
+	URL          string
+	AuthModel    string
+	User         string
+	Password     string
+	SourcesFile  string
+}
+
+type dataSourcesFile struct {
+	Connections map[string]struct {
+		Provider      string `json:"provider"`
+		Driver        string `json:"driver"`
+		Name          string `json:"name"`
+		Folder        string `json:"folder"`
+		SavePassword  bool   `json:"save-password"`
+		Configuration struct {
+			Host      string          `json:"host"`
+			Port      json.RawMessage `json:"port"`
+			Database  string          `json:"database"`
+			URL       string          `json:"url"`
+			AuthModel string          `json:"auth-model"`
+			User      string          `json:"user"`
+			Password  string          `json:"password"`
+		} `json:"configuration"`
+	} `json:"connections"`
+}
+
 func GetAppDataFolderPath() string {
 	return os.Getenv("APPDATA")
 }
 
+func GetDefaultWorkspacePath() string {
+	return filepath.Join(GetAppDataFolderPath(), "DBeaverData", "workspace6")
+}
+
 func GetDefaultConfigPaths() (string, string) {
 	appDataPath := GetAppDataFolderPath()
 	credentialsPath := filepath.Join(appDataPath, "DBeaverData", "workspace6", "General", ".dbeaver", "credentials-config.json")
@@ -28,107 +85,398 @@ func GetDefaultConfigPaths() (string, string) {
 	return credentialsPath, sourcesPath
 }
 
+// GetIniPaths 返回常见安装位置下的 dbeaver.ini
+func GetIniPaths() []string {
+	var paths []string
+	for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)", "LOCALAPPDATA"} {
+		dir := os.Getenv(env)
+		if dir == "" {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, "DBeaver", "dbeaver.ini"))
+	}
+	return paths
+}
+
+// ParseIniWorkspace 读取 dbeaver.ini 中 -data 参数指定的自定义工作区
+func ParseIniWorkspace(iniPath string) (string, error) {
+	return parseIniArgument(iniPath, "-data")
+}
+
+// parseIniArgument eclipse风格的ini中参数名和参数值各占一行
+func parseIniArgument(iniPath, name string) (string, error) {
+	file, err := os.Open(iniPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != name {
+			continue
+		}
+		if !scanner.Scan() {
+			break
+		}
+
+		value := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(value, "@user.home") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			value = filepath.Join(home, strings.TrimPrefix(value, "@user.home"))
+		}
+		return value, nil
+	}
+
+	return "", scanner.Err()
+}
+
+// GetWorkspacePaths 返回默认工作区以及 dbeaver.ini 中配置的工作区，已去重
+func GetWorkspacePaths() []string {
+	var workspaces []string
+	seen := make(map[string]bool)
+
+	add := func(path string) {
+		if path == "" {
+			return
+		}
+		key := strings.ToLower(filepath.Clean(path))
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		workspaces = append(workspaces, path)
+	}
+
+	add(GetDefaultWorkspacePath())
+	for _, iniPath := range GetIniPaths() {
+		workspace, err := ParseIniWorkspace(iniPath)
+		if err == nil {
+			add(workspace)
+		}
+	}
+
+	return workspaces
+}
+
+// FindProjects 枚举工作区下所有包含 .dbeaver 目录的项目，
+// 如果传入的本身就是项目目录则直接返回
+func FindProjects(workspace string) ([]Project, error) {
+	if _, err := os.Stat(filepath.Join(workspace, ".dbeaver")); err == nil {
+		return []Project{{Name: filepath.Base(workspace), Dir: workspace}}, nil
+	}
+
+	entries, err := ioutil.ReadDir(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(workspace, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, ".dbeaver")); err == nil {
+			projects = append(projects, Project{Name: entry.Name(), Dir: dir})
+		}
+	}
+
+	return projects, nil
+}
+
+// ProjectSourcesPaths 返回项目中的 data-sources.json 以及 data-sources-*.json
+func ProjectSourcesPaths(project Project) []string {
+	paths, _ := filepath.Glob(filepath.Join(project.Dir, ".dbeaver", "data-sources*.json"))
+	sort.Strings(paths)
+	return paths
+}
+
+func ProjectCredentialsPath(project Project) string {
+	return filepath.Join(project.Dir, ".dbeaver", "credentials-config.json")
+}
+
 func Decrypt(filePath, keyHex, ivHex string) (string, error) {
 	encryptedBytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return "", fmt.Errorf("读取文件失败: %v", err)
+		return "", i18n.Errorf("读取文件失败: %v", err)
 	}
 
 	key, err := hex.DecodeString(keyHex)
 	if err != nil {
-		return "", fmt.Errorf("解析密钥失败: %v", err)
+		return "", i18n.Errorf("解析密钥失败: %v", err)
 	}
 
 	iv, err := hex.DecodeString(ivHex)
 	if err != nil {
-		return "", fmt.Errorf("解析IV失败: %v", err)
+		return "", i18n.Errorf("解析IV失败: %v", err)
+	}
+
+	decrypted, err := DecryptBytes(encryptedBytes, key, iv)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// DecryptBytes AES-CBC解密，密钥和IV可能来自命令行参数，先校验长度，避免 cipher 包 panic
+func DecryptBytes(encryptedBytes, key, iv []byte) ([]byte, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, i18n.Errorf("密钥长度 %d 字节错误，应为16、24或32字节", len(key))
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, i18n.Errorf("IV长度 %d 字节错误，应为16字节", len(iv))
+	}
+	if len(encryptedBytes) == 0 || len(encryptedBytes)%aes.BlockSize != 0 {
+		return nil, i18n.Errorf("密文长度 %d 不是16的倍数", len(encryptedBytes))
 	}
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", fmt.Errorf("创建AES加密器失败: %v", err)
+		return nil, i18n.Errorf("创建AES加密器失败: %v", err)
 	}
 
 	mode := cipher.NewCBCDecrypter(block, iv)
 	decrypted := make([]byte, len(encryptedBytes))
 	mode.CryptBlocks(decrypted, encryptedBytes)
 
-	padding := int(decrypted[len(decrypted)-1])
-	if padding > 0 && padding <= aes.BlockSize {
-		decrypted = decrypted[:len(decrypted)-padding]
-	}
+	return pkcs7Unpad(decrypted)
+}
 
-	return string(decrypted), nil
+// pkcs7Unpad 校验每个填充字节，密钥不对时绝大多数情况会在这里失败
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return nil, ErrBadPadding
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, ErrBadPadding
+		}
+	}
+	return data[:len(data)-padding], nil
 }
 
-func MatchDataSource(json, jdbcKey string) string {
-	pattern := fmt.Sprintf(`"%s":\s*{[^}]+?"url":\s*"([^"]+)"[^}]+?}`, regexp.QuoteMeta(jdbcKey))
-	re := regexp.MustCompile(pattern)
-	match := re.FindStringSubmatch(json)
-	if len(match) > 1 {
-		return fmt.Sprintf("host: %s", match[1])
+// ParseCredentials 解析解密后的 credentials-config.json，
+// 结构为 {连接ID: {"#connection": {...}, "network/ssh_tunnel": {...}}}
+func ParseCredentials(config string) (map[string]map[string]Credential, error) {
+	// 文件前16字节是随机IV，用全零IV解密后这一块是乱码，JSON从第17字节开始
+	if len(config) > aes.BlockSize && config[aes.BlockSize] == '{' {
+		config = config[aes.BlockSize:]
+	} else if index := strings.Index(config, "{"); index > 0 {
+		config = config[index:]
+	}
+
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config), &raw); err != nil {
+		return nil, i18n.Errorf("解析凭据JSON失败: %v", err)
+	}
+
+	credentials := make(map[string]map[string]Credential)
+	for id, sections := range raw {
+		credentials[id] = make(map[string]Credential)
+		for section, value := range sections {
+			var credential Credential
+			if err := json.Unmarshal(value, &credential); err == nil {
+				credentials[id][section] = credential
+			}
+		}
 	}
-	return fmt.Sprintf("未找到匹配的连接: %s", jdbcKey)
+
+	return credentials, nil
 }
 
-func ConnectionInfo(config, sources string) (string, error) {
-	sourcesContent, err := ioutil.ReadFile(sources)
+func ParseDataSources(sourcesPath string) ([]DataSource, error) {
+	content, err := ioutil.ReadFile(sourcesPath)
 	if err != nil {
-		return "", fmt.Errorf("读取数据源文件失败: %v", err)
+		return nil, i18n.Errorf("读取数据源文件失败: %v", err)
+	}
+
+	var sources dataSourcesFile
+	if err := json.Unmarshal(content, &sources); err != nil {
+		return nil, i18n.Errorf("解析数据源JSON失败: %v", err)
 	}
 
-	pattern := `"(?P<key>[^"]+)"\s*:\s*{\s*"#connection"\s*:\s*{\s*"user"\s*:\s*"(?P<user>[^"]+)"\s*,\s*"password"\s*:\s*"(?P<password>[^"]+)"\s*}\s*}`
-	re := regexp.MustCompile(pattern)
-	matches := re.FindAllStringSubmatch(config, -1)
+	ids := make([]string, 0, len(sources.Connections))
+	for id := range sources.Connections {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var dataSources []DataSource
+	for _, id := range ids {
+		conn := sources.Connections[id]
+		dataSources = append(dataSources, DataSource{
+			ID:           id,
+			Name:         conn.Name,
+			SavePassword: conn.SavePassword,
+			Provider:     conn.Provider,
+			Driver:       conn.Driver,
+			Folder:       conn.Folder,
+			Host:         conn.Configuration.Host,
+			Port:         jsonScalar(conn.Configuration.Port),
+			Database:     conn.Configuration.Database,
+			URL:          conn.Configuration.URL,
+			AuthModel:    conn.Configuration.AuthModel,
+			User:         conn.Configuration.User,
+			Password:     conn.Configuration.Password,
+			SourcesFile:  sourcesPath,
+		})
+	}
 
+	return dataSources, nil
+}
+
+// jsonScalar 端口在不同版本里可能是字符串也可能是数字
+func jsonScalar(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func ConnectionInfo(projectName string, dataSources []DataSource, creds projectCredentials) string {
 	var result strings.Builder
-	for _, match := range matches {
-		if len(match) >= 4 {
-			key := match[1]
-			user := match[2]
-			password := match[3]
 
-			hostInfo := MatchDataSource(string(sourcesContent), key)
-			result.WriteString(hostInfo + "\n")
-			result.WriteString(fmt.Sprintf("username: %s\n", user))
-			result.WriteString(fmt.Sprintf("password: %s\n\n", password))
+	for _, ds := range dataSources {
+		user := ds.User
+		password := ds.Password
+		if saved, ok := creds.Credentials[ds.ID]["#connection"]; ok {
+			if saved.User != "" {
+				user = saved.User
+			}
+			if saved.Password != "" {
+				password = saved.Password
+			}
+		}
+
+		result.WriteString(i18n.Sprintf("项目: %s\n", projectName))
+		result.WriteString(i18n.Sprintf("连接名称: %s\n", ds.Name))
+		if ds.Folder != "" {
+			result.WriteString(i18n.Sprintf("文件夹: %s\n", ds.Folder))
+		}
+		result.WriteString(i18n.Sprintf("驱动: %s (%s)\n", ds.Driver, ds.Provider))
+		result.WriteString(i18n.Sprintf("主机: %s\n", ds.Host))
+		result.WriteString(i18n.Sprintf("端口: %s\n", ds.Port))
+		result.WriteString(i18n.Sprintf("数据库: %s\n", ds.Database))
+		if ds.URL != "" {
+			result.WriteString(fmt.Sprintf("URL: %s\n", ds.URL))
+		}
+		if ds.AuthModel != "" {
+			result.WriteString(i18n.Sprintf("认证方式: %s\n", ds.AuthModel))
+		}
+		result.WriteString(i18n.Sprintf("用户名: %s\n", user))
+		result.WriteString(i18n.Sprintf("密码: %s\n", password))
+		result.WriteString(i18n.Sprintf("状态: %s\n", ConnectionStatus(ds, password, creds)))
+		credential.Add(credential.Credential{Source: "DBeaver", Name: ds.Name, Host: ds.Host, Port: ds.Port, User: user, Password: password})
+
+		var sections []string
+		for section := range creds.Credentials[ds.ID] {
+			if section != "#connection" {
+				sections = append(sections, section)
+			}
+		}
+		sort.Strings(sections)
+		for _, section := range sections {
+			saved := creds.Credentials[ds.ID][section]
+			result.WriteString(i18n.Sprintf("%s 用户名: %s\n", section, saved.User))
+			result.WriteString(i18n.Sprintf("%s 密码: %s\n", section, saved.Password))
 		}
+		result.WriteString("\n")
 	}
 
-	return result.String(), nil
+	return result.String()
 }
 
-func ScanDBeaver(configPath, sourcesPath string) (string, error) {
+// scanProject 返回项目的解析结果和连接数量，凭据文件无法解密时同时返回结果和对应的错误
+func scanProject(project Project, configPath string, sourcesPaths []string) (string, int, error) {
+	creds := loadProjectCredentials(project, configPath)
+	count := 0
 
-	if configPath == "" || sourcesPath == "" {
-		defaultConfigPath, defaultSourcesPath := GetDefaultConfigPaths()
+	var result strings.Builder
+	if creds.Err != nil {
+		result.WriteString(i18n.Sprintf("项目 %s 凭据文件无法解密: %v\n", project.Name, creds.Err))
+	}
+
+	for _, sourcesPath := range sourcesPaths {
+		dataSources, err := ParseDataSources(sourcesPath)
+		if err != nil {
+			result.WriteString(i18n.Sprintf("解析 %s 失败: %v\n", sourcesPath, err))
+			continue
+		}
+		result.WriteString(ConnectionInfo(project.Name, dataSources, creds))
+		count += len(dataSources)
+	}
 
+	return result.String(), count, creds.Err
+}
+
+// ScanDBeaver 返回解析结果和连接数量，部分项目无法解密时同时返回结果和第一个错误
+func ScanDBeaver(configPath, sourcesPath, workspacePath string) (string, int, error) {
+
+	if configPath != "" || sourcesPath != "" {
 		if configPath == "" {
-			configPath = defaultConfigPath
+			configPath = filepath.Join(filepath.Dir(sourcesPath), "credentials-config.json")
 		}
 
 		if sourcesPath == "" {
-			sourcesPath = defaultSourcesPath
+			sourcesPath = filepath.Join(filepath.Dir(configPath), "data-sources.json")
 		}
-	}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("配置文件不存在: %s", configPath)
+		if _, err := os.Stat(sourcesPath); os.IsNotExist(err) {
+			return "", 0, status.Errorf(status.ErrNotFound, "数据源文件不存在: %s", sourcesPath)
+		}
+
+		project := Project{Name: filepath.Base(filepath.Dir(filepath.Dir(sourcesPath))), Dir: filepath.Dir(filepath.Dir(sourcesPath))}
+		return scanProject(project, configPath, []string{sourcesPath})
 	}
 
-	if _, err := os.Stat(sourcesPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("数据源文件不存在: %s", sourcesPath)
+	var workspaces []string
+	if workspacePath != "" {
+		workspaces = []string{workspacePath}
+	} else {
+		workspaces = GetWorkspacePaths()
 	}
 
-	decryptedConfig, err := Decrypt(configPath, DefaultKeyHex, DefaultIVHex)
-	if err != nil {
-		return "", fmt.Errorf("解密配置文件失败: %v", err)
+	var result strings.Builder
+	foundProject := false
+	count := 0
+	var firstErr error
+	for _, workspace := range workspaces {
+		projects, err := FindProjects(workspace)
+		if err != nil {
+			continue
+		}
+
+		for _, project := range projects {
+			sourcesPaths := ProjectSourcesPaths(project)
+			if len(sourcesPaths) == 0 {
+				continue
+			}
+			foundProject = true
+
+			projectResult, projectCount, err := scanProject(project, ProjectCredentialsPath(project), sourcesPaths)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			result.WriteString(projectResult)
+			count += projectCount
+		}
 	}
 
-	connectionInfo, err := ConnectionInfo(decryptedConfig, sourcesPath)
-	if err != nil {
-		return "", fmt.Errorf("解析连接信息失败: %v", err)
+	if !foundProject {
+		return "", 0, status.Errorf(status.ErrNotInstalled, "未找到DBeaver工作区: %s", strings.Join(workspaces, ", "))
 	}
 
-	return connectionInfo, nil
+	return result.String(), count, firstErr
 }
@@ -0,0 +1,79 @@
+package dbeaver
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InventoryDBeaver 统计工作区、项目和数据源数量，只检查凭据文件是否存在，不解密
+func InventoryDBeaver(configPath, sourcesPath, workspacePath string) (string, error) {
+	if configPath != "" || sourcesPath != "" {
+		if configPath == "" {
+			configPath = filepath.Join(filepath.Dir(sourcesPath), "credentials-config.json")
+		}
+		if sourcesPath == "" {
+			sourcesPath = filepath.Join(filepath.Dir(configPath), "data-sources.json")
+		}
+		if _, err := os.Stat(sourcesPath); os.IsNotExist(err) {
+			return "", status.Errorf(status.ErrNotFound, "数据源文件不存在: %s", sourcesPath)
+		}
+		return inventoryProject(filepath.Dir(sourcesPath), configPath, []string{sourcesPath}), nil
+	}
+
+	workspaces := []string{workspacePath}
+	if workspacePath == "" {
+		workspaces = GetWorkspacePaths()
+	}
+
+	var result strings.Builder
+	for _, workspace := range workspaces {
+		projects, err := FindProjects(workspace)
+		if err != nil {
+			continue
+		}
+		for _, project := range projects {
+			sourcesPaths := ProjectSourcesPaths(project)
+			if len(sourcesPaths) == 0 {
+				continue
+			}
+			result.WriteString(inventoryProject(project.Dir, ProjectCredentialsPath(project), sourcesPaths))
+		}
+	}
+
+	for _, path := range SecureStoragePaths() {
+		if _, err := os.Stat(path); err == nil {
+			result.WriteString(i18n.Sprintf("Eclipse安全存储: %s\n", path))
+		}
+	}
+
+	if result.Len() == 0 {
+		return "", status.Errorf(status.ErrNotInstalled, "未找到DBeaver工作区")
+	}
+	return result.String(), nil
+}
+
+func inventoryProject(location, configPath string, sourcesPaths []string) string {
+	connections, saved := 0, 0
+	for _, path := range sourcesPaths {
+		dataSources, err := ParseDataSources(path)
+		if err != nil {
+			continue
+		}
+		connections += len(dataSources)
+		for _, ds := range dataSources {
+			if ds.SavePassword {
+				saved++
+			}
+		}
+	}
+
+	credentials := i18n.T("不存在")
+	if _, err := os.Stat(configPath); err == nil {
+		credentials = i18n.T("存在")
+	}
+
+	return i18n.Sprintf("项目: %s\n  数据源: %d 个，保存密码: %d 个，credentials-config.json: %s\n", location, connections, saved, credentials)
+}
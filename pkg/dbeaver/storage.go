@@ -0,0 +1,133 @@
+package dbeaver
+
+import (
+	"e0e1-config/pkg/i18n"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	StatusDecrypted         = "已解密"
+	StatusPlaintext         = "data-sources.json中明文保存"
+	StatusNoSavedPassword   = "连接未勾选保存密码"
+	StatusProjectPassword   = "凭据文件无法用默认密钥解密，项目可能设置了密码"
+	StatusSecureStorage     = "密码保存在Eclipse安全存储(secure_storage)中，需要在目标用户会话中解密"
+	StatusCredentialManager = "密码保存在Windows凭据管理器中"
+	StatusMissing           = "凭据文件中没有该连接的密码"
+)
+
+var (
+	secureStoragePref     = regexp.MustCompile(`(?i)^[^=#]*secure[^=]*storage[^=]*=\s*true`)
+	credentialManagerPref = regexp.MustCompile(`(?i)^[^=#]*(credential[^=]*manager|win[^=]*credential)[^=]*=\s*true`)
+)
+
+// projectCredentials 保存一个项目的凭据解密结果以及检测到的其他存储方式
+type projectCredentials struct {
+	Credentials       map[string]map[string]Credential
+	Err               error
+	SecureStorage     bool
+	SecureStorageData string
+	CredentialManager bool
+}
+
+func loadProjectCredentials(project Project, configPath string) projectCredentials {
+	creds := projectCredentials{Credentials: make(map[string]map[string]Credential)}
+
+	if _, err := os.Stat(configPath); err == nil {
+		decryptedConfig, err := Decrypt(configPath, DefaultKeyHex, DefaultIVHex)
+		if err == nil {
+			creds.Credentials, err = ParseCredentials(decryptedConfig)
+		}
+		if err != nil {
+			creds.Credentials = make(map[string]map[string]Credential)
+			creds.Err = err
+		}
+	}
+
+	creds.SecureStorage, creds.CredentialManager = detectPreferences(filepath.Dir(project.Dir))
+	for _, path := range SecureStoragePaths() {
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			creds.SecureStorageData += string(data)
+		}
+	}
+
+	return creds
+}
+
+// ConnectionStatus 说明连接的密码是否恢复，未恢复时给出原因，返回翻译后的文本
+func ConnectionStatus(ds DataSource, password string, creds projectCredentials) string {
+	if credential, ok := creds.Credentials[ds.ID]["#connection"]; ok && credential.Password != "" {
+		return i18n.T(StatusDecrypted)
+	}
+	if password != "" {
+		return i18n.T(StatusPlaintext)
+	}
+	if creds.Err != nil {
+		return fmt.Sprintf("%s (%v)", i18n.T(StatusProjectPassword), creds.Err)
+	}
+	if !ds.SavePassword {
+		return i18n.T(StatusNoSavedPassword)
+	}
+	if creds.CredentialManager {
+		return i18n.T(StatusCredentialManager)
+	}
+	if creds.SecureStorage || inSecureStorage(creds.SecureStorageData, ds.ID) {
+		return i18n.T(StatusSecureStorage)
+	}
+	return i18n.T(StatusMissing)
+}
+
+// SecureStoragePaths 返回Eclipse安全存储文件，包括 dbeaver.ini 中 -eclipse.keyring 指定的位置
+func SecureStoragePaths() []string {
+	var paths []string
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".eclipse", "org.eclipse.equinox.security", "secure_storage"))
+	}
+	for _, iniPath := range GetIniPaths() {
+		keyring, err := parseIniArgument(iniPath, "-eclipse.keyring")
+		if err == nil && keyring != "" {
+			paths = append(paths, keyring)
+		}
+	}
+	return paths
+}
+
+// inSecureStorage secure_storage 是properties格式，节点路径中的特殊字符会被转义，
+// 所以同时匹配原始ID和转义后的形式
+func inSecureStorage(data, id string) bool {
+	if data == "" || id == "" {
+		return false
+	}
+	if strings.Contains(data, id) {
+		return true
+	}
+	return strings.Contains(data, strings.ReplaceAll(id, "-", "\\-"))
+}
+
+// detectPreferences 读取工作区 .metadata 下的偏好设置，判断是否启用了安全存储或Windows凭据管理器
+func detectPreferences(workspace string) (secureStorage bool, credentialManager bool) {
+	settingsDir := filepath.Join(workspace, ".metadata", ".plugins", "org.eclipse.core.runtime", ".settings")
+	prefs, _ := filepath.Glob(filepath.Join(settingsDir, "org.jkiss.dbeaver*.prefs"))
+
+	for _, pref := range prefs {
+		data, err := ioutil.ReadFile(pref)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if credentialManagerPref.MatchString(line) {
+				credentialManager = true
+			} else if secureStoragePref.MatchString(line) {
+				secureStorage = true
+			}
+		}
+	}
+
+	return secureStorage, credentialManager
+}
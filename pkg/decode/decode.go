@@ -0,0 +1,166 @@
+package decode
+
+import (
+	"crypto/aes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"e0e1-config/pkg/dbeaver"
+	"e0e1-config/pkg/filezilla"
+	"e0e1-config/pkg/finalshell"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/navicat"
+	"e0e1-config/pkg/winscp"
+	"e0e1-config/pkg/xshell"
+)
+
+// Types 支持解密的密文类型
+var Types = []string{"navicat", "winscp", "finalshell", "xshell", "filezilla", "dbeaver"}
+
+// Options 不同类型解密时需要的附加参数，用不到的字段会被忽略
+type Options struct {
+	Version        string
+	Host           string
+	User           string
+	SID            string
+	MasterPassword string
+	PubKey         string
+	Key            string
+	IV             string
+}
+
+type Result struct {
+	Type   string `json:"type"`
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+	Info   string `json:"info,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Decode 解密单个密文，失败时错误信息保存在 Result.Error 中
+func Decode(typ, value string, opts Options) Result {
+	result := Result{Type: typ, Input: value}
+
+	output, info, err := decode(strings.ToLower(typ), strings.TrimSpace(value), opts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = output
+	result.Info = info
+	return result
+}
+
+func decode(typ, value string, opts Options) (string, string, error) {
+	if value == "" {
+		return "", "", i18n.Errorf("密文为空")
+	}
+
+	switch typ {
+	case "navicat":
+		return decodeNavicat(value, opts)
+	case "winscp":
+		return decodeWinSCP(value, opts)
+	case "finalshell":
+		password, err := finalshell.DecodePass(value)
+		return password, "", err
+	case "xshell", "xftp":
+		password, strategy, err := xshell.DecryptValue(value, opts.Version, xshell.UserSID{Name: opts.User, SID: opts.SID}, opts.MasterPassword)
+		return password, i18n.Sprintf("密钥派生: %s", strategy), err
+	case "filezilla":
+		return decodeFileZilla(value, opts)
+	case "dbeaver":
+		return decodeDBeaver(value, opts)
+	}
+	return "", "", i18n.Errorf("不支持的类型: %s，可选: %s", typ, strings.Join(Types, ", "))
+}
+
+func decodeNavicat(value string, opts Options) (string, string, error) {
+	version := 0
+	if opts.Version != "" {
+		v, err := strconv.Atoi(opts.Version)
+		if err != nil {
+			return "", "", i18n.Errorf("Navicat版本号错误: %s", opts.Version)
+		}
+		version = v
+	}
+
+	password, detected, err := navicat.DecryptWithVersion(value, version)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(password), fmt.Sprintf("Navicat%d", detected), nil
+}
+
+func decodeWinSCP(value string, opts Options) (string, string, error) {
+	if winscp.IsMasterPasswordEncrypted(value) {
+		if opts.MasterPassword == "" {
+			return "", "", winscp.ErrMasterPasswordRequired
+		}
+		password, err := winscp.DecryptMasterPassword(value, opts.MasterPassword)
+		return password, i18n.T("主密码加密"), err
+	}
+
+	password := winscp.DecryptWinSCPPassword(opts.Host, opts.User, value)
+	if password == "" {
+		return "", "", i18n.Errorf("解密失败，请检查 -host 和 -user 是否与会话一致")
+	}
+	return password, "", nil
+}
+
+func decodeFileZilla(value string, opts Options) (string, string, error) {
+	if opts.PubKey != "" {
+		password, err := filezilla.DecryptCrypt(filezilla.PassElement{Value: value, Encoding: "crypt", PubKey: opts.PubKey}, opts.MasterPassword)
+		return password, i18n.T("主密码加密"), err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", "", i18n.Errorf("base64解码失败: %v", err)
+	}
+	return string(decoded), "base64", nil
+}
+
+// decodeDBeaver 密文为 credentials-config.json 内容的hex或base64编码
+func decodeDBeaver(value string, opts Options) (string, string, error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		data, err = base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", "", i18n.Errorf("密文需要是hex或base64编码")
+		}
+	}
+
+	keyHex, ivHex := dbeaver.DefaultKeyHex, dbeaver.DefaultIVHex
+	if opts.Key != "" {
+		keyHex = opts.Key
+	}
+	if opts.IV != "" {
+		ivHex = opts.IV
+	}
+
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return "", "", i18n.Errorf("解析密钥失败: %v", err)
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil {
+		return "", "", i18n.Errorf("解析IV失败: %v", err)
+	}
+
+	plain, err := dbeaver.DecryptBytes(data, key, iv)
+	if err != nil {
+		return "", "", err
+	}
+	// 与 dbeaver.ParseCredentials 相同，去掉全零IV解密出的前16字节乱码
+	text := string(plain)
+	if len(text) > aes.BlockSize && text[aes.BlockSize] == '{' {
+		text = text[aes.BlockSize:]
+	} else if index := strings.Index(text, "{"); index > 0 {
+		text = text[index:]
+	}
+	return text, "", nil
+}
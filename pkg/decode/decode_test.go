@@ -0,0 +1,70 @@
+package decode
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"e0e1-config/pkg/dbeaver"
+)
+
+func encryptDBeaver(t *testing.T, plain string) string {
+	t.Helper()
+	key, _ := hex.DecodeString(dbeaver.DefaultKeyHex)
+	iv, _ := hex.DecodeString(dbeaver.DefaultIVHex)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	data := append([]byte(plain), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return hex.EncodeToString(data)
+}
+
+func TestDecodeDBeaver(t *testing.T) {
+	// 第一块是随机IV，用全零IV解密后为乱码
+	value := encryptDBeaver(t, "0123456789abcdef"+`{"conn":{"#connection":{"user":"root"}}}`)
+
+	result := Decode("dbeaver", value, Options{})
+	if result.Error != "" || result.Output != `{"conn":{"#connection":{"user":"root"}}}` {
+		t.Fatalf("Decode() = %+v", result)
+	}
+}
+
+// 用户输入的密钥、IV和密文长度错误时应返回错误而不是 panic
+func TestDecodeInvalidInput(t *testing.T) {
+	block := "00112233445566778899aabbccddeeff"
+	tests := []struct {
+		name  string
+		typ   string
+		value string
+		opts  Options
+	}{
+		{"dbeaver short iv", "dbeaver", block, Options{IV: "0011"}},
+		{"dbeaver long iv", "dbeaver", block, Options{IV: block + "00"}},
+		{"dbeaver short key", "dbeaver", block, Options{Key: "0011"}},
+		{"dbeaver bad key hex", "dbeaver", block, Options{Key: "zz"}},
+		{"dbeaver partial block", "dbeaver", "001122", Options{}},
+		{"dbeaver not encoded", "dbeaver", "not hex!", Options{}},
+		{"winscp master short", "winscp", "A35D00", Options{MasterPassword: "x"}},
+		{"winscp master bad hex", "winscp", "A35DZZ", Options{MasterPassword: "x"}},
+		{"winscp odd", "winscp", "A35", Options{}},
+		{"navicat odd", "navicat", "abc", Options{Version: "12"}},
+		{"finalshell short", "finalshell", "AAAA", Options{}},
+		{"xshell short", "xshell", "AAAA", Options{}},
+		{"xshell master short", "xshell", "AAAA", Options{MasterPassword: "x"}},
+		{"filezilla bad pubkey", "filezilla", "AAAA", Options{PubKey: "AAAA", MasterPassword: "x"}},
+		{"unknown type", "foo", "x", Options{}},
+		{"empty value", "dbeaver", "", Options{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Decode(tt.typ, tt.value, tt.opts); result.Error == "" {
+				t.Errorf("Decode(%q, %q) = %+v, want error", tt.typ, tt.value, result)
+			}
+		})
+	}
+}
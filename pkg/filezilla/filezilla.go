@@ -1,61 +1,162 @@
 package filezilla
 
 import (
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
 	"encoding/base64"
 	"encoding/xml"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// Pass 的 encoding 属性可能是 base64、crypt，没有该属性时为明文
+type PassElement struct {
+	Value    string `xml:",chardata"`
+	Encoding string `xml:"encoding,attr"`
+	PubKey   string `xml:"pubkey,attr"`
+}
+
 type Server struct {
-	Host     string `xml:"Host"`
-	Port     string `xml:"Port"`
-	User     string `xml:"User"`
-	Pass     string `xml:"Pass"`
-	Protocol string `xml:"Protocol"`
-	Name     string `xml:"Name"`
+	Host      string      `xml:"Host"`
+	Port      string      `xml:"Port"`
+	User      string      `xml:"User"`
+	RawPass   PassElement `xml:"Pass"`
+	Account   string      `xml:"Account"`
+	Keyfile   string      `xml:"Keyfile"`
+	Logontype string      `xml:"Logontype"`
+	Protocol  string      `xml:"Protocol"`
+	Name      string      `xml:"Name"`
+	Comments  string      `xml:"Comments"`
+	Folder    string      `xml:"-"`
+	Recent    bool        `xml:"-"`
+	Pass      string      `xml:"-"`
 }
 
-type RecentServers struct {
-	XMLName xml.Name `xml:"RecentServers"`
-	Servers []Server `xml:"Server"`
+// Settings 为 filezilla.xml 中与凭据保存方式相关的设置
+type Settings struct {
+	MasterPasswordEncryptor string
+	KioskMode               string
 }
 
-type FileZilla3 struct {
-	XMLName xml.Name `xml:"FileZilla3"`
-	Servers struct {
-		Servers []Server `xml:"Server"`
-	} `xml:"Servers"`
+// protocols 对应FileZilla源码中的 ServerProtocol 枚举
+var protocols = map[string]string{
+	"0":  "FTP",
+	"1":  "SFTP",
+	"2":  "HTTP",
+	"3":  "FTPS(隐式)",
+	"4":  "FTPES(显式)",
+	"5":  "HTTPS",
+	"6":  "FTP(不加密)",
+	"7":  "S3",
+	"8":  "Storj",
+	"9":  "WebDAV",
+	"10": "Azure File",
+	"11": "Azure Blob",
+	"12": "OpenStack Swift",
+	"13": "Google Cloud Storage",
+	"14": "Google Drive",
+	"15": "Dropbox",
+	"16": "OneDrive",
+	"17": "Backblaze B2",
+	"18": "Box",
+	"19": "WebDAV(不加密)",
+	"20": "Rackspace",
+	"21": "Storj(Access Grant)",
 }
 
-func ScanFileZilla(customPath string) (string, error) {
-	var fzPath string
-	var result strings.Builder
+var logonTypes = map[string]string{
+	"0": "匿名",
+	"1": "普通",
+	"2": "询问密码",
+	"3": "交互式",
+	"4": "账户",
+	"5": "密钥文件",
+	"6": "配置文件",
+}
 
-	if customPath == "" {
+func protocolName(code string) string {
+	if name, ok := protocols[code]; ok {
+		return i18n.T(name)
+	}
+	return i18n.Sprintf("未知(%s)", code)
+}
 
-		appData, err := os.UserConfigDir()
+func logonTypeName(code string) string {
+	if name, ok := logonTypes[code]; ok {
+		return i18n.T(name)
+	}
+	return i18n.Sprintf("未知(%s)", code)
+}
+
+// decodePass 按每个Pass元素自身的encoding解码，无法解码时返回错误信息作为显示内容
+func decodePass(pass PassElement) (string, error) {
+	switch pass.Encoding {
+	case "":
+		return pass.Value, nil
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(pass.Value))
 		if err != nil {
-			return "", fmt.Errorf("获取用户配置目录失败: %v", err)
+			return i18n.Sprintf("[-] base64解码失败: %v", err), status.Errorf(status.ErrDecryptFailed, "base64解码失败: %v", err)
 		}
-		fzPath = filepath.Join(appData, "FileZilla")
-	} else {
-		fzPath = customPath
+		return string(decoded), nil
+	case "crypt":
+		return decryptProtected(pass)
+	}
+	return i18n.Sprintf("%s (编码: %s)", pass.Value, pass.Encoding), nil
+}
+
+// configDir 返回FileZilla配置目录，未指定时使用 %APPDATA%\FileZilla
+func configDir(customPath string) (string, error) {
+	if customPath != "" {
+		return customPath, nil
+	}
+
+	appData, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.Errorf("获取用户配置目录失败: %v", err)
+	}
+	return filepath.Join(appData, "FileZilla"), nil
+}
+
+// ScanFileZilla 返回解析结果和站点数量，部分密码无法解密时同时返回结果和对应的错误
+func ScanFileZilla(customPath string) (string, int, error) {
+	var result strings.Builder
+
+	fzPath, err := configDir(customPath)
+	if err != nil {
+		return "", 0, err
 	}
 
 	if _, err := os.Stat(fzPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("FileZilla 目录不存在: %s", fzPath)
+		return "", 0, status.Errorf(status.ErrNotInstalled, "FileZilla 目录不存在: %s", fzPath)
+	}
+
+	settings := readSettings(filepath.Join(fzPath, "filezilla.xml"))
+	if settings.MasterPasswordEncryptor != "" {
+		switch {
+		case MasterPassword == "":
+			result.WriteString(i18n.T("主密码: 已启用，保存的密码使用主密码加密，可使用 -filezilla-master-password 指定主密码解密\n"))
+		case VerifyMasterPassword(settings.MasterPasswordEncryptor, MasterPassword):
+			result.WriteString(i18n.T("主密码: 已启用，提供的主密码校验通过\n"))
+		default:
+			result.WriteString(i18n.T("主密码: 已启用，提供的主密码校验失败\n"))
+		}
+	}
+	if settings.KioskMode != "" && settings.KioskMode != "0" {
+		result.WriteString(i18n.Sprintf("Kiosk模式: %s，FileZilla不会保存密码\n", settings.KioskMode))
 	}
 
 	xmlFiles, err := findXMLFiles(fzPath)
 	if err != nil {
-		return "", fmt.Errorf("查找 XML 文件失败: %v", err)
+		return "", 0, i18n.Errorf("查找 XML 文件失败: %v", err)
 	}
 
-	foundServers := false
+	count := 0
+	var passErr error
 
 	for _, xmlFile := range xmlFiles {
 		servers, err := parseFileZillaXML(xmlFile)
@@ -64,36 +165,70 @@ func ScanFileZilla(customPath string) (string, error) {
 		}
 
 		if len(servers) > 0 {
-			foundServers = true
-			result.WriteString(fmt.Sprintf("从文件解析: %s\n", xmlFile))
+			result.WriteString(i18n.Sprintf("从文件解析: %s\n", xmlFile))
 
 			for _, server := range servers {
-				if server.Host != "" && server.Port != "" && server.User != "" && server.Pass != "" {
-					if server.Name != "" {
-						result.WriteString(fmt.Sprintf("名称: %s\n", server.Name))
-					}
-					result.WriteString(fmt.Sprintf("主机: %s\n", server.Host))
-					result.WriteString(fmt.Sprintf("端口: %s\n", server.Port))
-					result.WriteString(fmt.Sprintf("用户: %s\n", server.User))
-					result.WriteString(fmt.Sprintf("密码: %s\n", server.Pass))
-					if server.Protocol != "" {
-						protocol := "FTP"
-						if server.Protocol == "1" {
-							protocol = "SFTP"
-						}
-						result.WriteString(fmt.Sprintf("协议: %s\n", protocol))
-					}
-					result.WriteString("\n")
+				if server.Host == "" {
+					continue
+				}
+				server.Pass, err = decodePass(server.RawPass)
+				if err != nil && passErr == nil {
+					passErr = err
 				}
+				if err == nil {
+					credential.Add(credential.Credential{Source: "FileZilla", Name: server.Name, Host: server.Host, Port: server.Port, User: server.User, Password: server.Pass})
+				}
+				result.WriteString(formatServer(server))
+				count++
 			}
 		}
 	}
 
-	if !foundServers {
-		return "", fmt.Errorf("未找到有效的 FileZilla 服务器配置")
+	if count == 0 {
+		return "", 0, status.Errorf(status.ErrNotFound, "未找到有效的 FileZilla 服务器配置")
+	}
+
+	return result.String(), count, passErr
+}
+
+func formatServer(server Server) string {
+	var result strings.Builder
+
+	if server.Name != "" {
+		result.WriteString(i18n.Sprintf("名称: %s\n", server.Name))
+	}
+	if server.Folder != "" {
+		result.WriteString(i18n.Sprintf("文件夹: %s\n", server.Folder))
+	}
+	if server.Recent {
+		result.WriteString(i18n.T("来源: 最近连接\n"))
+	}
+	result.WriteString(i18n.Sprintf("主机: %s\n", server.Host))
+	result.WriteString(i18n.Sprintf("端口: %s\n", server.Port))
+	if server.Protocol != "" {
+		result.WriteString(i18n.Sprintf("协议: %s\n", protocolName(server.Protocol)))
+	}
+	if server.Logontype != "" {
+		result.WriteString(i18n.Sprintf("登录类型: %s\n", logonTypeName(server.Logontype)))
 	}
+	result.WriteString(i18n.Sprintf("用户: %s\n", server.User))
+	if server.RawPass.Value != "" {
+		result.WriteString(i18n.Sprintf("密码: %s\n", server.Pass))
+	} else {
+		result.WriteString(i18n.T("密码: 未保存\n"))
+	}
+	if server.Account != "" {
+		result.WriteString(i18n.Sprintf("账户: %s\n", server.Account))
+	}
+	if server.Keyfile != "" {
+		result.WriteString(i18n.Sprintf("密钥文件: %s\n", server.Keyfile))
+	}
+	if server.Comments != "" {
+		result.WriteString(i18n.Sprintf("备注: %s\n", server.Comments))
+	}
+	result.WriteString("\n")
 
-	return result.String(), nil
+	return result.String()
 }
 
 func findXMLFiles(dir string) ([]string, error) {
@@ -112,47 +247,96 @@ func findXMLFiles(dir string) ([]string, error) {
 	return xmlFiles, err
 }
 
+// parseFileZillaXML 同时支持 sitemanager.xml 和 recentservers.xml，
+// Folder 元素的文本内容是文件夹名称，其中可以嵌套 Server 和 Folder
 func parseFileZillaXML(filePath string) ([]Server, error) {
 	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	var fileZilla3 FileZilla3
-	err1 := xml.Unmarshal(data, &fileZilla3)
-	if err1 == nil && len(fileZilla3.Servers.Servers) > 0 {
+	var servers []Server
+	var folders []string
+	var namedFolder []bool
+	recent := false
 
-		for i := range fileZilla3.Servers.Servers {
-			if fileZilla3.Servers.Servers[i].Pass != "" {
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.Errorf("XML解析错误: %v", err)
+		}
 
-				if strings.Contains(string(data), `encoding="base64"`) {
-					decodedPass, err := base64.StdEncoding.DecodeString(fileZilla3.Servers.Servers[i].Pass)
-					if err == nil {
-						fileZilla3.Servers.Servers[i].Pass = string(decodedPass)
-					}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "RecentServers":
+				recent = true
+			case "Folder":
+				folders = append(folders, "")
+				namedFolder = append(namedFolder, false)
+			case "Server":
+				var server Server
+				if err := decoder.DecodeElement(&server, &t); err != nil {
+					return nil, i18n.Errorf("XML解析错误: %v", err)
+				}
+				server.Host = strings.TrimSpace(server.Host)
+				server.Folder = strings.Join(folders, "/")
+				server.Recent = recent
+				servers = append(servers, server)
+			}
+		case xml.CharData:
+			if n := len(folders); n > 0 && !namedFolder[n-1] {
+				if name := strings.TrimSpace(string(t)); name != "" {
+					folders[n-1] = name
+					namedFolder[n-1] = true
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "RecentServers":
+				recent = false
+			case "Folder":
+				if n := len(folders); n > 0 {
+					folders = folders[:n-1]
+					namedFolder = namedFolder[:n-1]
 				}
 			}
 		}
-		return fileZilla3.Servers.Servers, nil
 	}
 
-	var recentServers RecentServers
-	err2 := xml.Unmarshal(data, &recentServers)
-	if err2 == nil && len(recentServers.Servers) > 0 {
+	return servers, nil
+}
 
-		for i := range recentServers.Servers {
-			if recentServers.Servers[i].Pass != "" {
+func readSettings(filePath string) Settings {
+	var settings Settings
 
-				if strings.Contains(string(data), `encoding="base64"`) {
-					decodedPass, err := base64.StdEncoding.DecodeString(recentServers.Servers[i].Pass)
-					if err == nil {
-						recentServers.Servers[i].Pass = string(decodedPass)
-					}
-				}
-			}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return settings
+	}
+
+	var config struct {
+		Settings []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Settings>Setting"`
+	}
+	if err := xml.Unmarshal(data, &config); err != nil {
+		return settings
+	}
+
+	for _, setting := range config.Settings {
+		switch setting.Name {
+		case "Master password encryptor":
+			settings.MasterPasswordEncryptor = strings.TrimSpace(setting.Value)
+		case "Kiosk mode":
+			settings.KioskMode = strings.TrimSpace(setting.Value)
 		}
-		return recentServers.Servers, nil
 	}
 
-	return nil, fmt.Errorf("无法解析 XML 文件: %v 或 %v", err1, err2)
+	return settings
 }
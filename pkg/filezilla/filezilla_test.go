@@ -0,0 +1,134 @@
+package filezilla
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseFileZillaXML(t *testing.T) {
+	dir := t.TempDir()
+	siteManager := writeFile(t, dir, "sitemanager.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<FileZilla3>
+  <Servers>
+    <Folder expanded="1">Prod
+      <Server>
+        <Host> ftp.example.com </Host>
+        <Port>21</Port>
+        <Protocol>0</Protocol>
+        <Logontype>1</Logontype>
+        <User>admin</User>
+        <Pass encoding="base64">UEBzc3cwcmQ=</Pass>
+        <Name>prod ftp</Name>
+      </Server>
+      <Folder>DB
+        <Server>
+          <Host>sftp.example.com</Host>
+          <Port>22</Port>
+          <Protocol>1</Protocol>
+          <Logontype>5</Logontype>
+          <User>deploy</User>
+          <Keyfile>C:\keys\deploy.ppk</Keyfile>
+        </Server>
+      </Folder>
+    </Folder>
+    <Server>
+      <Host>plain.example.com</Host>
+      <User>u</User>
+      <Pass>plaintext</Pass>
+    </Server>
+  </Servers>
+</FileZilla3>`)
+	recent := writeFile(t, dir, "recentservers.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<FileZilla3>
+  <RecentServers>
+    <Server><Host>10.0.0.1</Host><Port>21</Port><User>r</User><Pass encoding="base64">cmVjZW50</Pass></Server>
+  </RecentServers>
+</FileZilla3>`)
+
+	servers, err := parseFileZillaXML(siteManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		host, folder, user, pass string
+	}{
+		{"ftp.example.com", "Prod", "admin", "P@ssw0rd"},
+		{"sftp.example.com", "Prod/DB", "deploy", ""},
+		{"plain.example.com", "", "u", "plaintext"},
+	}
+	if len(servers) != len(want) {
+		t.Fatalf("got %d servers, want %d: %+v", len(servers), len(want), servers)
+	}
+	for i, w := range want {
+		s := servers[i]
+		pass, err := decodePass(s.RawPass)
+		if err != nil {
+			t.Errorf("server %d: decodePass error: %v", i, err)
+		}
+		if s.Host != w.host || s.Folder != w.folder || s.User != w.user || pass != w.pass || s.Recent {
+			t.Errorf("server %d = %+v (pass %q), want %+v", i, s, pass, w)
+		}
+	}
+	if servers[1].Keyfile != `C:\keys\deploy.ppk` || servers[1].Logontype != "5" {
+		t.Errorf("key file server = %+v", servers[1])
+	}
+
+	servers, err = parseFileZillaXML(recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 1 || !servers[0].Recent || servers[0].Host != "10.0.0.1" {
+		t.Errorf("recent servers = %+v", servers)
+	}
+
+	broken := writeFile(t, dir, "broken.xml", `<FileZilla3><Servers><Server><Host>x</Servers>`)
+	if _, err := parseFileZillaXML(broken); err == nil {
+		t.Error("expected error for malformed XML")
+	}
+}
+
+func TestDecodePass(t *testing.T) {
+	tests := []struct {
+		name    string
+		pass    PassElement
+		want    string
+		wantErr bool
+	}{
+		{"plain", PassElement{Value: "secret"}, "secret", false},
+		{"base64", PassElement{Value: " c2VjcmV0 ", Encoding: "base64"}, "secret", false},
+		{"bad base64", PassElement{Value: "!!!", Encoding: "base64"}, "", true},
+		{"crypt without master password", PassElement{Value: "AAAA", Encoding: "crypt", PubKey: "AAAA"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := decodePass(tt.pass)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("%s: decodePass() = %q, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestReadSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "filezilla.xml", `<FileZilla3><Settings>
+  <Setting name="Master password encryptor">abc</Setting>
+  <Setting name="Kiosk mode">1</Setting>
+</Settings></FileZilla3>`)
+
+	settings := readSettings(path)
+	if settings.MasterPasswordEncryptor != "abc" || settings.KioskMode != "1" {
+		t.Errorf("readSettings() = %+v", settings)
+	}
+	if settings := readSettings(filepath.Join(dir, "missing.xml")); settings != (Settings{}) {
+		t.Errorf("missing file: readSettings() = %+v", settings)
+	}
+}
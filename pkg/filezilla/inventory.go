@@ -0,0 +1,55 @@
+package filezilla
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InventoryFileZilla 统计各XML文件中的站点数量以及密码的保存方式，不解码密码
+func InventoryFileZilla(customPath string) (string, error) {
+	var result strings.Builder
+
+	fzPath, err := configDir(customPath)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(fzPath); os.IsNotExist(err) {
+		return "", status.Errorf(status.ErrNotInstalled, "FileZilla 目录不存在: %s", fzPath)
+	}
+
+	result.WriteString(i18n.Sprintf("配置目录: %s\n", fzPath))
+	settings := readSettings(filepath.Join(fzPath, "filezilla.xml"))
+	if settings.MasterPasswordEncryptor != "" {
+		result.WriteString(i18n.T("  主密码: 已启用\n"))
+	}
+
+	xmlFiles, err := findXMLFiles(fzPath)
+	if err != nil {
+		return "", i18n.Errorf("查找 XML 文件失败: %v", err)
+	}
+
+	for _, xmlFile := range xmlFiles {
+		servers, err := parseFileZillaXML(xmlFile)
+		if err != nil || len(servers) == 0 {
+			continue
+		}
+
+		saved, protected := 0, 0
+		for _, server := range servers {
+			if server.RawPass.Value == "" {
+				continue
+			}
+			saved++
+			if server.RawPass.Encoding == "crypt" {
+				protected++
+			}
+		}
+		result.WriteString(i18n.Sprintf("  %s: 站点 %d 个，保存密码 %d 个，主密码保护 %d 个\n", xmlFile, len(servers), saved, protected))
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,143 @@
+package filezilla
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 启用主密码后 FileZilla 使用 libfilezilla 的 fz::encrypt：
+// 私钥为 PBKDF2-SHA256(主密码, salt) 得到的 X25519 私钥，公钥为 key(32) + salt(32)，
+// 密文为 临时公钥(32) + 临时salt(32) + AES-256-GCM密文 + tag(16)
+const (
+	keySize          = 32
+	saltSize         = 32
+	tagSize          = 16
+	pbkdf2Iterations = 100000
+)
+
+var (
+	ErrMasterPasswordRequired = status.New(status.ErrProtectedByMasterPassword, "密码受主密码保护，需要使用 -filezilla-master-password 指定")
+	ErrMasterPasswordWrong    = status.New(status.ErrDecryptFailed, "主密码错误")
+)
+
+var MasterPassword string
+
+func SetMasterPassword(password string) {
+	MasterPassword = password
+}
+
+type publicKey struct {
+	Key  []byte
+	Salt []byte
+}
+
+func parsePublicKey(encoded string) (publicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return publicKey{}, i18n.Errorf("公钥base64解码失败: %v", err)
+	}
+	if len(data) != keySize+saltSize {
+		return publicKey{}, i18n.Errorf("公钥长度错误: %d", len(data))
+	}
+	return publicKey{Key: data[:keySize], Salt: data[keySize:]}, nil
+}
+
+// privateKeyFromPassword 对应 fz::private_key::from_password
+func privateKeyFromPassword(password string, salt []byte) []byte {
+	key := pbkdf2.Key([]byte(password), salt, pbkdf2Iterations, keySize, sha256.New)
+	key[0] &= 248
+	key[31] &= 127
+	key[31] |= 64
+	return key
+}
+
+// VerifyMasterPassword 用主密码派生公钥，与 filezilla.xml 中的 Master password encryptor 比较
+func VerifyMasterPassword(encryptor, password string) bool {
+	pub, err := parsePublicKey(encryptor)
+	if err != nil {
+		return false
+	}
+
+	derived, err := curve25519.X25519(privateKeyFromPassword(password, pub.Salt), curve25519.Basepoint)
+	return err == nil && bytes.Equal(derived, pub.Key)
+}
+
+func deriveHash(ephemeral, pub publicKey, secret []byte, label byte) []byte {
+	h := sha256.New()
+	h.Write(ephemeral.Salt)
+	h.Write([]byte{label})
+	h.Write(secret)
+	h.Write(ephemeral.Key)
+	h.Write(pub.Key)
+	h.Write(pub.Salt)
+	return h.Sum(nil)
+}
+
+// DecryptCrypt 解密 encoding="crypt" 的 Pass 元素
+func DecryptCrypt(pass PassElement, password string) (string, error) {
+	if password == "" {
+		return "", ErrMasterPasswordRequired
+	}
+
+	pub, err := parsePublicKey(pass.PubKey)
+	if err != nil {
+		return "", err
+	}
+
+	private := privateKeyFromPassword(password, pub.Salt)
+	derived, err := curve25519.X25519(private, curve25519.Basepoint)
+	if err != nil || !bytes.Equal(derived, pub.Key) {
+		return "", ErrMasterPasswordWrong
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(pass.Value))
+	if err != nil {
+		return "", status.Errorf(status.ErrDecryptFailed, "base64解码失败: %v", err)
+	}
+	if len(data) < keySize+saltSize+tagSize {
+		return "", status.Errorf(status.ErrDecryptFailed, "密文长度不足")
+	}
+
+	ephemeral := publicKey{Key: data[:keySize], Salt: data[keySize : keySize+saltSize]}
+	secret, err := curve25519.X25519(private, ephemeral.Key)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(deriveHash(ephemeral, pub, secret, 0))
+	if err != nil {
+		return "", err
+	}
+	nonce := deriveHash(ephemeral, pub, secret, 2)
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(nonce))
+	if err != nil {
+		return "", err
+	}
+
+	plain, err := gcm.Open(nil, nonce, data[keySize+saltSize:], nil)
+	if err != nil {
+		return "", status.Errorf(status.ErrDecryptFailed, "解密失败: %v", err)
+	}
+
+	// FileZilla 加密前会用 \0 填充密码以隐藏长度
+	return strings.TrimRight(string(plain), "\x00"), nil
+}
+
+// decryptProtected 生成受主密码保护的密码在报告中的显示内容
+func decryptProtected(pass PassElement) (string, error) {
+	password, err := DecryptCrypt(pass, MasterPassword)
+	if err != nil {
+		return i18n.Sprintf("[主密码保护] %v", err), err
+	}
+	return password, nil
+}
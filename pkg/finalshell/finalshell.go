@@ -3,6 +3,10 @@ package finalshell
 import (
 	"crypto/des"
 	"crypto/md5"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/status"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
@@ -122,71 +126,280 @@ func DecodePass(data string) (string, error) {
 }
 
 type Connection struct {
-	Host     string `json:"host"`
-	Username string `json:"user_name"`
-	Password string `json:"password"`
+	ID                 string          `json:"id"`
+	Name               string          `json:"name"`
+	Host               string          `json:"host"`
+	Port               json.RawMessage `json:"port"`
+	Username           string          `json:"user_name"`
+	Password           string          `json:"password"`
+	SavePassword       bool            `json:"save_password"`
+	ConnectionType     int             `json:"conection_type"`
+	AuthenticationType int             `json:"authentication_type"`
+	SecretKeyID        string          `json:"secret_key_id"`
+	FolderID           string          `json:"folder_id"`
+	ProxyID            string          `json:"proxy_id"`
+	Description        string          `json:"description"`
 }
 
-func ScanFinalShell(customPath string) (string, error) {
-	var connPath string
-	if customPath != "" {
+type Folder struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id"`
+}
+
+var connectionTypes = map[int]string{
+	100: "SSH",
+	101: "RDP",
+}
+
+var authenticationTypes = map[int]string{
+	1: "密码",
+	2: "公钥",
+}
+
+func jsonScalar(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func connectionType(t int) string {
+	if name, ok := connectionTypes[t]; ok {
+		return name
+	}
+	return i18n.Sprintf("未知(%d)", t)
+}
+
+func authenticationType(t int) string {
+	if name, ok := authenticationTypes[t]; ok {
+		return i18n.T(name)
+	}
+	return i18n.Sprintf("未知(%d)", t)
+}
+
+// folderPath 沿着 parent_id 向上拼出完整的文件夹路径
+func folderPath(folders map[string]Folder, id string) string {
+	var path []string
+	seen := make(map[string]bool)
+	for id != "" && !seen[id] {
+		seen[id] = true
+		folder, ok := folders[id]
+		if !ok {
+			break
+		}
+		path = append([]string{folder.Name}, path...)
+		id = folder.ParentID
+	}
+	return strings.Join(path, "/")
+}
 
-		connPath = customPath
+// loadConfigObjects FinalShell 的 config.json 中保存了代理和私钥列表，
+// 这里不依赖具体结构，按 id 收集所有对象供连接引用
+func loadConfigObjects(configPath string) map[string]map[string]interface{} {
+	objects := make(map[string]map[string]interface{})
 
-		if !strings.HasSuffix(strings.ToLower(connPath), "conn") {
-			connPath = filepath.Join(connPath, "conn")
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return objects
+	}
+
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return objects
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			if id, ok := node["id"].(string); ok && id != "" {
+				objects[id] = node
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range node {
+				walk(child)
+			}
 		}
-	} else {
+	}
+	walk(root)
+
+	return objects
+}
+
+func objectString(object map[string]interface{}, key string) string {
+	switch v := object[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%v", v)
+	case bool:
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+func formatConnection(conn Connection, folders map[string]Folder, configObjects map[string]map[string]interface{}) string {
+	var result strings.Builder
 
-		home, err := os.UserHomeDir()
+	result.WriteString(i18n.Sprintf("名称: %s\n", conn.Name))
+	if path := folderPath(folders, conn.FolderID); path != "" {
+		result.WriteString(i18n.Sprintf("文件夹: %s\n", path))
+	}
+	result.WriteString(i18n.Sprintf("类型: %s\n", connectionType(conn.ConnectionType)))
+	result.WriteString(i18n.Sprintf("主机: %s\n", conn.Host))
+	result.WriteString(i18n.Sprintf("端口: %s\n", jsonScalar(conn.Port)))
+	result.WriteString(i18n.Sprintf("用户名: %s\n", conn.Username))
+	result.WriteString(i18n.Sprintf("认证方式: %s\n", authenticationType(conn.AuthenticationType)))
+
+	if conn.Password != "" {
+		password, err := DecodePass(conn.Password)
 		if err != nil {
-			return "", fmt.Errorf("获取用户目录失败: %v", err)
+			result.WriteString(i18n.Sprintf("密码: 解密失败(%v)\n", err))
+		} else {
+			result.WriteString(i18n.Sprintf("密码: %s\n", password))
+			credential.Add(credential.Credential{Source: "FinalShell", Name: conn.Name, Host: conn.Host, Port: jsonScalar(conn.Port), User: conn.Username, Password: password})
 		}
-		connPath = filepath.Join(home, "AppData", "Local", "finalshell", "conn")
+	} else if !conn.SavePassword {
+		result.WriteString(i18n.T("密码: 未保存\n"))
+	}
+
+	if conn.SecretKeyID != "" {
+		keyName := conn.SecretKeyID
+		if key, ok := configObjects[conn.SecretKeyID]; ok && objectString(key, "name") != "" {
+			keyName = fmt.Sprintf("%s (%s)", objectString(key, "name"), conn.SecretKeyID)
+		}
+		result.WriteString(i18n.Sprintf("私钥: %s\n", keyName))
+	}
+
+	if conn.ProxyID != "" {
+		if proxy, ok := configObjects[conn.ProxyID]; ok {
+			result.WriteString(i18n.Sprintf("代理: %s %s:%s",
+				objectString(proxy, "type"), objectString(proxy, "host"), objectString(proxy, "port")))
+			if user := objectString(proxy, "user_name"); user != "" {
+				result.WriteString(i18n.Sprintf(" 用户名: %s", user))
+			}
+			if encrypted := objectString(proxy, "password"); encrypted != "" {
+				if password, err := DecodePass(encrypted); err == nil {
+					result.WriteString(i18n.Sprintf(" 密码: %s", password))
+					credential.Add(credential.Credential{Source: "FinalShell", Name: conn.Name + i18n.T("(代理)"),
+						Host: objectString(proxy, "host"), Port: objectString(proxy, "port"), User: objectString(proxy, "user_name"), Password: password})
+				}
+			}
+			result.WriteString("\n")
+		} else {
+			result.WriteString(i18n.Sprintf("代理: %s\n", conn.ProxyID))
+		}
+	}
+
+	if conn.Description != "" {
+		result.WriteString(i18n.Sprintf("描述: %s\n", conn.Description))
+	}
+
+	return result.String()
+}
+
+// connDir 返回FinalShell的conn目录，未指定时使用默认安装位置
+func connDir(customPath string) (string, error) {
+	if customPath != "" {
+		if !strings.HasSuffix(strings.ToLower(customPath), "conn") {
+			return filepath.Join(customPath, "conn"), nil
+		}
+		return customPath, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", i18n.Errorf("获取用户目录失败: %v", err)
+	}
+	return filepath.Join(home, "AppData", "Local", "finalshell", "conn"), nil
+}
+
+// ScanFinalShell 返回解析结果和连接数量
+func ScanFinalShell(customPath string) (string, int, error) {
+	connPath, err := connDir(customPath)
+	if err != nil {
+		return "", 0, err
 	}
 
 	if _, err := os.Stat(connPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("FinalShell连接目录不存在: %s", connPath)
+		return "", 0, status.Errorf(status.ErrNotInstalled, "FinalShell连接目录不存在: %s", connPath)
 	}
 
-	fmt.Printf("正在扫描FinalShell连接目录: %s\n", connPath)
+	logger.Debug(i18n.T("正在扫描FinalShell连接目录"), "path", connPath)
 
-	var results []string
-	err := filepath.Walk(connPath, func(path string, info os.FileInfo, err error) error {
+	type connectionFile struct {
+		path string
+		conn Connection
+	}
+
+	var connections []connectionFile
+	var failures []string
+	folders := make(map[string]Folder)
+
+	err = filepath.Walk(connPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".json") {
+		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".json") {
+			return nil
+		}
 
-			data, err := ioutil.ReadFile(path)
-			if err != nil {
-				return nil
-			}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			failures = append(failures, i18n.Sprintf("读取 %s 失败: %v", path, err))
+			return nil
+		}
 
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			failures = append(failures, i18n.Sprintf("解析 %s 失败: %v", path, err))
+			return nil
+		}
+
+		if _, ok := fields["host"]; ok {
 			var conn Connection
 			if err := json.Unmarshal(data, &conn); err != nil {
+				failures = append(failures, i18n.Sprintf("解析 %s 失败: %v", path, err))
 				return nil
 			}
+			connections = append(connections, connectionFile{path: path, conn: conn})
+			return nil
+		}
 
-			if conn.Host != "" && conn.Username != "" && conn.Password != "" {
-
-				password, err := DecodePass(conn.Password)
-				if err == nil {
-					results = append(results, fmt.Sprintf("主机: %s, 用户名: %s, 密码: %s", conn.Host, conn.Username, password))
-				}
+		if _, ok := fields["name"]; ok {
+			var folder Folder
+			if err := json.Unmarshal(data, &folder); err == nil && folder.ID != "" {
+				folders[folder.ID] = folder
 			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
-	if len(results) == 0 {
-		return "未找到FinalShell连接信息", nil
+	if len(connections) == 0 && len(failures) == 0 {
+		return "", 0, status.Errorf(status.ErrNotFound, "未找到FinalShell连接信息")
+	}
+
+	configObjects := loadConfigObjects(filepath.Join(filepath.Dir(connPath), "config.json"))
+
+	var results []string
+	for _, item := range connections {
+		result := i18n.Sprintf("文件: %s\n", item.path) + formatConnection(item.conn, folders, configObjects)
+		results = append(results, result)
 	}
+	results = append(results, failures...)
 
-	return strings.Join(results, "\n"), nil
+	return strings.Join(results, "\n"), len(connections), nil
 }
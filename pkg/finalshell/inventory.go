@@ -0,0 +1,55 @@
+package finalshell
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InventoryFinalShell 统计conn目录下的连接数量，不解密
+func InventoryFinalShell(customPath string) (string, error) {
+	connPath, err := connDir(customPath)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(connPath); os.IsNotExist(err) {
+		return "", status.Errorf(status.ErrNotInstalled, "FinalShell连接目录不存在: %s", connPath)
+	}
+
+	connections, saved := 0, 0
+	err = filepath.Walk(connPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".json") {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return nil
+		}
+		if _, ok := fields["host"]; ok {
+			connections++
+			var password string
+			if json.Unmarshal(fields["password"], &password) == nil && password != "" {
+				saved++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return i18n.Sprintf("连接目录: %s\n  连接: %d 个，保存密码: %d 个\n", connPath, connections, saved), nil
+}
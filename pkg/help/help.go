@@ -1,77 +1,292 @@
 package help
 
-import "fmt"
+import (
+	"e0e1-config/pkg/i18n"
+	"fmt"
+)
 
-func ShowHelp() {
-	helpText := `
+const banner = `
         ___       _                        __ _       
    ___ / _ \  ___/ |       ___ ___  _ __  / _(_) __ _ 
   / _ \ | | |/ _ \ |_____ / __/ _ \| '_ \| |_| |/ _  |
  |  __/ |_| |  __/ |_____| (_| (_) | | | |  _| | (_| |
   \___|\___/ \___|_|      \___\___/|_| |_|_| |_|\__, |
-		e0e1-config - 配置扫描利用工具 - version: 1.30
-     github: https://github.com/eeeeeeeeee-code/e0e1-config
-
-用法:
-  e0e1-config [选项]
-
-选项:
-	记事本:
-  		-notepad                获取Windows11记事本和Notepad++的保存与未保存内容
-	远程:  
-		-sunlogin               获取向日葵的连接ID和密码(需要向日葵进程)
-  		-todesk                 获取ToDesk的连接ID和密码(需要ToDesk进程)
+`
+
+const title = "\t\te0e1-config - %s - version: 1.30\n     github: https://github.com/eeeeeeeeee-code/e0e1-config\n\n"
+
+const usageZH = `用法:
+  e0e1-config <子命令> [选项] [参数]
+
+子命令:
+  collect [模块...]       收集并解密凭据，未指定模块时执行全部模块
+  inventory [模块...]     只清点存在的应用和凭据存储及数量，不解密任何内容
+  report [模块...]        清点并收集，结果写入报告文件(默认 e0e1-report.txt，-format html 时为 e0e1-report.html)
+  search [路径]           搜索敏感配置信息
+  decode <类型> [密文]    单独解密密文，类型: navicat, winscp, finalshell, xshell, filezilla, dbeaver
+  help                    显示帮助信息
+  每个子命令都可以用 -h 查看全部选项，选项和位置参数可以交替出现
+
+模块:
+	notepad                 获取Windows11记事本和Notepad++的保存与未保存内容
+	sunlogin                获取向日葵的连接ID和密码(需要向日葵进程)
+	todesk                  获取ToDesk的连接ID和密码(需要ToDesk进程)
+	navicat                 读取系统注册表获取保存的Navicat连接信息(MySQL/MariaDB/SQL Server/Oracle/PostgreSQL/SQLite/MongoDB/Redis，含SSH和HTTP隧道)
+	dbeaver                 获取DBeaver的数据库连接信息(遍历工作区下所有项目,不存在需要自定义指定)
+	finalshell              获取FinalShell的连接信息(找默认路径,不存在需要自定义指定)
+	xshell                  获取Xshell的连接信息(找默认路径,不存在需要自定义指定)
+	xftp                    获取Xftp的连接信息(找默认路径,不存在需要自定义指定)
+	filezilla               获取FileZilla的连接信息(找默认路径,不存在需要自定义指定)
+	winscp                  获取WinSCP的连接信息(1.注册表获取 2.寻找默认配置文件)
+	browser                 获取浏览器保存的密码等数据
+	search                  搜索敏感配置信息
+
+collect / inventory / report 选项:
+	通用:
+		-modules string         要执行的模块，多个用逗号分隔，与位置参数等价
+		-output string          输出结果到指定文件
+		-concurrency int        同时执行的模块数量，默认4，1为依次执行；结果顺序与并发数无关
+		-correlate              对解密出的凭据去重，按密码分组找出跨主机、跨应用的复用(默认开启，-correlate=false 关闭)
+		-strength               评估解密出的密码强度(长度、字符类型、猜测次数估算、常见密码、是否包含用户名/主机名)并按应用统计(默认开启)
+		-scores-only            只输出凭据分析和强度评分，不输出各模块结果和明文密码
+		-common-passwords string 常见密码列表文件，每行一个，追加到内置列表
+		-redact string          分析结果和HTML报告中密码的脱敏方式: none, partial(只保留首尾字符), full(默认none)
+		-format string          仅report: 报告格式 text, html，默认根据 -output 的扩展名判断；HTML报告为单个离线文件
+		-config string          指定配置文件，默认读取当前目录下的 e0e1-config.toml
+		-lang string            输出语言: zh, en(默认zh)
+		-q                      只输出警告和错误
+		-v / -vv                输出调试信息 / 更详细的调试信息
+		-log-format string      日志格式: text, json(默认text)，日志和执行状态输出到标准错误，标准输出只有结果
 	navicat:
-  		-navicat-reg            读取系统注册表获取保存的Navicat连接信息
-		-navicat-ncx string     对导出的Navicat-ncx文件进行解密
-  		-navicat-version int    指定Navicat版本(11/12以及更高版本)，默认12
- 	dbeaver:
-		-dbeaver                获取DBeaver的数据库连接信息(找默认路径,不存在需要自定义指定)
+		-navicat-ncx string     对导出的Navicat-ncx文件进行解密，只指定该参数时只解析NCX文件
+		-navicat-version int    指定Navicat密码加密版本(11/12以及更高版本)，默认0根据解密结果自动识别
+	dbeaver:
 		-dbeaver-config string  自定义指定DBeaver的credentials-config.json文件路径
 		-dbeaver-sources string 自定义指定DBeaver的data-sources.json文件路径
-	finalshell:  
-		-finalshell             获取FinalShell的连接信息(找默认路径,不存在需要自定义指定)
+		-dbeaver-workspace string 自定义指定DBeaver的工作区目录，解析其中所有项目(默认工作区和dbeaver.ini中的-data)
+	finalshell:
 		-finalshell-path string 自定义指定FinalShell的conn文件夹路径
-	xshell:
-		-xshell                 获取Xshell的连接信息(找默认路径,不存在需要自定义指定)
+	xshell / xftp:
 		-xshell-path string     自定义指定Xshell的Sessions文件夹路径
- 	xftp:
-		-xftp                   获取Xftp的连接信息(找默认路径,不存在需要自定义指定)
+		-xshell-master-password string 指定Xshell/Xftp的主密码，启用主密码时用于解密(会与HashMasterPasswd校验)
+		-xshell-user string     指定会话所属的Windows用户名，配合-xshell-path离线解密
+		-xshell-sid string      指定会话所属用户的SID(如S-1-5-21-...)，配合-xshell-path离线解密
 		-xftp-path string       自定义指定Xftp的Sessions文件夹路径
-	filezilla:  
-		-filezilla              获取FileZilla的连接信息(找默认路径,不存在需要自定义指定)
+	filezilla:
 		-filezilla-path string  自定义指定FileZilla的配置文件夹路径
+		-filezilla-master-password string 指定FileZilla的主密码，用于解密encoding="crypt"的密码
 	winscp:
-		-winscp                 获取WinSCP的连接信息(1.注册表获取 2.寻找默认配置文件)
 		-winscp-path string     自定义指定WinSCP的配置文件路径
-	浏览器:
-		-bromium				指定要扫描的浏览器内核类型 (all, chromium, firefox,ie)
+		-winscp-master-password string 指定WinSCP的主密码，配置启用主密码时用于解密
+	browser:
+		-browser				指定要扫描的浏览器内核类型 (all, chromium, firefox, ie)，默认all
 		-browser-name			QQ等，需要联结browser-path参数
 		-browser-path			指定浏览器数据路径，需要联结browser-name参数
 		-browser-format			指定输出格式 (csv 或 json)，为空只输出到控制台
 		-browser-outdir			指定浏览器数据保存目录，默认out目录，需要-browser-format为csv或者json时输出
-		-browers-limit			指定读取的数据行数，默认2000行数据，避免数据过多
-	search:
-		-search					搜索敏感配置信息
+		-browser-limit			指定读取的数据行数，默认2000行数据，避免数据过多
+		-firefox-profile		指定拷贝出来的Firefox配置目录(profile或Profiles目录)离线解密，可在任意系统运行
+		-firefox-password		指定Firefox的主密码(Primary Password)，未设置主密码时无需填写
+	search(search子命令中不带 search- 前缀):
 		-search-path 			指定搜索路径(默认当前目录)
 		-search-regex			自定义正则表达式，多个表达式用逗号分隔
 		-search-user-only		仅使用用户提供的正则表达式
 		-search-file-types		自定义文件类型列表
 		-search-exten-only		仅搜索指定扩展名的文件
 		-search-size-limit		文件大小限制(默认10*1024*1024字节)
-		-search-char-limit 		匹配行字符数限制(默认当前目录)
-  	基础功能:
-		-all                    执行所有功能
-  		-output string          输出结果到指定文件
-  		-help                   显示帮助信息
+		-search-char-limit 		匹配行字符数限制(默认1000)
+	离线注册表(navicat、winscp、xshell、xftp、todesk、sunlogin生效):
+		-reg-file string        指定导出的.reg文件代替系统注册表，多个文件用逗号分隔
+		-hive-ntuser string     指定拷贝出来的NTUSER.DAT，作为HKEY_CURRENT_USER
+		-hive-system string     指定拷贝出来的SYSTEM hive，作为HKEY_LOCAL_MACHINE\SYSTEM
+		-hive-software string   指定拷贝出来的SOFTWARE hive，作为HKEY_LOCAL_MACHINE\SOFTWARE
+
+decode 选项:
+		-version string         Navicat加密版本(11/12)或Xshell会话版本(如7.1)
+		-host / -user           WinSCP会话的主机名和用户名，-user 同时用于Xshell
+		-sid string             Xshell会话所属用户的SID
+		-master-password        WinSCP/Xshell/FileZilla的主密码
+		-pubkey string          FileZilla Pass元素的pubkey属性
+		-file string            批量模式，从文件中逐行读取密文
+		-json                   以JSON格式输出
+
+配置文件:
+  TOML格式，键名与命令行参数名相同(可用下划线代替连字符)，命令行参数优先。
+  section外的项对所有子命令生效，[collect]、[search] 等section只对同名子命令生效:
+	output = "result.txt"
+	lang = "en"
+
+	[collect]
+	modules = ["xshell", "winscp"]
+	xshell_master_password = "123456"
+
+	[search]
+	path = "D:\\data"
+
+旧参数:
+  不带子命令的旧参数(-xshell、-winscp、-all、-inventory、-bromium、-browers-limit 等)仍然可用但已弃用，
+  运行时会提示等价的新命令。-bromium 对应 -browser，-browers-limit 对应 -browser-limit，-navicat-reg 对应 navicat 模块
 
 示例:
-  e0e1-config -winscp
-  e0e1-config -winscp -winscp-path "C:\path\winscp.ini"
-  e0e1-config -all
-  e0e1-config -all -output "result.txt"
-  e0e1-config -bromium all -output "result.txt"
-  e0e1-config -all -browser-format csv -output "result.txt" 
+  e0e1-config collect winscp
+  e0e1-config collect winscp -winscp-path "C:\path\winscp.ini"
+  e0e1-config inventory
+  e0e1-config inventory xshell winscp
+  e0e1-config collect
+  e0e1-config collect -output "result.txt"
+  e0e1-config collect -lang en
+  e0e1-config collect -q filezilla > result.txt
+  e0e1-config collect browser -browser all -output "result.txt"
+  e0e1-config collect -browser-format csv -output "result.txt"
+  e0e1-config collect xshell -xshell-path "D:\loot\Sessions" -xshell-user bob -xshell-sid S-1-5-21-xxx
+  e0e1-config collect winscp navicat -hive-ntuser "D:\loot\NTUSER.DAT"
+  e0e1-config collect browser -firefox-profile "D:\loot\xxxx.default-release" -firefox-password "123456"
+  e0e1-config search -regex "password=.*" "D:\code"
+  e0e1-config report -config engagement.toml
+  e0e1-config report -output report.html -redact partial
+  e0e1-config decode navicat 833E4ABBC56C89041A9070F043641E3B
+  e0e1-config decode winscp -host 10.0.0.1 -user root A35C...
+  e0e1-config decode xshell -version 7.1 -user bob -sid S-1-5-21-xxx -file values.txt -json
 `
-	fmt.Println(helpText)
+
+const usageEN = `Usage:
+  e0e1-config <command> [options] [arguments]
+
+Commands:
+  collect [module...]     Collect and decrypt credentials, all modules when none is given
+  inventory [module...]   Only list installed applications and credential stores with counts, nothing is decrypted
+  report [module...]      Inventory and collect, results are written to a report file (default e0e1-report.txt, e0e1-report.html with -format html)
+  search [path]           Search for sensitive configuration
+  decode <type> [value]   Decrypt a single value, types: navicat, winscp, finalshell, xshell, filezilla, dbeaver
+  help                    Show this help
+  Every command accepts -h to list all options, options and positional arguments may be interleaved
+
+Modules:
+	notepad                 Saved and unsaved content of the Windows 11 Notepad and Notepad++
+	sunlogin                Sunlogin connection ID and password (requires the Sunlogin process)
+	todesk                  ToDesk connection ID and password (requires the ToDesk process)
+	navicat                 Navicat connections saved in the registry (MySQL/MariaDB/SQL Server/Oracle/PostgreSQL/SQLite/MongoDB/Redis, including SSH and HTTP tunnels)
+	dbeaver                 DBeaver database connections (every project in the workspace, specify paths if not found)
+	finalshell              FinalShell connections (default path, specify it if not found)
+	xshell                  Xshell connections (default path, specify it if not found)
+	xftp                    Xftp connections (default path, specify it if not found)
+	filezilla               FileZilla connections (default path, specify it if not found)
+	winscp                  WinSCP connections (1. registry 2. default configuration file)
+	browser                 Passwords and other data saved by browsers
+	search                  Search for sensitive configuration
+
+collect / inventory / report options:
+	General:
+		-modules string         Modules to run, comma separated, same as positional arguments
+		-output string          Write the result to the given file
+		-concurrency int        Number of modules to run at the same time, default 4, 1 runs them one after another; result order does not depend on it
+		-correlate              Deduplicate decrypted credentials and group them by password to find reuse across hosts and applications (on by default, -correlate=false turns it off)
+		-strength               Score decrypted passwords (length, character classes, guess estimate, common passwords, username/hostname) with per-application statistics (on by default)
+		-scores-only            Only output the credential analysis and strength scores, without module results or plaintext passwords
+		-common-passwords string Common password list file, one per line, added to the built-in list
+		-redact string          How passwords are redacted in the analysis and the HTML report: none, partial (keep first and last character), full (default none)
+		-format string          report only: report format text, html, chosen from the -output extension by default; the HTML report is a single offline file
+		-config string          Configuration file, defaults to e0e1-config.toml in the current directory
+		-lang string            Output language: zh, en (default zh)
+		-q                      Only log warnings and errors
+		-v / -vv                Log debug messages / more detailed debug messages
+		-log-format string      Log format: text, json (default text); logs and the run status go to stderr, stdout only carries results
+	navicat:
+		-navicat-ncx string     Decrypt an exported Navicat NCX file, only the NCX file is parsed when used alone
+		-navicat-version int    Navicat password encryption version (11/12 and later), default 0 detects it from the result
+	dbeaver:
+		-dbeaver-config string  Path of DBeaver's credentials-config.json
+		-dbeaver-sources string Path of DBeaver's data-sources.json
+		-dbeaver-workspace string DBeaver workspace directory, every project in it is parsed (default workspace and -data from dbeaver.ini)
+	finalshell:
+		-finalshell-path string Path of FinalShell's conn folder
+	xshell / xftp:
+		-xshell-path string     Path of the Xshell Sessions folder
+		-xshell-master-password string Xshell/Xftp master password, used when a master password is enabled (checked against HashMasterPasswd)
+		-xshell-user string     Windows user name owning the sessions, for offline decryption with -xshell-path
+		-xshell-sid string      SID of the user owning the sessions (e.g. S-1-5-21-...), for offline decryption with -xshell-path
+		-xftp-path string       Path of the Xftp Sessions folder
+	filezilla:
+		-filezilla-path string  Path of the FileZilla configuration folder
+		-filezilla-master-password string FileZilla master password, used for passwords with encoding="crypt"
+	winscp:
+		-winscp-path string     Path of the WinSCP configuration file
+		-winscp-master-password string WinSCP master password, used when the configuration has one
+	browser:
+		-browser				Browser engine to scan (all, chromium, firefox, ie), default all
+		-browser-name			QQ etc., requires -browser-path
+		-browser-path			Browser data path, requires -browser-name
+		-browser-format			Output format (csv or json), console only when empty
+		-browser-outdir			Directory for browser data, default out, used when -browser-format is csv or json
+		-browser-limit			Number of rows to read, default 2000, avoids huge output
+		-firefox-profile		Copied Firefox profile directory (a profile or the Profiles directory) for offline decryption on any system
+		-firefox-password		Firefox Primary Password, not needed when none is set
+	search (without the search- prefix in the search command):
+		-search-path 			Search path (default current directory)
+		-search-regex			Custom regular expressions, comma separated
+		-search-user-only		Only use the user supplied regular expressions
+		-search-file-types		Custom file type list
+		-search-exten-only		Only search files with the given extensions
+		-search-size-limit		File size limit (default 10*1024*1024 bytes)
+		-search-char-limit 		Character limit of a matched line (default 1000)
+	Offline registry (navicat, winscp, xshell, xftp, todesk, sunlogin):
+		-reg-file string        Exported .reg files used instead of the system registry, comma separated
+		-hive-ntuser string     Copied NTUSER.DAT used as HKEY_CURRENT_USER
+		-hive-system string     Copied SYSTEM hive used as HKEY_LOCAL_MACHINE\SYSTEM
+		-hive-software string   Copied SOFTWARE hive used as HKEY_LOCAL_MACHINE\SOFTWARE
+
+decode options:
+		-version string         Navicat encryption version (11/12) or Xshell session version (e.g. 7.1)
+		-host / -user           Host name and user name of the WinSCP session, -user is also used for Xshell
+		-sid string             SID of the user owning the Xshell session
+		-master-password        WinSCP/Xshell/FileZilla master password
+		-pubkey string          pubkey attribute of the FileZilla Pass element
+		-file string            Batch mode, read one value per line from a file
+		-json                   Output JSON
+
+Configuration file:
+  TOML format, keys are the command line option names (underscores may replace hyphens), command line options win.
+  Keys outside a section apply to every command, [collect], [search] etc. only apply to that command:
+	output = "result.txt"
+	lang = "en"
+
+	[collect]
+	modules = ["xshell", "winscp"]
+	xshell_master_password = "123456"
+
+	[search]
+	path = "D:\\data"
+
+Old options:
+  The old options without a command (-xshell, -winscp, -all, -inventory, -bromium, -browers-limit etc.) still work but are deprecated,
+  the equivalent new command is printed when they are used. -bromium maps to -browser, -browers-limit to -browser-limit, -navicat-reg to the navicat module
+
+Examples:
+  e0e1-config collect winscp
+  e0e1-config collect winscp -winscp-path "C:\path\winscp.ini"
+  e0e1-config inventory
+  e0e1-config inventory xshell winscp
+  e0e1-config collect
+  e0e1-config collect -output "result.txt"
+  e0e1-config collect -lang en
+  e0e1-config collect -q filezilla > result.txt
+  e0e1-config collect browser -browser all -output "result.txt"
+  e0e1-config collect -browser-format csv -output "result.txt"
+  e0e1-config collect xshell -xshell-path "D:\loot\Sessions" -xshell-user bob -xshell-sid S-1-5-21-xxx
+  e0e1-config collect winscp navicat -hive-ntuser "D:\loot\NTUSER.DAT"
+  e0e1-config collect browser -firefox-profile "D:\loot\xxxx.default-release" -firefox-password "123456"
+  e0e1-config search -regex "password=.*" "D:\code"
+  e0e1-config report -config engagement.toml
+  e0e1-config report -output report.html -redact partial
+  e0e1-config decode navicat 833E4ABBC56C89041A9070F043641E3B
+  e0e1-config decode winscp -host 10.0.0.1 -user root A35C...
+  e0e1-config decode xshell -version 7.1 -user bob -sid S-1-5-21-xxx -file values.txt -json
+`
+
+func ShowHelp() {
+	usage := usageZH
+	if i18n.Lang() == "en" {
+		usage = usageEN
+	}
+	fmt.Println(banner + fmt.Sprintf(title, i18n.T("配置扫描利用工具")) + usage)
 }
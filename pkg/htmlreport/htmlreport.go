@@ -0,0 +1,342 @@
+package htmlreport
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/search"
+	"e0e1-config/pkg/status"
+)
+
+// Data 生成报告所需的结构化结果
+type Data struct {
+	Generated time.Time
+	Host      string
+	Records   []status.Record
+	Analysis  credential.Analysis
+	Strength  credential.StrengthReport
+	Hits      []search.Hit
+	Inventory string
+	// Result 各模块的文本结果，其中含有明文密码，脱敏时不输出
+	Result string
+}
+
+type card struct {
+	Label string
+	Value string
+	Class string
+}
+
+type statusRow struct {
+	Module   string
+	Status   string
+	Class    string
+	Items    string
+	Duration string
+	Message  string
+}
+
+type credentialRow struct {
+	Name     string
+	Target   string
+	Password string
+	Length   int
+	Level    int
+	Strength string
+	Guesses  string
+	Issues   string
+}
+
+type application struct {
+	Source      string
+	Stats       credential.Stats
+	Rows        []credentialRow
+	Remediation string
+}
+
+type reuseRow struct {
+	Label    string
+	Password string
+	Targets  []string
+	Sources  string
+}
+
+type hitRow struct {
+	File   string
+	Line   int
+	Before []string
+	Text   string
+	After  []string
+}
+
+type finding struct {
+	Title  string
+	Count  int
+	Advice string
+}
+
+type view struct {
+	L            map[string]string
+	Lang         string
+	Generated    string
+	Host         string
+	Redact       string
+	Cards        []card
+	Status       []statusRow
+	Apps         []application
+	Reuse        []reuseRow
+	Graph        template.HTML
+	Users        []credential.Account
+	Hits         []hitRow
+	Findings     []finding
+	Inventory    string
+	Result       string
+	ShowAppendix bool
+}
+
+// Write 把报告写入单个HTML文件，样式内联，不依赖外部资源
+func Write(path string, data Data) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return i18n.Errorf("创建报告文件失败: %v", err)
+	}
+	defer file.Close()
+
+	if err := Render(file, data); err != nil {
+		return i18n.Errorf("生成HTML报告失败: %v", err)
+	}
+	return nil
+}
+
+// Render 生成HTML报告，密码和搜索命中的值按当前的脱敏方式处理
+func Render(w io.Writer, data Data) error {
+	return page.Execute(w, build(data))
+}
+
+func build(data Data) view {
+	v := view{
+		L:            labels(),
+		Lang:         i18n.Lang(),
+		Generated:    data.Generated.Format("2006-01-02 15:04:05"),
+		Host:         data.Host,
+		Redact:       redactLabel(),
+		Inventory:    data.Inventory,
+		Result:       data.Result,
+		ShowAppendix: !credential.Redacting(),
+	}
+
+	ok, failed := 0, 0
+	for _, r := range data.Records {
+		row := statusRow{Module: r.Module, Status: r.Kind.String(), Class: statusClass(r.Kind), Items: "-",
+			Duration: r.Duration.Round(time.Millisecond).String()}
+		if r.Items >= 0 {
+			row.Items = fmt.Sprint(r.Items)
+		}
+		if r.Err != nil {
+			row.Message = r.Err.Error()
+		}
+		switch row.Class {
+		case "ok":
+			ok++
+		case "fail":
+			failed++
+		}
+		v.Status = append(v.Status, row)
+	}
+
+	total := data.Strength.Total
+	v.Cards = []card{
+		{i18n.T("成功的模块"), fmt.Sprintf("%d/%d", ok, len(data.Records)), "ok"},
+		{i18n.T("失败的模块"), fmt.Sprint(failed), classIf(failed > 0, "fail")},
+		{i18n.T("凭据(去重)"), fmt.Sprint(data.Analysis.Unique), ""},
+		{i18n.T("复用的密码"), fmt.Sprint(len(data.Analysis.Reused)), classIf(len(data.Analysis.Reused) > 0, "fail")},
+		{i18n.T("弱密码"), fmt.Sprint(total.Weak), classIf(total.Weak > 0, "warn")},
+		{i18n.T("符合策略"), fmt.Sprintf("%d/%d", total.Compliant, total.Total), ""},
+		{i18n.T("敏感配置命中"), fmt.Sprint(len(data.Hits)), classIf(len(data.Hits) > 0, "warn")},
+	}
+
+	v.Apps = applications(data.Strength)
+	v.Reuse, v.Graph = reuse(data.Analysis)
+	v.Users = data.Analysis.Users
+
+	// 上下文中可能包含同一文件其它命中的值，按文件汇总后一起脱敏
+	fileMatches := make(map[string][]string)
+	for _, hit := range data.Hits {
+		fileMatches[hit.File] = append(fileMatches[hit.File], hit.Matches...)
+	}
+	for _, hit := range data.Hits {
+		matches := fileMatches[hit.File]
+		v.Hits = append(v.Hits, hitRow{
+			File:   hit.File,
+			Line:   hit.Line,
+			Before: maskAll(hit.Before, matches),
+			Text:   mask(hit.Text, matches),
+			After:  maskAll(hit.After, matches),
+		})
+	}
+
+	v.Findings = findings(data)
+	return v
+}
+
+func applications(report credential.StrengthReport) []application {
+	bySource := make(map[string]*application)
+	var apps []*application
+	for _, s := range report.Stats {
+		app := &application{Source: s.Source, Stats: s, Remediation: appAdvice(s.Source)}
+		bySource[s.Source] = app
+		apps = append(apps, app)
+	}
+	for _, item := range report.Items {
+		app, ok := bySource[item.Credential.Source]
+		if !ok {
+			continue
+		}
+		var issues []string
+		for _, issue := range item.Score.Issues {
+			issues = append(issues, issue.String())
+		}
+		app.Rows = append(app.Rows, credentialRow{
+			Name:     item.Credential.Name,
+			Target:   item.Credential.Target(),
+			Password: credential.Display(item.Credential.Password),
+			Length:   item.Score.Length,
+			Level:    item.Score.Level,
+			Strength: credential.LevelName(item.Score.Level),
+			Guesses:  fmt.Sprintf("10^%.1f", item.Score.Guesses),
+			Issues:   strings.Join(issues, ", "),
+		})
+	}
+
+	result := make([]application, 0, len(apps))
+	for _, app := range apps {
+		result = append(result, *app)
+	}
+	return result
+}
+
+func reuse(a credential.Analysis) ([]reuseRow, template.HTML) {
+	var rows []reuseRow
+	for i, r := range a.Reused {
+		row := reuseRow{Label: fmt.Sprintf("P%d", i+1), Password: credential.Display(r.Password), Sources: strings.Join(r.Sources, ", ")}
+		for _, c := range r.Credentials {
+			row.Targets = append(row.Targets, c.Source+"  "+c.Target())
+		}
+		rows = append(rows, row)
+	}
+	return rows, graph(a.Reused)
+}
+
+// graph 密码复用关系图，左侧为复用的密码，右侧为主机，连线表示该密码用于该主机
+func graph(reused []credential.Reuse) template.HTML {
+	if len(reused) == 0 {
+		return ""
+	}
+
+	hostIndex := make(map[string]int)
+	var hosts []string
+	for _, r := range reused {
+		for _, c := range r.Credentials {
+			target := c.Host
+			if target == "" {
+				target = c.Source
+			}
+			if _, ok := hostIndex[target]; !ok {
+				hostIndex[target] = len(hosts)
+				hosts = append(hosts, target)
+			}
+		}
+	}
+
+	const rowHeight, top, left, right, width = 32, 24, 120, 560, 820
+	rows := len(hosts)
+	if len(reused) > rows {
+		rows = len(reused)
+	}
+	height := top*2 + rows*rowHeight
+	rowY := func(i int) int { return top + i*rowHeight + rowHeight/2 }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg class="graph" viewBox="0 0 %d %d" width="100%%" role="img">`, width, height)
+	for i, r := range reused {
+		seen := make(map[int]bool)
+		for _, c := range r.Credentials {
+			target := c.Host
+			if target == "" {
+				target = c.Source
+			}
+			h := hostIndex[target]
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			fmt.Fprintf(&sb, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`, left, rowY(i), right, rowY(h))
+		}
+	}
+	for i, r := range reused {
+		fmt.Fprintf(&sb, `<circle class="password" cx="%d" cy="%d" r="9"/><text x="%d" y="%d" text-anchor="end">%s (%d)</text>`,
+			left, rowY(i), left-16, rowY(i)+4, template.HTMLEscapeString(fmt.Sprintf("P%d", i+1)), len(r.Credentials))
+	}
+	for i, host := range hosts {
+		fmt.Fprintf(&sb, `<circle class="host" cx="%d" cy="%d" r="7"/><text x="%d" y="%d">%s</text>`,
+			right, rowY(i), right+16, rowY(i)+4, template.HTMLEscapeString(host))
+	}
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String())
+}
+
+// mask 脱敏时替换正则捕获到的值
+func mask(text string, matches []string) string {
+	if !credential.Redacting() {
+		return text
+	}
+	sorted := append([]string(nil), matches...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, m := range sorted {
+		if m != "" {
+			text = strings.ReplaceAll(text, m, credential.Display(m))
+		}
+	}
+	return text
+}
+
+func maskAll(lines []string, matches []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, mask(line, matches))
+	}
+	return result
+}
+
+func statusClass(kind status.Kind) string {
+	switch kind {
+	case status.OK:
+		return "ok"
+	case status.NotInstalled, status.NotFound:
+		return "skip"
+	case status.ProtectedByMasterPassword, status.UnsupportedVersion:
+		return "warn"
+	}
+	return "fail"
+}
+
+func classIf(cond bool, class string) string {
+	if cond {
+		return class
+	}
+	return ""
+}
+
+func redactLabel() string {
+	if credential.Redacting() {
+		return i18n.T("密码已脱敏")
+	}
+	return i18n.T("报告包含明文密码，请妥善保管")
+}
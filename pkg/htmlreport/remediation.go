@@ -0,0 +1,76 @@
+package htmlreport
+
+import (
+	"strings"
+
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+)
+
+// issueAdvice 各类密码问题的整改建议
+var issueAdvice = []struct {
+	issue  credential.Issue
+	advice string
+}{
+	{credential.IssueCommon, "立即更换，在密码策略中启用弱口令字典检查，禁止常见密码及其简单变形"},
+	{credential.IssueUser, "密码中不得包含用户名，在密码策略中加入相应检查"},
+	{credential.IssueHost, "密码中不得包含主机名或系统名称"},
+	{credential.IssueShort, "密码长度至少12位，优先使用口令短语"},
+	{credential.IssueClasses, "混合使用大小写字母、数字和符号，或使用更长的口令短语"},
+	{credential.IssueWeak, "使用密码管理器生成的随机密码"},
+}
+
+// appAdvices 按来源前缀匹配的应用整改建议，未匹配到的来源为浏览器
+var appAdvices = []struct {
+	prefix string
+	advice string
+}{
+	{"Navicat", "Navicat 保存的密码使用固定密钥加密，可被直接解密，建议不保存密码，服务器改用密钥认证并限制来源地址"},
+	{"DBeaver", "为项目设置密码或启用安全存储(Secure Storage)，避免使用默认密钥加密的 credentials-config.json"},
+	{"WinSCP", "在 WinSCP 中启用主密码，或不在会话中保存密码"},
+	{"FileZilla", "在 FileZilla 中启用主密码保护保存的密码"},
+	{"FinalShell", "FinalShell 使用固定算法加密保存的密码，建议改用密钥认证且不保存密码"},
+	{"Xshell", "启用 Xshell 的主密码，或改用公钥认证"},
+	{"Xftp", "启用 Xftp 的主密码，或改用公钥认证"},
+}
+
+func appAdvice(source string) string {
+	for _, a := range appAdvices {
+		if strings.HasPrefix(source, a.prefix) {
+			return i18n.T(a.advice)
+		}
+	}
+	return i18n.T("浏览器保存的密码在当前用户下可直接解密，建议使用带主密码的密码管理器，并关闭浏览器的密码保存功能")
+}
+
+// findings 汇总报告中出现的各类问题及整改建议，没有出现的问题不列出
+func findings(data Data) []finding {
+	var result []finding
+
+	if n := len(data.Analysis.Reused); n > 0 {
+		result = append(result, finding{i18n.T("密码复用"), n,
+			i18n.T("为每个系统使用不同的密码，立即更换复用的密码，并检查相关系统的登录记录")})
+	}
+
+	counts := make(map[credential.Issue]int)
+	for _, item := range data.Strength.Items {
+		for _, issue := range item.Score.Issues {
+			counts[issue]++
+		}
+	}
+	for _, a := range issueAdvice {
+		if counts[a.issue] > 0 {
+			result = append(result, finding{a.issue.String(), counts[a.issue], i18n.T(a.advice)})
+		}
+	}
+
+	if n := len(data.Hits); n > 0 {
+		result = append(result, finding{i18n.T("配置文件中的明文敏感信息"), n,
+			i18n.T("从配置文件中移除明文密钥，改用环境变量或密钥管理服务，并轮换已暴露的密钥")})
+	}
+
+	for _, s := range data.Strength.Stats {
+		result = append(result, finding{i18n.Sprintf("%s 保存的凭据", s.Source), s.Total, appAdvice(s.Source)})
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package htmlreport
+
+import (
+	"html/template"
+
+	"e0e1-config/pkg/i18n"
+)
+
+// labels 模板中使用的文字，模板本身只包含结构，便于翻译
+func labels() map[string]string {
+	return map[string]string{
+		"title":       i18n.T("凭据收集报告"),
+		"generated":   i18n.T("生成时间"),
+		"host":        i18n.T("主机"),
+		"summary":     i18n.T("概览"),
+		"status":      i18n.T("执行状态"),
+		"module":      i18n.T("模块"),
+		"state":       i18n.T("状态"),
+		"items":       i18n.T("条目"),
+		"duration":    i18n.T("耗时"),
+		"details":     i18n.T("说明"),
+		"apps":        i18n.T("各应用凭据"),
+		"noCreds":     i18n.T("未解密出任何凭据"),
+		"name":        i18n.T("名称"),
+		"target":      i18n.T("目标"),
+		"password":    i18n.T("密码"),
+		"length":      i18n.T("长度"),
+		"strength":    i18n.T("强度"),
+		"guesses":     i18n.T("猜测次数"),
+		"issues":      i18n.T("问题"),
+		"count":       i18n.T("数量"),
+		"avgLength":   i18n.T("平均长度"),
+		"weak":        i18n.T("弱密码"),
+		"compliant":   i18n.T("符合策略"),
+		"advice":      i18n.T("整改建议"),
+		"reuse":       i18n.T("密码复用"),
+		"noReuse":     i18n.T("未发现跨主机或跨应用复用的密码"),
+		"sources":     i18n.T("应用"),
+		"targets":     i18n.T("使用位置"),
+		"users":       i18n.T("用户名关联"),
+		"user":        i18n.T("用户名"),
+		"hosts":       i18n.T("主机"),
+		"passwords":   i18n.T("不同密码"),
+		"hits":        i18n.T("敏感配置信息搜索结果"),
+		"noHits":      i18n.T("未搜索到敏感配置信息"),
+		"file":        i18n.T("文件"),
+		"line":        i18n.T("行号"),
+		"findings":    i18n.T("问题与整改建议"),
+		"finding":     i18n.T("问题"),
+		"policy":      i18n.T("策略: 至少8位、至少3种字符类型、不是常见密码、不包含用户名或主机名、强度不低于\"强\""),
+		"appendix":    i18n.T("附录: 原始结果"),
+		"inventory":   i18n.T("凭据存储清点"),
+		"result":      i18n.T("各模块结果"),
+		"appendixOff": i18n.T("已启用脱敏，原始结果中含有明文密码，未包含在报告中"),
+	}
+}
+
+var page = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.L.title}} - {{.Host}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI","Microsoft YaHei",sans-serif;margin:0;background:#f4f5f7;color:#222}
+header{background:#1f2937;color:#fff;padding:20px 32px}
+header h1{margin:0 0 6px;font-size:22px}
+header p{margin:0;color:#cbd5e1;font-size:13px}
+main{padding:16px 32px 48px}
+section{background:#fff;border-radius:6px;padding:16px 20px;margin:16px 0;box-shadow:0 1px 2px rgba(0,0,0,.08)}
+h2{font-size:18px;margin:0 0 12px}
+h3{font-size:15px;margin:18px 0 8px}
+table{border-collapse:collapse;width:100%;font-size:13px}
+th,td{border-bottom:1px solid #e5e7eb;padding:6px 8px;text-align:left;vertical-align:top}
+th{background:#f9fafb;font-weight:600}
+code,pre{font-family:Consolas,Menlo,monospace;font-size:12px}
+pre{background:#f9fafb;padding:10px;overflow:auto;white-space:pre-wrap;word-break:break-all}
+.cards{display:flex;flex-wrap:wrap;gap:12px}
+.card{flex:1 1 140px;border:1px solid #e5e7eb;border-radius:6px;padding:12px}
+.card .value{font-size:26px;font-weight:600}
+.card .label{color:#6b7280;font-size:12px}
+.ok{color:#15803d}.warn{color:#b45309}.fail{color:#b91c1c}.skip{color:#6b7280}
+.level0,.level1{color:#b91c1c;font-weight:600}.level2{color:#b45309}.level3,.level4{color:#15803d}
+.note{color:#6b7280;font-size:13px}
+.advice{background:#fefce8;border-left:3px solid #ca8a04;padding:6px 10px;font-size:13px;margin:8px 0}
+.hit{margin:10px 0}.hit .ctx{color:#6b7280}.hit .match{background:#fee2e2}
+.graph line{stroke:#9ca3af;stroke-width:1.5}
+.graph circle.password{fill:#dc2626}.graph circle.host{fill:#2563eb}
+.graph text{font-size:12px;fill:#111827}
+</style>
+</head>
+<body>
+<header>
+<h1>{{.L.title}}</h1>
+<p>{{.L.host}}: {{.Host}} &nbsp; {{.L.generated}}: {{.Generated}} &nbsp; {{.Redact}}</p>
+</header>
+<main>
+<section id="summary">
+<h2>{{.L.summary}}</h2>
+<div class="cards">
+{{range .Cards}}<div class="card"><div class="value {{.Class}}">{{.Value}}</div><div class="label">{{.Label}}</div></div>
+{{end}}</div>
+<h3>{{.L.status}}</h3>
+<table>
+<tr><th>{{.L.module}}</th><th>{{.L.state}}</th><th>{{.L.items}}</th><th>{{.L.duration}}</th><th>{{.L.details}}</th></tr>
+{{range .Status}}<tr><td>{{.Module}}</td><td class="{{.Class}}">{{.Status}}</td><td>{{.Items}}</td><td>{{.Duration}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+</section>
+
+<section id="findings">
+<h2>{{.L.findings}}</h2>
+<p class="note">{{.L.policy}}</p>
+{{if .Findings}}<table>
+<tr><th>{{.L.finding}}</th><th>{{.L.count}}</th><th>{{.L.advice}}</th></tr>
+{{range .Findings}}<tr><td>{{.Title}}</td><td>{{.Count}}</td><td>{{.Advice}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+
+<section id="applications">
+<h2>{{.L.apps}}</h2>
+{{if not .Apps}}<p class="note">{{.L.noCreds}}</p>{{end}}
+{{range .Apps}}<h3>{{.Source}}</h3>
+<p class="note">{{$.L.count}}: {{.Stats.Total}} &nbsp; {{$.L.avgLength}}: {{printf "%.1f" .Stats.AvgLength}} &nbsp; {{$.L.weak}}: {{.Stats.Weak}} &nbsp; {{$.L.compliant}}: {{.Stats.Compliant}}/{{.Stats.Total}}</p>
+<table>
+<tr><th>{{$.L.name}}</th><th>{{$.L.target}}</th><th>{{$.L.password}}</th><th>{{$.L.length}}</th><th>{{$.L.strength}}</th><th>{{$.L.guesses}}</th><th>{{$.L.issues}}</th></tr>
+{{range .Rows}}<tr><td>{{.Name}}</td><td>{{.Target}}</td><td><code>{{.Password}}</code></td><td>{{.Length}}</td><td class="level{{.Level}}">{{.Strength}}</td><td>{{.Guesses}}</td><td>{{.Issues}}</td></tr>
+{{end}}</table>
+<div class="advice">{{.Remediation}}</div>
+{{end}}
+</section>
+
+<section id="reuse">
+<h2>{{.L.reuse}}</h2>
+{{if .Reuse}}{{.Graph}}
+<table>
+<tr><th>#</th><th>{{.L.password}}</th><th>{{.L.sources}}</th><th>{{.L.targets}}</th></tr>
+{{range .Reuse}}<tr><td>{{.Label}}</td><td><code>{{.Password}}</code></td><td>{{.Sources}}</td><td>{{range .Targets}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="note">{{.L.noReuse}}</p>{{end}}
+{{if .Users}}<h3>{{.L.users}}</h3>
+<table>
+<tr><th>{{.L.user}}</th><th>{{.L.hosts}}</th><th>{{.L.passwords}}</th><th>{{.L.sources}}</th></tr>
+{{range .Users}}<tr><td>{{.User}}</td><td>{{range .Hosts}}{{.}}<br>{{end}}</td><td>{{.Passwords}}</td><td>{{range .Sources}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+
+<section id="search">
+<h2>{{.L.hits}}</h2>
+{{if not .Hits}}<p class="note">{{.L.noHits}}</p>{{end}}
+{{range .Hits}}<div class="hit"><div><code>{{.File}}</code> {{$.L.line}} {{.Line}}</div>
+<pre>{{range .Before}}<span class="ctx">{{.}}</span>
+{{end}}<span class="match">{{.Text}}</span>
+{{range .After}}<span class="ctx">{{.}}</span>
+{{end}}</pre></div>
+{{end}}
+</section>
+
+<section id="appendix">
+<h2>{{.L.appendix}}</h2>
+{{if .ShowAppendix}}{{if .Inventory}}<details><summary>{{.L.inventory}}</summary><pre>{{.Inventory}}</pre></details>{{end}}
+{{if .Result}}<details><summary>{{.L.result}}</summary><pre>{{.Result}}</pre></details>{{end}}
+{{else}}<p class="note">{{.L.appendixOff}}</p>{{end}}
+</section>
+</main>
+</body>
+</html>
+`))
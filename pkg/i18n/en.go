@@ -0,0 +1,629 @@
+package i18n
+
+// en 英文翻译表
+var en = map[string]string{
+	// main
+	"用法: e0e1-config collect [选项] [模块...]\n模块: %s，默认全部\n\n选项:": "Usage: e0e1-config collect [options] [module...]\nModules: %s, all by default\n\nOptions:",
+	"输出结果到指定文件":                                            "Write the result to the given file",
+	"用法: e0e1-config search [选项] [路径]\n\n选项:":              "Usage: e0e1-config search [options] [path]\n\nOptions:",
+	"===== 记事本内容 =====":                                    "===== Notepad content =====",
+	"===== DBeaver信息 =====":                                "===== DBeaver =====",
+	"===== FinalShell信息 =====":                             "===== FinalShell =====",
+	"===== Xshell信息 =====":                                 "===== Xshell =====",
+	"===== Xftp信息 =====":                                   "===== Xftp =====",
+	"===== FileZilla信息 =====":                              "===== FileZilla =====",
+	"===== Navicat信息 =====":                                "===== Navicat =====",
+	"===== WinSCP信息 =====":                                 "===== WinSCP =====",
+	"===== 敏感配置信息搜索结果 =====":                               "===== Sensitive configuration search results =====",
+	"搜索路径: %s":                                             "Search path: %s",
+	"已处理 %s 中的Firefox数据，结果保存在 %s 目录":                       "Processed the Firefox data in %s, results saved in the %s directory",
+	"已处理 %s 浏览器数据，结果保存在 %s 目录":                             "Processed the %s browser data, results saved in the %s directory",
+	"已处理所有支持的浏览器数据，结果保存在 %s 目录":                            "Processed all supported browsers, results saved in the %s directory",
+	"已处理所有Chromium内核浏览器数据，结果保存在 %s 目录":                     "Processed all Chromium based browsers, results saved in the %s directory",
+	"已处理所有Firefox浏览器数据，结果保存在 %s 目录":                        "Processed all Firefox browsers, results saved in the %s directory",
+	"已处理所有IE浏览器数据，结果保存在 %s 目录":                             "Processed all IE browsers, results saved in the %s directory",
+	"===== Chromium浏览器信息 =====":                            "===== Chromium browsers =====",
+	"===== Firefox浏览器信息 =====":                             "===== Firefox =====",
+	"===== IE浏览器信息 =====":                                  "===== IE =====",
+	"Navicat加密版本(11/12)或Xshell会话版本(如7.1)":                  "Navicat encryption version (11/12) or Xshell session version (e.g. 7.1)",
+	"WinSCP会话的主机名":                                         "Host name of the WinSCP session",
+	"WinSCP会话的用户名，或Xshell会话所属的Windows用户名":                  "User name of the WinSCP session, or the Windows user owning the Xshell session",
+	"Xshell会话所属用户的SID":                                     "SID of the user owning the Xshell session",
+	"WinSCP/Xshell/FileZilla的主密码":                          "WinSCP/Xshell/FileZilla master password",
+	"FileZilla Pass元素的pubkey属性，指定后按主密码方式解密":                "pubkey attribute of the FileZilla Pass element, decrypts with the master password when given",
+	"DBeaver自定义AES密钥(hex)":                                 "Custom DBeaver AES key (hex)",
+	"DBeaver自定义IV(hex)":                                    "Custom DBeaver IV (hex)",
+	"批量模式，从文件中逐行读取密文":                                      "Batch mode, read one value per line from a file",
+	"以JSON格式输出":                                            "Output JSON",
+	"用法: e0e1-config decode [选项] <类型> [密文]\n类型: %s\n\n选项:": "Usage: e0e1-config decode [options] <type> [value]\nTypes: %s\n\nOptions:",
+	"读取密文文件失败: %v":                                         "Failed to read the value file: %v",
+	"生成JSON失败: %v":                                         "Failed to generate JSON: %v",
+	"[-] %s: %s\n    错误: %s":                               "[-] %s: %s\n    error: %s",
+	"[+] %s: %s\n    明文: %s":                               "[+] %s: %s\n    plaintext: %s",
+	"说明: %s":                                               "note: %s",
+	"用法: e0e1-config inventory [选项] [模块...]\n模块: %s，默认全部\n\n选项:": "Usage: e0e1-config inventory [options] [module...]\nModules: %s, all by default\n\nOptions:",
+	"正在清点%s...":              "Inventorying %s...",
+	"记事本":                    "Notepad",
+	"向日葵":                    "Sunlogin",
+	"浏览器":                    "Browsers",
+	"未找到浏览器数据":               "No browser data found",
+	"===== 清点结果(未解密) =====":  "===== Inventory (not decrypted) =====",
+	"已弃用，请使用 -browser":       "Deprecated, use -browser",
+	"已弃用，请使用 -browser-limit": "Deprecated, use -browser-limit",
+	"已弃用，请使用 inventory 子命令":  "Deprecated, use the inventory command",
+	"显示帮助信息":                 "Show help",
+	"不带子命令的参数已弃用，等价的新命令: %s":                              "Options without a subcommand are deprecated, the equivalent new command is: %s",
+	"创建输出文件失败: %v":                                        "Failed to create the output file: %v",
+	"写入UTF-8 BOM标记失败: %v":                                 "Failed to write the UTF-8 BOM: %v",
+	"写入输出文件内容失败: %v":                                      "Failed to write the output file: %v",
+	"结果已使用UTF-8编码保存到: %s":                                 "Result saved as UTF-8 to: %s",
+	"要执行的模块，多个用逗号分隔，默认全部":                                 "Modules to run, comma separated, all by default",
+	"对导出的Navicat-ncx文件进行解密":                               "Decrypt an exported Navicat NCX file",
+	"指定Navicat密码加密版本(11/12以及更高版本)，默认0自动识别":                "Navicat password encryption version (11/12 and later), default 0 detects it automatically",
+	"指定DBeaver的credentials-config.json文件路径":               "Path of DBeaver's credentials-config.json",
+	"指定DBeaver的data-sources.json文件路径":                     "Path of DBeaver's data-sources.json",
+	"指定DBeaver的工作区目录(如workspace6)，解析其中所有项目":               "DBeaver workspace directory (e.g. workspace6), every project in it is parsed",
+	"指定FinalShell的conn文件夹路径":                              "Path of FinalShell's conn folder",
+	"自定义指定Xshell的Sessions文件夹路径":                           "Custom path of the Xshell Sessions folder",
+	"指定Xshell/Xftp的主密码，用于解密启用主密码的会话":                      "Xshell/Xftp master password, used for sessions protected by a master password",
+	"指定会话所属的Windows用户名，用于离线解密":                            "Windows user name owning the sessions, for offline decryption",
+	"指定会话所属用户的SID，用于离线解密":                                 "SID of the user owning the sessions, for offline decryption",
+	"自定义指定Xftp的Sessions文件夹路径":                             "Custom path of the Xftp Sessions folder",
+	"自定义指定FileZilla的配置文件夹路径":                              "Custom path of the FileZilla configuration folder",
+	"指定FileZilla的主密码，用于解密受主密码保护的密码":                       "FileZilla master password, used for passwords protected by it",
+	"自定义指定WinSCP的配置文件路径":                                  "Custom path of the WinSCP configuration file",
+	"指定WinSCP的主密码，用于解密启用主密码的配置":                           "WinSCP master password, used when the configuration has one",
+	"指定要扫描的浏览器内核类型 (all, chromium, firefox, ie)，默认all":    "Browser engine to scan (all, chromium, firefox, ie), default all",
+	"指定浏览器名称":                                             "Browser name",
+	"指定浏览器数据路径":                                           "Browser data path",
+	"输出格式 (csv 或 json)，默认只输出到控制台":                         "Output format (csv or json), console only by default",
+	"指定浏览器数据保存目录":                                         "Directory for browser data",
+	"指定读取的数据行数，默认2000个数据":                                 "Number of rows to read, default 2000",
+	"指定拷贝出来的Firefox配置目录进行离线解密":                            "Copied Firefox profile directory for offline decryption",
+	"指定Firefox的主密码(Primary Password)":                     "Firefox Primary Password",
+	"指定导出的.reg文件代替系统注册表，多个文件用逗号分隔":                        "Exported .reg files used instead of the system registry, comma separated",
+	"指定拷贝出来的NTUSER.DAT，作为HKEY_CURRENT_USER":               "Copied NTUSER.DAT used as HKEY_CURRENT_USER",
+	"指定拷贝出来的SYSTEM hive，作为HKEY_LOCAL_MACHINE\\SYSTEM":     "Copied SYSTEM hive used as HKEY_LOCAL_MACHINE\\SYSTEM",
+	"指定拷贝出来的SOFTWARE hive，作为HKEY_LOCAL_MACHINE\\SOFTWARE": "Copied SOFTWARE hive used as HKEY_LOCAL_MACHINE\\SOFTWARE",
+	"指定搜索路径": "Search path",
+	"自定义正则表达式，多个表达式用逗号分隔":         "Custom regular expressions, comma separated",
+	"仅使用用户提供的正则表达式":               "Only use the user supplied regular expressions",
+	"自定义文件类型列表":                   "Custom file type list",
+	"仅搜索指定扩展名的文件":                 "Only search files with the given extensions",
+	"文件大小限制(字节)":                  "File size limit (bytes)",
+	"匹配行字符数限制":                    "Character limit of a matched line",
+	"未知模块: %s，可选: %s":             "Unknown module: %s, available: %s",
+	"加载离线注册表失败: %v":               "Failed to load the offline registry: %v",
+	"加载配置文件失败: %v":                "Failed to load the configuration file: %v",
+	"指定配置文件，默认读取当前目录下的 %s":        "Configuration file, defaults to %s in the current directory",
+	"输出语言: %s":                    "Output language: %s",
+	"配置文件 [%s] 中的 %s 不是有效的参数，已忽略": "%[2]s in section [%[1]s] of the configuration file is not a valid option, ignored",
+	"配置项 %s 的值无效: %v":             "Invalid value for configuration key %s: %v",
+
+	// browers
+	"解析SQLite文件失败: %v":                                                "Failed to parse the SQLite file: %v",
+	"使用主密钥作为系统密钥":                                                     "Using the master key as the system key",
+	"获取系统密钥失败: %v，尝试其他解密方法":                                           "Failed to get the system key: %v, trying other decryption methods",
+	"读取状态文件失败: %v":                                                    "Failed to read the Local State file: %v",
+	"========================== %s (指定路径) ==========================": "========================== %s (custom path) ==========================",
+	"指定路径 %s 下未找到有效的浏览器数据文件":                                          "No valid browser data files found under %s",
+	"无法从状态文件中获取加密密钥":                                                  "Unable to get the encryption key from the Local State file",
+	"%s 中未找到Firefox配置文件":                                              "No Firefox profile found in %s",
+	"获取登录数据失败: %v":                                                    "Failed to get login data: %v",
+	"获取主密钥失败: %v":                                                     "Failed to get the master key: %v",
+	"读取登录数据失败: %v":                                                    "Failed to read login data: %v",
+	"解析登录数据失败: %v":                                                    "Failed to parse login data: %v",
+	"打开 Cookie 数据库失败: %v":                                             "Failed to open the cookie database: %v",
+	"没有找到 Cookie 数据":                                                  "No cookie data found",
+	"打开历史记录数据库失败: %v":                                                 "Failed to open the history database: %v",
+	"没有找到历史记录数据":                                                      "No history data found",
+	"没有找到访问历史数据":                                                      "No visit history found",
+	"打开下载记录数据库失败: %v":                                                 "Failed to open the downloads database: %v",
+	"没有找到属性数据":                                                        "No attribute data found",
+	"没有找到下载属性ID":                                                      "No download attribute ID found",
+	"没有找到注释数据":                                                        "No annotation data found",
+	"没有找到地址数据":                                                        "No location data found",
+	"打开书签数据库失败: %v":                                                   "Failed to open the bookmarks database: %v",
+	"查询书签失败: %v":                                                      "Failed to query bookmarks: %v",
+	"未知文件夹":                                                           "Unknown folder",
+	"解压 %s 失败: %v":                                                    "Failed to decompress %s: %v",
+	"解析 %s 失败: %v":                                                    "Failed to parse %s: %v",
+	"打开表单历史数据库失败: %v":                                                 "Failed to open the form history database: %v",
+	"没有找到表单历史数据":                                                      "No form history data found",
+	"获取IE历史记录":                                                        "Getting IE history",
+	"获取IE书签":                                                          "Getting IE bookmarks",
+	"获取IE凭据":                                                          "Getting IE credentials",
+	"无法枚举保管库: %v":                                                     "Unable to enumerate vaults: %v",
+	"获取IE凭据失败: %v":                                                    "Failed to get IE credentials: %v",
+	"获取IE书签失败: %v":                                                    "Failed to get IE bookmarks: %v",
+	"获取IE历史记录失败: %v":                                                  "Failed to get IE history: %v",
+	"数据文件: %s":                                                        "Data files: %s",
+	"打开SQLite数据库失败: %v":                                               "Failed to open the SQLite database: %v",
+	"连接SQLite数据库失败: %v":                                               "Failed to connect to the SQLite database: %v",
+	"获取表名列表时出错: %v":                                                   "Error listing tables: %v",
+	"检查表 %s 是否存在时出错: %v":                                              "Error checking whether table %s exists: %v",
+	"[-] 没有查询到%s该信息":                                                  "[-] No %s data found",
+	"获取表 %s 结构时出错: %v":                                                "Error getting the structure of table %s: %v",
+	"扫描表结构时出错: %v":                                                    "Error scanning the table structure: %v",
+	"表 %s 没有字段":                                                       "Table %s has no columns",
+	"查询表 %s 数据时出错: %v":                                                "Error querying table %s: %v",
+	"获取列信息时出错: %v":                                                    "Error getting column information: %v",
+	"扫描行数据时出错: %v":                                                    "Error scanning row data: %v",
+
+	// config
+	"读取配置文件失败: %v":     "Failed to read the configuration file: %v",
+	"第%d行格式错误: %s":     "Line %d is malformed: %s",
+	"第%d行 %s 的值错误: %v": "Line %d has an invalid value for %s: %v",
+	"字符串缺少结束引号":        "String is missing the closing quote",
+	"数组缺少结束括号":         "Array is missing the closing bracket",
+
+	// credential
+	"===== 凭据关联分析 =====":                "===== Credential correlation =====",
+	"凭据总数: %d，去重后: %d，重复: %d":           "Credentials: %d, unique: %d, duplicates: %d",
+	"[密码复用] 共 %d 组":                     "[Password reuse] %d groups",
+	"密码: %s  (%d 处，主机: %d，应用: %s)":      "Password: %s  (%d places, hosts: %d, applications: %s)",
+	"[用户名关联] 共 %d 个用户名":                 "[Usernames] %d usernames",
+	"用户名: %s  (主机: %d，不同密码: %d，应用: %s)": "Username: %s  (hosts: %d, distinct passwords: %d, applications: %s)",
+	"不支持的脱敏方式: %s，可选: %s":               "Unsupported redaction mode: %s, available: %s",
+	"[已隐藏]":              "[hidden]",
+	"长度不足8位":             "Shorter than 8 characters",
+	"字符类型少于3种":           "Fewer than 3 character classes",
+	"常见密码":               "Common password",
+	"包含用户名":              "Contains the username",
+	"包含主机名":              "Contains the hostname",
+	"容易被猜到":              "Easy to guess",
+	"极弱":                 "very weak",
+	"弱":                  "weak",
+	"一般":                 "fair",
+	"强":                  "strong",
+	"很强":                 "very strong",
+	"读取常见密码列表失败: %v":     "Failed to read the common password list: %v",
+	"合计":                 "Total",
+	"===== 密码强度评估 =====": "===== Password strength =====",
+	"策略: 至少8位、至少3种字符类型、不是常见密码、不包含用户名或主机名、强度不低于\"强\"": "Policy: at least 8 characters, at least 3 character classes, not a common password, no username or hostname, strength at least \"strong\"",
+	"应用":   "Application",
+	"数量":   "Count",
+	"弱密码":  "Weak",
+	"平均长度": "Avg length",
+	"含用户名": "Username",
+	"含主机名": "Hostname",
+	"符合策略": "Compliant",
+	"目标":   "Target",
+	"长度":   "Length",
+	"字符类型": "Classes",
+	"强度":   "Strength",
+	"猜测次数": "Guesses",
+	"问题":   "Issues",
+
+	// dbeaver
+	"PKCS7填充校验失败，密钥不正确":         "PKCS7 padding check failed, wrong key",
+	"读取文件失败: %v":                "Failed to read the file: %v",
+	"解析密钥失败: %v":                "Failed to parse the key: %v",
+	"解析IV失败: %v":                "Failed to parse the IV: %v",
+	"密文长度 %d 不是16的倍数":           "Ciphertext length %d is not a multiple of 16",
+	"密钥长度 %d 字节错误，应为16、24或32字节": "Invalid key length %d bytes, must be 16, 24 or 32 bytes",
+	"IV长度 %d 字节错误，应为16字节":       "Invalid IV length %d bytes, must be 16 bytes",
+	"创建AES加密器失败: %v":            "Failed to create the AES cipher: %v",
+	"解析凭据JSON失败: %v":            "Failed to parse the credentials JSON: %v",
+	"读取数据源文件失败: %v":             "Failed to read the data sources file: %v",
+	"解析数据源JSON失败: %v":           "Failed to parse the data sources JSON: %v",
+	"项目: %s":                    "Project: %s",
+	"连接名称: %s":                  "Connection name: %s",
+	"文件夹: %s":                   "Folder: %s",
+	"驱动: %s (%s)":               "Driver: %s (%s)",
+	"主机: %s":                    "Host: %s",
+	"端口: %s":                    "Port: %s",
+	"数据库: %s":                   "Database: %s",
+	"认证方式: %s":                  "Authentication: %s",
+	"用户名: %s":                   "User: %s",
+	"密码: %s":                    "Password: %s",
+	"状态: %s":                    "Status: %s",
+	"%s 用户名: %s":                "%s user: %s",
+	"%s 密码: %s":                 "%s password: %s",
+	"项目 %s 凭据文件无法解密: %v":        "Credentials file of project %s cannot be decrypted: %v",
+	"数据源文件不存在: %s":              "Data sources file does not exist: %s",
+	"未找到DBeaver工作区: %s":         "DBeaver workspace not found: %s",
+	"Eclipse安全存储: %s":           "Eclipse secure storage: %s",
+	"未找到DBeaver工作区":             "DBeaver workspace not found",
+	"不存在":                       "missing",
+	"存在":                        "present",
+	"项目: %s\n  数据源: %d 个，保存密码: %d 个，credentials-config.json: %s": "Project: %s\n  data sources: %d, saved passwords: %d, credentials-config.json: %s",
+	"已解密":                    "decrypted",
+	"data-sources.json中明文保存": "stored in plaintext in data-sources.json",
+	"连接未勾选保存密码":              "\"Save password\" is not checked for this connection",
+	"凭据文件无法用默认密钥解密，项目可能设置了密码":                        "the credentials file cannot be decrypted with the default key, the project may have a password",
+	"密码保存在Eclipse安全存储(secure_storage)中，需要在目标用户会话中解密": "the password is stored in the Eclipse secure storage (secure_storage) and must be decrypted in the target user's session",
+	"密码保存在Windows凭据管理器中":                             "the password is stored in the Windows Credential Manager",
+	"凭据文件中没有该连接的密码":                                  "the credentials file has no password for this connection",
+
+	// decode
+	"密文为空":              "Empty value",
+	"密钥派生: %s":          "key derivation: %s",
+	"不支持的类型: %s，可选: %s": "Unsupported type: %s, available: %s",
+	"Navicat版本号错误: %s":  "Invalid Navicat version: %s",
+	"主密码加密":             "master password encryption",
+	"解密失败，请检查 -host 和 -user 是否与会话一致": "Decryption failed, check that -host and -user match the session",
+	"base64解码失败: %v":    "base64 decoding failed: %v",
+	"密文需要是hex或base64编码": "The value must be hex or base64 encoded",
+
+	// filezilla
+	"FTPS(隐式)":            "FTPS (implicit)",
+	"FTPES(显式)":           "FTPES (explicit)",
+	"FTP(不加密)":            "FTP (unencrypted)",
+	"WebDAV(不加密)":         "WebDAV (unencrypted)",
+	"匿名":                  "Anonymous",
+	"普通":                  "Normal",
+	"询问密码":                "Ask for password",
+	"交互式":                 "Interactive",
+	"账户":                  "Account",
+	"密钥文件":                "Key file",
+	"配置文件":                "Profile",
+	"未知(%s)":              "unknown (%s)",
+	"[-] base64解码失败: %v":  "[-] base64 decoding failed: %v",
+	"%s (编码: %s)":         "%s (encoding: %s)",
+	"获取用户配置目录失败: %v":      "Failed to get the user configuration directory: %v",
+	"FileZilla 目录不存在: %s": "FileZilla directory does not exist: %s",
+	"主密码: 已启用，保存的密码使用主密码加密，可使用 -filezilla-master-password 指定主密码解密": "Master password: enabled, saved passwords are encrypted with it, use -filezilla-master-password to decrypt them",
+	"主密码: 已启用，提供的主密码校验通过":                                          "Master password: enabled, the given master password is correct",
+	"主密码: 已启用，提供的主密码校验失败":                                          "Master password: enabled, the given master password is wrong",
+	"Kiosk模式: %s，FileZilla不会保存密码":                                  "Kiosk mode: %s, FileZilla does not save passwords",
+	"查找 XML 文件失败: %v":                                              "Failed to find XML files: %v",
+	"从文件解析: %s":                                                    "Parsed from file: %s",
+	"未找到有效的 FileZilla 服务器配置":                                       "No valid FileZilla server configuration found",
+	"名称: %s":      "Name: %s",
+	"来源: 最近连接":    "Source: recent servers",
+	"协议: %s":      "Protocol: %s",
+	"登录类型: %s":    "Logon type: %s",
+	"用户: %s":      "User: %s",
+	"密码: 未保存":     "Password: not saved",
+	"账户: %s":      "Account: %s",
+	"密钥文件: %s":    "Key file: %s",
+	"备注: %s":      "Comments: %s",
+	"XML解析错误: %v": "XML parse error: %v",
+	"配置目录: %s":    "Configuration directory: %s",
+	"主密码: 已启用":    "Master password: enabled",
+	"%s: 站点 %d 个，保存密码 %d 个，主密码保护 %d 个":            "%s: sites %d, saved passwords %d, protected by master password %d",
+	"密码受主密码保护，需要使用 -filezilla-master-password 指定": "The password is protected by a master password, specify it with -filezilla-master-password",
+	"主密码错误":            "Wrong master password",
+	"公钥base64解码失败: %v": "Failed to base64 decode the public key: %v",
+	"公钥长度错误: %d":       "Invalid public key length: %d",
+	"密文长度不足":           "Ciphertext too short",
+	"不是主密码加密的密文":       "Not a master password encrypted value",
+	"解密失败: %v":         "Decryption failed: %v",
+	"[主密码保护] %v":       "[master password protected] %v",
+
+	// finalshell
+	"(代理)":                  "(proxy)",
+	"密码":                    "Password",
+	"公钥":                    "Public key",
+	"未知(%d)":                "unknown (%d)",
+	"类型: %s":                "Type: %s",
+	"密码: 解密失败(%v)":          "Password: decryption failed (%v)",
+	"私钥: %s":                "Private key: %s",
+	"代理: %s %s:%s":          "Proxy: %s %s:%s",
+	"代理: %s":                "Proxy: %s",
+	"描述: %s":                "Description: %s",
+	"获取用户目录失败: %v":          "Failed to get the user directory: %v",
+	"FinalShell连接目录不存在: %s": "FinalShell connection directory does not exist: %s",
+	"正在扫描FinalShell连接目录":    "Scanning the FinalShell connection directory",
+	"读取 %s 失败: %v":          "Failed to read %s: %v",
+	"未找到FinalShell连接信息":     "No FinalShell connections found",
+	"文件: %s":                "File: %s",
+	"连接目录: %s\n  连接: %d 个，保存密码: %d 个": "Connection directory: %s\n  connections: %d, saved passwords: %d",
+
+	// htmlreport
+	"创建报告文件失败: %v":   "Failed to create the report file: %v",
+	"生成HTML报告失败: %v": "Failed to render the HTML report: %v",
+	"成功的模块":          "Modules succeeded",
+	"失败的模块":          "Modules failed",
+	"凭据(去重)":         "Credentials (unique)",
+	"复用的密码":          "Reused passwords",
+	"敏感配置命中":         "Sensitive config hits",
+	"密码已脱敏":          "Passwords are redacted",
+	"报告包含明文密码，请妥善保管": "This report contains plaintext passwords, keep it safe",
+	"立即更换，在密码策略中启用弱口令字典检查，禁止常见密码及其简单变形":                                  "Change immediately and enable a weak password dictionary check in the password policy that rejects common passwords and simple variations",
+	"密码中不得包含用户名，在密码策略中加入相应检查":                                            "Passwords must not contain the username, add a check to the password policy",
+	"密码中不得包含主机名或系统名称":                                                    "Passwords must not contain the hostname or system name",
+	"密码长度至少12位，优先使用口令短语":                                                 "Use at least 12 characters, preferably a passphrase",
+	"混合使用大小写字母、数字和符号，或使用更长的口令短语":                                         "Mix upper and lower case letters, digits and symbols, or use a longer passphrase",
+	"使用密码管理器生成的随机密码":                                                     "Use random passwords generated by a password manager",
+	"Navicat 保存的密码使用固定密钥加密，可被直接解密，建议不保存密码，服务器改用密钥认证并限制来源地址":              "Navicat encrypts saved passwords with a fixed key and they can be decrypted directly; do not save passwords, use key authentication on the servers and restrict source addresses",
+	"为项目设置密码或启用安全存储(Secure Storage)，避免使用默认密钥加密的 credentials-config.json": "Set a project password or enable Secure Storage instead of credentials-config.json encrypted with the default key",
+	"在 WinSCP 中启用主密码，或不在会话中保存密码":                                         "Enable a master password in WinSCP, or do not save passwords in sessions",
+	"在 FileZilla 中启用主密码保护保存的密码":                                          "Enable a master password in FileZilla to protect saved passwords",
+	"FinalShell 使用固定算法加密保存的密码，建议改用密钥认证且不保存密码":                            "FinalShell encrypts saved passwords with a fixed algorithm; use key authentication and do not save passwords",
+	"启用 Xshell 的主密码，或改用公钥认证":                                             "Enable the Xshell master password, or use public key authentication",
+	"启用 Xftp 的主密码，或改用公钥认证":                                               "Enable the Xftp master password, or use public key authentication",
+	"浏览器保存的密码在当前用户下可直接解密，建议使用带主密码的密码管理器，并关闭浏览器的密码保存功能":                   "Passwords saved by browsers can be decrypted directly as the current user; use a password manager with a master password and turn off password saving in the browser",
+	"密码复用": "Password reuse",
+	"为每个系统使用不同的密码，立即更换复用的密码，并检查相关系统的登录记录": "Use a different password for every system, change reused passwords immediately and review the login history of the affected systems",
+	"配置文件中的明文敏感信息": "Plaintext secrets in configuration files",
+	"从配置文件中移除明文密钥，改用环境变量或密钥管理服务，并轮换已暴露的密钥": "Remove plaintext secrets from configuration files, use environment variables or a secret manager, and rotate the exposed secrets",
+	"%s 保存的凭据": "Credentials saved by %s",
+	"凭据收集报告":   "Credential collection report",
+	"生成时间":     "Generated",
+	"主机":       "Host",
+	"概览":       "Summary",
+	"执行状态":     "Module status",
+	"各应用凭据":    "Credentials by application",
+	"未解密出任何凭据": "No credentials were decrypted",
+	"名称":       "Name",
+	"整改建议":     "Remediation",
+	"未发现跨主机或跨应用复用的密码": "No password is reused across hosts or applications",
+	"使用位置":       "Used at",
+	"用户名关联":      "Usernames",
+	"用户名":        "Username",
+	"不同密码":       "Distinct passwords",
+	"敏感配置信息搜索结果": "Sensitive configuration search",
+	"未搜索到敏感配置信息": "No sensitive configuration found",
+	"文件":         "File",
+	"行号":         "Line",
+	"问题与整改建议":    "Findings and remediation",
+	"附录: 原始结果":   "Appendix: raw results",
+	"凭据存储清点":     "Credential store inventory",
+	"各模块结果":      "Module results",
+	"已启用脱敏，原始结果中含有明文密码，未包含在报告中": "Redaction is enabled; the raw results contain plaintext passwords and are not included",
+
+	// navicat
+	"未知":                     "unknown",
+	"[+] 产品: %s, 连接名称: %s":   "[+] Product: %s, connection name: %s",
+	"连接类型: %s":               "Connection type: %s",
+	"主机: %s, 端口: %s":         "Host: %s, port: %s",
+	"密码: %s (Navicat%d算法)":   "Password: %s (Navicat%d algorithm)",
+	"密码: %s (密文: %s)":        "Password: %s (ciphertext: %s)",
+	"SSH隧道: %s:%s, 用户名: %s":  "SSH tunnel: %s:%s, user: %s",
+	", 认证方式: %s":             ", authentication: %s",
+	", 密码: %s":               ", password: %s",
+	", 私钥: %s":               ", private key: %s",
+	", 私钥密码: %s":             ", private key passphrase: %s",
+	"HTTP隧道: %s":             "HTTP tunnel: %s",
+	", 用户名: %s, 密码: %s":      ", user: %s, password: %s",
+	", 客户端证书: %s, 客户端私钥: %s": ", client certificate: %s, client key: %s",
+	", 代理: %s:%s":            ", proxy: %s:%s",
+	"用户名: %s 密码: %s":         "User: %s password: %s",
+	"客户端私钥":                  "Client key",
+	"客户端证书":                  "Client certificate",
+	"CA证书":                   "CA certificate",
+	"加密套件":                   "Cipher",
+	"模式":                     "Mode",
+	"私钥密码":                   "Key passphrase",
+	"打开注册表项失败: %v":           "Failed to open the registry key: %v",
+	"读取子键失败: %v":             "Failed to read subkeys: %v",
+	"连接: %d 个，保存密码: %d 个":    "connections: %d, saved passwords: %d",
+	"未找到Navicat连接":           "No Navicat connections found",
+	"无密码":                    "no password",
+	"十六进制解码失败: %v":           "Hex decoding failed: %v",
+	"创建Blowfish密码器失败: %v":    "Failed to create the Blowfish cipher: %v",
+	"密文长度不是%d的倍数":            "Ciphertext length is not a multiple of %d",
+	"创建AES密码器失败: %v":         "Failed to create the AES cipher: %v",
+	"无法识别加密版本":               "Unable to detect the encryption version",
+	"解析NCX文件失败: %v":          "Failed to parse the NCX file: %v",
+	"[+] 成功解析指定文件，获取账密如下：":         "[+] Parsed the given file, credentials:",
+	"未解析到任何数据库连接信息，请检查 .ncx 文件格式！": "No database connections found, check the format of the .ncx file!",
+	"从注册表获取Navicat连接失败: %v":        "Failed to get Navicat connections from the registry: %v",
+	"[+] 成功从注册表获取保存的 Navicat 连接":   "[+] Got the saved Navicat connections from the registry",
+	"未找到任何包含密码的 Navicat 连接":        "No Navicat connections with passwords found",
+
+	"打开注册表项失败: %w":        "Failed to open the registry key: %w",
+	"不支持的Navicat加密版本: %d": "Unsupported Navicat encryption version: %d",
+
+	// notepad
+	"记事本TabState: %s\n  文件: %d 个": "Notepad TabState: %s\n  files: %d",
+	"Notepad++备份: %s\n  文件: %d 个": "Notepad++ backup: %s\n  files: %d",
+	"未找到记事本或Notepad++的缓存文件":       "No Notepad or Notepad++ cache files found",
+	"查找TabState路径失败: %v":          "Failed to find the TabState path: %v",
+	"TabState路径: %s":              "TabState path: %s",
+	"读取TabState目录失败: %v":          "Failed to read the TabState directory: %v",
+	"处理文件: %s":                    "Processing file: %s",
+	"找到 %d 个文件":                   "Found %d files",
+	"终止进程失败: %v, %s":              "Failed to terminate the process: %v, %s",
+	"获取AppData路径失败: %v":           "Failed to get the AppData path: %v",
+	"读取Packages目录失败: %v":          "Failed to read the Packages directory: %v",
+	"未找到记事本TabState路径":            "Notepad TabState path not found",
+	"文件数据不完整":                     "Incomplete file data",
+	"状态: ( 已保存在本地的文件 √)":          "Status: ( saved to a local file √)",
+	"状态: ( 未保存本地的临时文件 √)":         "Status: ( unsaved temporary file √)",
+	"[-] 文件数据不完整，无法读取文件路径":        "[-] Incomplete file data, unable to read the file path",
+	"文件名: %s":                     "File name: %s",
+	"文件数据不完整，无法读取内容长度":            "Incomplete file data, unable to read the content length",
+	"内容长度: %d":                    "Content length: %d",
+	"文件数据不完整，无法读取内容":              "Incomplete file data, unable to read the content",
+	"内容区域计算错误":                    "Content range calculation error",
+	"内容:":                         "Content:",
+	"[*] 文件数据不完整，长度不足":            "[*] Incomplete file data, not long enough",
+	"获取用户名失败: %v":                 "Failed to get the user name: %v",
+	"目录 %s 不存在":                   "Directory %s does not exist",
+	"读取目录失败: %v":                  "Failed to read the directory: %v",
+	"总文件数: %d":                    "Total files: %d",
+	"读取文件: %s":                    "Reading file: %s",
+	"----( 已保存文件  √)----":         "----( saved file  √)----",
+	"----(未保存文件 ×)----":           "----(unsaved file ×)----",
+	"文件路径长度: %d":                  "File path length: %d",
+	"尝试提取内容:":                     "Trying to extract content:",
+	"--- 从位置 %d 开始的内容 ---":        "--- Content from offset %d ---",
+	"--- 整个文件内容 ---":              "--- Whole file content ---",
+
+	// regsource
+	"不是有效的hive文件":    "Not a valid hive file",
+	"hive根键损坏":       "Corrupted hive root key",
+	"cell偏移越界: 0x%x": "Cell offset out of range: 0x%x",
+	"cell大小错误: 0x%x": "Invalid cell size: 0x%x",
+	"键记录损坏: 0x%x":    "Corrupted key record: 0x%x",
+	"子键索引嵌套过深":       "Subkey index nested too deeply",
+	"子键索引损坏":         "Corrupted subkey index",
+	"未知的子键索引类型: %q":  "Unknown subkey index type: %q",
+	"值列表损坏":          "Corrupted value list",
+	"大数据记录损坏":        "Corrupted big data record",
+	"值数据越界":          "Value data out of range",
+	"文件为空":           "Empty file",
+	"不是有效的.reg文件":    "Not a valid .reg file",
+	"注册表项不存在":        "Registry key does not exist",
+	"注册表值类型不匹配":      "Registry value type mismatch",
+	"注册表仅在Windows上可用，请使用 -reg-file 或 -hive-* 指定离线数据": "The registry is only available on Windows, use -reg-file or -hive-* to specify offline data",
+
+	// remotecontrol
+	"ToDesk远程控制":     "ToDesk remote control",
+	"向日葵远程控制":        "Sunlogin remote control",
+	"程序路径":           "Program path",
+	"安装路径":           "Install path",
+	"配置文件路径":         "Config file path",
+	"用户路径":           "User path",
+	"打开配置文件错误: %v":   "Error opening the configuration file: %v",
+	"读取配置文件错误: %v":   "Error reading the configuration file: %v",
+	"设备代码":           "Device code",
+	"版本号":            "Version",
+	"手机号":            "Phone number",
+	"邮箱":             "Email",
+	"登录规则":           "Logon rule",
+	"仅使用临时密码登录":      "Temporary password only",
+	"仅使用安全密码登录":      "Security password only",
+	"临时密码和安全密码均可登录":  "Temporary or security password",
+	"账号":             "Account",
+	"连接ID":           "Connection ID",
+	"临时密码":           "Temporary password",
+	"安全密码":           "Security password",
+	"设备识别码":          "Device identifier",
+	"%s 未安装":         "%s is not installed",
+	"%s: 已安装":        "%s: installed",
+	"配置文件: %s":       "Configuration file: %s",
+	"配置文件: %s (不存在)": "Configuration file: %s (missing)",
+	"状态: 正在运行，可读取进程内存":  "Status: running, process memory can be read",
+	"状态: 未运行":           "Status: not running",
+	"无法打开进程: %v":        "Unable to open the process: %v",
+	"无法查找内存: %v":        "Unable to query memory: %v",
+	"无法读取内存: %v":        "Unable to read memory: %v",
+	"验证码":               "Verification code",
+	"不支持的远程控制软件类型: %s":  "Unsupported remote control software: %s",
+	"===== %s 信息 =====": "===== %s =====",
+	"--- 注册表信息 ---":     "--- Registry ---",
+	"--- 配置文件信息 ---":    "--- Configuration file ---",
+	"--- 运行状态 ---":      "--- Running state ---",
+	"状态: 正在运行":          "Status: running",
+	"--- 内存信息 ---":      "--- Memory ---",
+
+	// search
+	"路径 %s 不存在，请输入正确路径":       "Path %s does not exist, please enter a valid path",
+	"编译正则表达式失败: %v":           "Failed to compile the regular expression: %v",
+	"正在搜索文件，这可能需要一些时间，请稍候...": "Searching files, this may take a while, please wait...",
+	"获取绝对路径失败: %v":            "Failed to get the absolute path: %v",
+	"扫描文件":                    "Scanning file",
+	"搜索完成":                    "Search finished",
+	"正在扫描有效文件... %d":          "Scanning candidate files... %d",
+	"搜索文件时出错: %v":             "Error while searching files: %v",
+	"文件类型: %s":                "File types: %s",
+	"文件大小限制: %d 字节，跳过目录: %s":  "Size limit: %d bytes, skipped directories: %s",
+	"正则表达式 %d 个:":             "%d regular expressions:",
+
+	// status
+	"未安装":              "not installed",
+	"未找到":              "not found",
+	"权限不足":             "access denied",
+	"受主密码保护":           "master password protected",
+	"不支持的版本":           "unsupported version",
+	"解密失败":             "decryption failed",
+	"成功":               "ok",
+	"失败":               "failed",
+	"模块":               "Module",
+	"状态":               "Status",
+	"条目":               "Items",
+	"耗时":               "Time",
+	"说明":               "Details",
+	"===== 执行状态 =====": "===== Run status =====",
+
+	// winscp
+	"(SSH隧道)":                    "(SSH tunnel)",
+	"注册表: HKEY_CURRENT_USER\\%s": "Registry: HKEY_CURRENT_USER\\%s",
+	"未找到 WinSCP 连接信息":            "No WinSCP connections found",
+	"会话: %d 个，保存密码: %d 个":        "sessions: %d, saved passwords: %d",
+	"配置启用了主密码，需要使用 -winscp-master-password 指定": "The configuration has a master password, specify it with -winscp-master-password",
+	"主密码校验失败":          "Master password check failed",
+	"[主密码保护] 解密失败: %v": "[master password protected] decryption failed: %v",
+	"无":                "None",
+	"会话名称: %s":         "Session name: %s",
+	"主机名: %s":          "Host name: %s",
+	"加密密码: %s":         "Encrypted password: %s",
+	"解密密码: %s":         "Decrypted password: %s",
+	"私钥文件: %s":         "Private key file: %s",
+	"代理用户名: %s":        "Proxy user: %s",
+	"代理密码: %s":         "Proxy password: %s",
+	"SSH隧道: %s:%s":     "SSH tunnel: %s:%s",
+	"隧道用户名: %s":        "Tunnel user: %s",
+	"隧道密码: %s":         "Tunnel password: %s",
+	"隧道私钥文件: %s":       "Tunnel private key file: %s",
+	"主密码: 已启用，未提供主密码，会话密码无法解密":                "Master password: enabled, none given, session passwords cannot be decrypted",
+	"主密码: 已启用，使用提供的主密码解密":                     "Master password: enabled, decrypting with the given master password",
+	"=== WinSCP 注册表信息 ===":                    "=== WinSCP registry ===",
+	"注册表位置: HKEY_CURRENT_USER\\%s":            "Registry location: HKEY_CURRENT_USER\\%s",
+	"未找到 WinSCP 注册表位置: HKEY_CURRENT_USER\\%s": "WinSCP registry location not found: HKEY_CURRENT_USER\\%s",
+	"=== WinSCP 配置文件信息 ===":                   "=== WinSCP configuration file ===",
+	"配置文件位置: %s":                              "Configuration file location: %s",
+	"配置文件解析失败: %v":                            "Failed to parse the configuration file: %v",
+
+	// xshell
+	"获取%s用户数据路径失败: %v":                   "Failed to get the %s user data path: %v",
+	"会话目录: %s":                           "Session directory: %s",
+	"会话: %d 个，保存密码: %d 个，UserKeys: %d 个": "sessions: %d, saved passwords: %d, UserKeys: %d",
+	"未找到%s会话目录":                          "%s session directory not found",
+	"固定密钥":                               "fixed key",
+	"SHA256(用户名+SID)":                    "SHA256(user name+SID)",
+	"SHA256(反转(反转用户名+SID))":              "SHA256(reverse(reversed user name+SID))",
+	"所有密钥派生方式均校验失败(版本: %s)，请检查用户名和SID":       "Every key derivation failed the check (version: %s), check the user name and SID",
+	"主密码已启用，需要使用 -xshell-master-password 指定": "A master password is enabled, specify it with -xshell-master-password",
+	"解密结果校验失败":                  "Decrypted data failed the checksum",
+	"加密数据长度不足":                  "Encrypted data too short",
+	"键盘交互":                      "Keyboard interactive",
+	"会话目录不存在: %s":               "Session directory does not exist: %s",
+	"遍历目录失败: %v":                "Failed to walk the directory: %v",
+	"会话路径: %s":                  "Session path: %s",
+	"用户密钥: %s (文件: %s)":         "User key: %s (file: %s)",
+	"用户密钥: %s (UserKeys目录中未找到)": "User key: %s (not found in the UserKeys directory)",
+	"密钥口令: 解密失败(%v)":            "Key passphrase: decryption failed (%v)",
+	"密钥口令: %s":                  "Key passphrase: %s",
+	"端口转发: %s":                  "Port forwarding: %s",
+	"版本: %s":                    "Version: %s",
+	"正在扫描%s...":                 "Scanning %s...",
+	"未找到%s用户数据路径":               "%s user data path not found",
+	"获取用户SID失败: %v，离线解密请使用 -xshell-user 和 -xshell-sid 指定": "Failed to get the user SID: %v, use -xshell-user and -xshell-sid for offline decryption",
+	"检查主密码失败: %v":    "Failed to check the master password: %v",
+	"枚举%s文件失败: %v":   "Failed to enumerate %s files: %v",
+	"解析%s文件失败: %v":   "Failed to parse the %s file: %v",
+	"UserKeys目录: %s": "UserKeys directory: %s",
+	"%s (未被会话引用)":    "%s (not referenced by any session)",
+	"无法获取SID对应的用户名，请使用 -xshell-user 指定: %v": "Unable to resolve the user name of the SID, specify it with -xshell-user: %v",
+	"无法获取当前用户名":                             "Unable to get the current user name",
+	"打开注册表失败: %v":                           "Failed to open the registry: %v",
+	"无法获取用户SID":                             "Unable to get the user SID",
+	"开始获取用户路径":                              "Getting the user path",
+	"获取用户路径成功":                              "Got the user path",
+	"读取主密码文件失败: %v":                         "Failed to read the master password file: %v",
+	"Base64解码失败: %v":                        "Base64 decoding failed: %v",
+	"SHA256(主密码)":                           "SHA256(master password)",
+	"未找到%s会话文件":                             "No %s session files found",
+	"打开注册表失败: %w":                           "Failed to open the registry: %w",
+
+	// main
+	"用法: e0e1-config report [选项] [模块...]\n模块: %s，默认全部\n\n选项:": "Usage: e0e1-config report [options] [module...]\nModules: %s, all by default\n\nOptions:",
+	"%s扫描失败: %v":         "%s scan failed: %v",
+	"敏感配置信息搜索":           "sensitive config search",
+	"模块执行状态":             "Module status",
+	"只输出警告和错误":           "Only log warnings and errors",
+	"输出调试信息":             "Log debug messages",
+	"输出更详细的调试信息":         "Log more detailed debug messages",
+	"日志格式: %s，日志输出到标准错误": "Log format: %s, logs are written to stderr",
+	"同时执行的模块数量，1为依次执行":   "Number of modules to run at the same time, 1 runs them one after another",
+	"并发数必须大于0: %d":       "Concurrency must be greater than 0: %d",
+	"对解密出的凭据去重并分析跨主机、跨应用的密码复用":                  "Deduplicate decrypted credentials and analyse password reuse across hosts and applications",
+	"评估解密出的密码强度，并按应用统计":                         "Score the strength of decrypted passwords with per-application statistics",
+	"只输出凭据分析和强度评分，不输出各模块结果和明文密码":                "Only output the credential analysis and strength scores, without module results or plaintext passwords",
+	"指定常见密码列表文件(每行一个)，追加到内置列表":                  "Common password list file (one per line), added to the built-in list",
+	"分析结果和HTML报告中密码的脱敏方式 (none, partial, full)": "How passwords are redacted in the analysis and the HTML report (none, partial, full)",
+	"报告格式 (text, html)，默认根据 -output 的扩展名判断":     "Report format (text, html), chosen from the -output extension by default",
+	"不支持的报告格式: %s，可选: %s":                       "Unsupported report format: %s, available: %s",
+	"报告已保存到: %s": "Report saved to: %s",
+
+	// logger
+	"不支持的日志格式: %s，可选: %s": "Unsupported log format: %s, available: %s",
+
+	// help
+	"配置扫描利用工具": "configuration scanning and exploitation tool",
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Langs 支持的输出语言，zh为默认语言
+var Langs = []string{"zh", "en"}
+
+var lang = "zh"
+
+// catalogs 以代码中的原文(去掉首尾空白)为键的翻译表，原文大多是中文，
+// 浏览器模块的原文是英文，所以中文也需要一个翻译表
+var catalogs = map[string]map[string]string{
+	"zh": zh,
+	"en": en,
+}
+
+func SetLang(l string) error {
+	l = strings.ToLower(strings.TrimSpace(l))
+	if l == "" {
+		return nil
+	}
+	for _, name := range Langs {
+		if name == l {
+			lang = l
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的语言: %s，可选: %s", l, strings.Join(Langs, ", "))
+}
+
+func Lang() string {
+	return lang
+}
+
+// T 返回当前语言的文本，首尾空白不参与查找，没有翻译时返回原文
+func T(msg string) string {
+	catalog := catalogs[lang]
+	if catalog == nil {
+		return msg
+	}
+	if translated, ok := catalog[msg]; ok {
+		return translated
+	}
+
+	core := strings.TrimSpace(msg)
+	translated, ok := catalog[core]
+	if !ok || core == "" {
+		return msg
+	}
+	start := strings.Index(msg, core)
+	return msg[:start] + translated + msg[start+len(core):]
+}
+
+func Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(T(format), a...)
+}
+
+func Printf(format string, a ...interface{}) {
+	fmt.Print(Sprintf(format, a...))
+}
+
+func Errorf(format string, a ...interface{}) error {
+	return fmt.Errorf(T(format), a...)
+}
+
+// New 用于包级别的错误变量，Error() 时才翻译，保证使用 -lang 设置后的语言
+func New(msg string) error {
+	return &message{msg}
+}
+
+type message struct {
+	text string
+}
+
+func (m *message) Error() string {
+	return T(m.text)
+}
@@ -0,0 +1,34 @@
+package i18n
+
+// zh 浏览器模块的提示原文是英文，中文输出时使用该翻译表
+var zh = map[string]string{
+	"%s Not Found!": "%s 不存在!",
+	"Not Found SystemKey OR Not Administrator Privileges!": "未找到系统密钥或没有管理员权限!",
+	"Failed to parse bookmark data: %v":                    "解析书签数据失败: %v",
+	"Bookmark data extracted successfully":                 "书签数据提取成功",
+	"[+] Get %s Login Data":                                "[+] 获取 %s 登录数据",
+	"Get %s Login Data":                                    "获取 %s 登录数据",
+	"Get %s Bookmarks":                                     "获取 %s 书签",
+	"Get %s Cookie":                                        "获取 %s Cookie",
+	"Get %s History":                                       "获取 %s 历史记录",
+	"Get %s Downloads":                                     "获取 %s 下载记录",
+	"Get %s Sessions":                                      "获取 %s 会话",
+	"Get %s Form History":                                  "获取 %s 表单历史",
+	"Cookie file not found":                                "未找到Cookie文件",
+	"========================== %s (Current User) ==========================": "========================== %s (当前用户) ==========================",
+	"========================== IE (Current User) ==========================": "========================== IE (当前用户) ==========================",
+	"file does not exist":                                       "文件不存在",
+	"DPAPI is only available on Windows":                        "DPAPI仅在Windows上可用",
+	"IE is only available on Windows":                           "IE仅在Windows上可用",
+	"profile path not found":                                    "未找到配置目录",
+	"primary password is set, supply it with -firefox-password": "已设置主密码，请使用 -firefox-password 指定",
+	"primary password is incorrect":                             "主密码错误",
+	"no cookie data found":                                      "没有找到Cookie数据",
+	"no history data found":                                     "没有找到历史记录数据",
+	"no visit history data found":                               "没有找到访问历史数据",
+	"no attribute data found":                                   "没有找到属性数据",
+	"download attribute ID not found":                           "没有找到下载属性ID",
+	"no annotation data found":                                  "没有找到注释数据",
+	"no places data found":                                      "没有找到地址数据",
+	"no form history data found":                                "没有找到表单历史数据",
+}
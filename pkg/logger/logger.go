@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"e0e1-config/pkg/i18n"
+)
+
+// LevelTrace -vv 时输出的逐条明细，比 Debug 更详细
+const LevelTrace = slog.LevelDebug - 4
+
+// Formats 支持的日志格式
+var Formats = []string{"text", "json"}
+
+var (
+	output = &terminal{w: os.Stderr}
+	format = "text"
+	level  = new(slog.LevelVar)
+	std    = slog.New(newHandler(output, format))
+)
+
+// Setup 设置日志级别和格式，日志统一写到标准错误，标准输出只保留结果
+func Setup(l slog.Level, f string) error {
+	f = strings.ToLower(strings.TrimSpace(f))
+	if f == "" {
+		f = "text"
+	}
+	if f != "text" && f != "json" {
+		return i18n.Errorf("不支持的日志格式: %s，可选: %s", f, strings.Join(Formats, ", "))
+	}
+	level.Set(l)
+	format = f
+	std = slog.New(newHandler(output, format))
+	return nil
+}
+
+// LevelFor 根据 -q、-v、-vv 计算日志级别，-q 只保留警告和错误
+func LevelFor(quiet bool, verbose int) slog.Level {
+	switch {
+	case quiet:
+		return slog.LevelWarn
+	case verbose >= 2:
+		return LevelTrace
+	case verbose == 1:
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+// newHandler 终端上阅读的文本日志不需要时间，JSON 日志保留时间便于留存
+func newHandler(w io.Writer, f string) slog.Handler {
+	options := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				if f == "text" {
+					return slog.Attr{}
+				}
+			case slog.LevelKey:
+				if a.Value.Any().(slog.Level) <= LevelTrace {
+					a.Value = slog.StringValue("TRACE")
+				}
+			}
+			return a
+		},
+	}
+	if f == "json" {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// JSON 当前是否输出JSON格式的日志
+func JSON() bool {
+	return format == "json"
+}
+
+func Enabled(l slog.Level) bool {
+	return std.Enabled(context.Background(), l)
+}
+
+func Log(l slog.Level, msg string, args ...any) {
+	std.Log(context.Background(), l, msg, args...)
+}
+
+func Trace(msg string, args ...any) {
+	std.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+func Debug(msg string, args ...any) {
+	std.Debug(msg, args...)
+}
+
+func Info(msg string, args ...any) {
+	std.Info(msg, args...)
+}
+
+func Warn(msg string, args ...any) {
+	std.Warn(msg, args...)
+}
+
+func Error(msg string, args ...any) {
+	std.Error(msg, args...)
+}
+
+// Progress 在标准错误的同一行上刷新进度，只在文本格式且输出 Info 时显示
+func Progress(msg string) {
+	if format != "text" || !Enabled(slog.LevelInfo) {
+		return
+	}
+	output.mu.Lock()
+	defer output.mu.Unlock()
+	fmt.Fprintf(output.w, "\r%s\033[0K", msg)
+	output.progress = true
+}
+
+// EndProgress 清除进度行
+func EndProgress() {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+	output.clear()
+}
+
+// terminal 输出日志前先清除未结束的进度行，避免日志接在进度后面
+type terminal struct {
+	mu       sync.Mutex
+	w        io.Writer
+	progress bool
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clear()
+	return t.w.Write(p)
+}
+
+func (t *terminal) clear() {
+	if t.progress {
+		fmt.Fprint(t.w, "\r\033[0K")
+		t.progress = false
+	}
+}
+
+// Print 输出多行的文本内容(如状态汇总表)，JSON 格式时不输出，由调用方改为结构化记录
+func Print(l slog.Level, text string) {
+	if format != "text" || !Enabled(l) {
+		return
+	}
+	fmt.Fprint(output, text)
+}
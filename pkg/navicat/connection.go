@@ -0,0 +1,279 @@
+package navicat
+
+import (
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"fmt"
+	"strings"
+)
+
+// products 注册表 Software\PremiumSoft 下各产品的子键
+var products = map[string]string{
+	"navicat":        "MySQL",
+	"navicatmariadb": "MariaDB",
+	"navicatmssql":   "SQL Server",
+	"navicatora":     "Oracle",
+	"navicatpg":      "PostgreSQL",
+	"navicatsqlite":  "SQLite",
+	"navicatmongodb": "MongoDB",
+	"navicatredis":   "Redis",
+}
+
+// ncxProducts 导出的NCX文件中 ConnType 对应的产品
+var ncxProducts = map[string]string{
+	"MYSQL":      "MySQL",
+	"MARIADB":    "MariaDB",
+	"MSSQL":      "SQL Server",
+	"SQLSERVER":  "SQL Server",
+	"ORACLE":     "Oracle",
+	"POSTGRESQL": "PostgreSQL",
+	"PGSQL":      "PostgreSQL",
+	"SQLITE":     "SQLite",
+	"MONGODB":    "MongoDB",
+	"REDIS":      "Redis",
+}
+
+func ncxProductName(connType string) string {
+	if name, ok := ncxProducts[strings.ToUpper(connType)]; ok {
+		return name
+	}
+	if connType == "" {
+		return i18n.T("未知")
+	}
+	return connType
+}
+
+func productName(subKey string) string {
+	if name, ok := products[strings.ToLower(subKey)]; ok {
+		return name
+	}
+	return strings.TrimPrefix(subKey, "Navicat")
+}
+
+type SSHTunnel struct {
+	Host       string `json:"Host,omitempty"`
+	Port       string `json:"Port,omitempty"`
+	UserName   string `json:"UserName,omitempty"`
+	AuthMethod string `json:"AuthMethod,omitempty"`
+	Password   string `json:"Password,omitempty"`
+	PrivateKey string `json:"PrivateKey,omitempty"`
+	Passphrase string `json:"Passphrase,omitempty"`
+}
+
+type HTTPTunnel struct {
+	URL           string `json:"URL,omitempty"`
+	UserName      string `json:"UserName,omitempty"`
+	Password      string `json:"Password,omitempty"`
+	ProxyHost     string `json:"ProxyHost,omitempty"`
+	ProxyPort     string `json:"ProxyPort,omitempty"`
+	ProxyUserName string `json:"ProxyUserName,omitempty"`
+	ProxyPassword string `json:"ProxyPassword,omitempty"`
+	ClientKey     string `json:"ClientKey,omitempty"`
+	ClientCert    string `json:"ClientCert,omitempty"`
+	Passphrase    string `json:"Passphrase,omitempty"`
+}
+
+type SSLConfig struct {
+	ClientKey  string `json:"ClientKey,omitempty"`
+	ClientCert string `json:"ClientCert,omitempty"`
+	CACert     string `json:"CACert,omitempty"`
+	Cipher     string `json:"Cipher,omitempty"`
+	Mode       string `json:"Mode,omitempty"`
+	Passphrase string `json:"Passphrase,omitempty"`
+}
+
+// attrGetter 按名称读取连接属性，注册表的值名和NCX的属性名是一致的
+type attrGetter func(name string) string
+
+func (get attrGetter) first(names ...string) string {
+	for _, name := range names {
+		if value := get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (get attrGetter) enabled(name string) bool {
+	switch strings.ToLower(get(name)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// newConnection 根据属性构建连接，version为0时自动识别密码的加密版本
+func newConnection(product, name string, get attrGetter, version int) Connection {
+	conn := Connection{
+		Product:        product,
+		ConnectionName: name,
+		ConnType:       get("ConnType"),
+		Host:           get.first("Host", "Server"),
+		Port:           get("Port"),
+		Database:       get.first("InitialDatabase", "Database", "ServiceName", "DatabaseFileName"),
+		UserName:       get.first("UserName", "User"),
+		// Oracle 连接方式(Basic/TNS)和服务名类型
+		ServiceProvider: get("ServiceProvider"),
+		TNS:             get("TNS"),
+	}
+
+	if encrypted := get.first("Pwd", "Password"); encrypted != "" {
+		conn.EncryptedPassword = encrypted
+		password, cipherVersion, err := DecryptWithVersion(encrypted, version)
+		if err != nil {
+			password = "[-] " + err.Error()
+		}
+		conn.Password, conn.CipherVersion = password, cipherVersion
+	}
+
+	if get.enabled("UseSSH") || get.enabled("SSH") {
+		conn.SSH = &SSHTunnel{
+			Host:       get("SSH_Host"),
+			Port:       get("SSH_Port"),
+			UserName:   get("SSH_UserName"),
+			AuthMethod: get("SSH_AuthenMethod"),
+			Password:   decryptOptional(get("SSH_Password"), version),
+			PrivateKey: get("SSH_PrivateKey"),
+			Passphrase: decryptOptional(get("SSH_Passphrase"), version),
+		}
+	}
+
+	if get.enabled("UseHTTP") || get.enabled("HTTP") {
+		conn.HTTP = &HTTPTunnel{
+			URL:      get("HTTP_URL"),
+			UserName: get("HTTP_PA_UserName"),
+			Password: decryptOptional(get("HTTP_PA_Password"), version),
+		}
+		if get.enabled("HTTP_CA") {
+			conn.HTTP.ClientKey = get("HTTP_CA_ClientKey")
+			conn.HTTP.ClientCert = get("HTTP_CA_ClientCert")
+			conn.HTTP.Passphrase = decryptOptional(get("HTTP_CA_Passphrase"), version)
+		}
+		if get.enabled("HTTP_Proxy") {
+			conn.HTTP.ProxyHost = get("HTTP_Proxy_Host")
+			conn.HTTP.ProxyPort = get("HTTP_Proxy_Port")
+			conn.HTTP.ProxyUserName = get("HTTP_Proxy_UserName")
+			conn.HTTP.ProxyPassword = decryptOptional(get("HTTP_Proxy_Password"), version)
+		}
+	}
+
+	if get.enabled("UseSSL") || get.enabled("SSL") {
+		// Navicat 导出的属性名本身就拼写为 SSL_Clpher
+		conn.SSL = &SSLConfig{
+			ClientKey:  get("SSL_ClientKey"),
+			ClientCert: get("SSL_ClientCert"),
+			CACert:     get("SSL_CACert"),
+			Cipher:     get.first("SSL_Clpher", "SSL_Cipher"),
+			Mode:       get("SSL_PGSSLMode"),
+			Passphrase: decryptOptional(get.first("SSL_Passphrase", "SSL_ClientKeyPassword"), version),
+		}
+	}
+
+	return conn
+}
+
+// recordCredentials 记录成功解密的连接密码和SSH隧道密码，解密失败时密码为"[-] "开头的提示
+func recordCredentials(conn Connection) {
+	source := "Navicat " + conn.Product
+	if conn.EncryptedPassword != "" && !strings.HasPrefix(conn.Password, "[-]") {
+		credential.Add(credential.Credential{Source: source, Name: conn.ConnectionName, Host: conn.Host, Port: conn.Port, User: conn.UserName, Password: conn.Password})
+	}
+	if conn.SSH != nil && conn.SSH.Password != "" && !strings.HasPrefix(conn.SSH.Password, "[-]") {
+		credential.Add(credential.Credential{Source: source, Name: conn.ConnectionName + "(SSH)", Host: conn.SSH.Host, Port: conn.SSH.Port, User: conn.SSH.UserName, Password: conn.SSH.Password})
+	}
+}
+
+func decryptOptional(encrypted string, version int) string {
+	if encrypted == "" {
+		return ""
+	}
+	return DecryptPassword(encrypted, version)
+}
+
+func formatConnection(conn Connection) string {
+	var result strings.Builder
+
+	result.WriteString(i18n.Sprintf("[+] 产品: %s, 连接名称: %s\n", conn.Product, conn.ConnectionName))
+	if conn.ConnType != "" {
+		result.WriteString(i18n.Sprintf("  连接类型: %s\n", conn.ConnType))
+	}
+	if conn.Host != "" || conn.Port != "" {
+		result.WriteString(i18n.Sprintf("  主机: %s, 端口: %s\n", conn.Host, conn.Port))
+	}
+	if conn.ServiceProvider != "" {
+		result.WriteString(fmt.Sprintf("  ServiceProvider: %s\n", conn.ServiceProvider))
+	}
+	if conn.TNS != "" {
+		result.WriteString(fmt.Sprintf("  TNS: %s\n", conn.TNS))
+	}
+	if conn.Database != "" {
+		result.WriteString(i18n.Sprintf("  数据库: %s\n", conn.Database))
+	}
+	if conn.UserName != "" {
+		result.WriteString(i18n.Sprintf("  用户名: %s\n", conn.UserName))
+	}
+	if conn.EncryptedPassword != "" {
+		if conn.CipherVersion != 0 {
+			result.WriteString(i18n.Sprintf("  密码: %s (Navicat%d算法)\n", conn.Password, conn.CipherVersion))
+		} else {
+			result.WriteString(i18n.Sprintf("  密码: %s (密文: %s)\n", conn.Password, conn.EncryptedPassword))
+		}
+	}
+
+	if ssh := conn.SSH; ssh != nil {
+		result.WriteString(i18n.Sprintf("  SSH隧道: %s:%s, 用户名: %s", ssh.Host, ssh.Port, ssh.UserName))
+		if ssh.AuthMethod != "" {
+			result.WriteString(i18n.Sprintf(", 认证方式: %s", ssh.AuthMethod))
+		}
+		if ssh.Password != "" {
+			result.WriteString(i18n.Sprintf(", 密码: %s", ssh.Password))
+		}
+		if ssh.PrivateKey != "" {
+			result.WriteString(i18n.Sprintf(", 私钥: %s", ssh.PrivateKey))
+		}
+		if ssh.Passphrase != "" {
+			result.WriteString(i18n.Sprintf(", 私钥密码: %s", ssh.Passphrase))
+		}
+		result.WriteString("\n")
+	}
+
+	if http := conn.HTTP; http != nil {
+		result.WriteString(i18n.Sprintf("  HTTP隧道: %s", http.URL))
+		if http.UserName != "" || http.Password != "" {
+			result.WriteString(i18n.Sprintf(", 用户名: %s, 密码: %s", http.UserName, http.Password))
+		}
+		if http.ClientCert != "" || http.ClientKey != "" {
+			result.WriteString(i18n.Sprintf(", 客户端证书: %s, 客户端私钥: %s", http.ClientCert, http.ClientKey))
+			if http.Passphrase != "" {
+				result.WriteString(i18n.Sprintf(", 私钥密码: %s", http.Passphrase))
+			}
+		}
+		if http.ProxyHost != "" {
+			result.WriteString(i18n.Sprintf(", 代理: %s:%s", http.ProxyHost, http.ProxyPort))
+			if http.ProxyUserName != "" || http.ProxyPassword != "" {
+				result.WriteString(i18n.Sprintf(" 用户名: %s 密码: %s", http.ProxyUserName, http.ProxyPassword))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if ssl := conn.SSL; ssl != nil {
+		result.WriteString("  SSL:")
+		fields := []struct{ name, value string }{
+			{i18n.T("客户端私钥"), ssl.ClientKey},
+			{i18n.T("客户端证书"), ssl.ClientCert},
+			{i18n.T("CA证书"), ssl.CACert},
+			{i18n.T("加密套件"), ssl.Cipher},
+			{i18n.T("模式"), ssl.Mode},
+			{i18n.T("私钥密码"), ssl.Passphrase},
+		}
+		for _, field := range fields {
+			if field.value != "" {
+				result.WriteString(fmt.Sprintf(" %s: %s", field.name, field.value))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
@@ -0,0 +1,59 @@
+package navicat
+
+import (
+	"fmt"
+	"strings"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/regsource"
+)
+
+// InventoryNavicat 统计注册表中各Navicat产品保存的连接数量，不解密
+func InventoryNavicat() (string, error) {
+	baseKey := `Software\PremiumSoft`
+
+	key, err := regsource.OpenKey(regsource.CurrentUser, baseKey)
+	if err != nil {
+		return "", i18n.Errorf("打开注册表项失败: %v", err)
+	}
+	defer key.Close()
+
+	subKeys, err := key.SubKeyNames()
+	if err != nil {
+		return "", i18n.Errorf("读取子键失败: %v", err)
+	}
+
+	var result strings.Builder
+	for _, subKey := range subKeys {
+		if !strings.Contains(subKey, "Navicat") {
+			continue
+		}
+
+		serverKey, err := key.OpenSubKey(subKey + `\Servers`)
+		if err != nil {
+			continue
+		}
+		serverNames, _ := serverKey.SubKeyNames()
+
+		saved := 0
+		for _, serverName := range serverNames {
+			server, err := serverKey.OpenSubKey(serverName)
+			if err != nil {
+				continue
+			}
+			if value, err := regsource.GetString(server, "Pwd"); err == nil && value != "" {
+				saved++
+			}
+			server.Close()
+		}
+		serverKey.Close()
+
+		result.WriteString(fmt.Sprintf("%s: HKEY_CURRENT_USER\\%s\\%s\\Servers\n", productName(subKey), baseKey, subKey))
+		result.WriteString(i18n.Sprintf("  连接: %d 个，保存密码: %d 个\n", len(serverNames), saved))
+	}
+
+	if result.Len() == 0 {
+		return "", i18n.Errorf("未找到Navicat连接")
+	}
+	return result.String(), nil
+}
@@ -6,31 +6,42 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/xml"
-	"fmt"
 	"io/ioutil"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/regsource"
+	"e0e1-config/pkg/status"
 
 	"golang.org/x/crypto/blowfish"
-	"golang.org/x/sys/windows/registry"
 )
 
 var (
-	aesKey        = []byte("libcckeylibcckey")
-	aesIV         = []byte("libcciv libcciv ")
-	blowfishKey   = sha1Sum([]byte("3DC5CA39"))
-	blowfishIV    = hexDecode("d9c7c3c8870d64bd")
-	resultBuilder strings.Builder
+	aesKey      = []byte("libcckeylibcckey")
+	aesIV       = []byte("libcciv libcciv ")
+	blowfishKey = sha1Sum([]byte("3DC5CA39"))
+	blowfishIV  = hexDecode("d9c7c3c8870d64bd")
 )
 
 type Connection struct {
-	ConnectionName    string `json:"ConnectionName,omitempty"`
-	Host              string `json:"Host,omitempty"`
-	Port              string `json:"Port,omitempty"`
-	Database          string `json:"Database,omitempty"`
-	UserName          string `json:"UserName,omitempty"`
-	Password          string `json:"Password,omitempty"`
-	EncryptedPassword string `json:"EncryptedPassword,omitempty"`
-	ConnType          string `json:"ConnType,omitempty"`
+	Product           string      `json:"Product,omitempty"`
+	ConnectionName    string      `json:"ConnectionName,omitempty"`
+	Host              string      `json:"Host,omitempty"`
+	Port              string      `json:"Port,omitempty"`
+	Database          string      `json:"Database,omitempty"`
+	UserName          string      `json:"UserName,omitempty"`
+	Password          string      `json:"Password,omitempty"`
+	EncryptedPassword string      `json:"EncryptedPassword,omitempty"`
+	ConnType          string      `json:"ConnType,omitempty"`
+	CipherVersion     int         `json:"CipherVersion,omitempty"`
+	ServiceProvider   string      `json:"ServiceProvider,omitempty"`
+	TNS               string      `json:"TNS,omitempty"`
+	SSH               *SSHTunnel  `json:"SSH,omitempty"`
+	HTTP              *HTTPTunnel `json:"HTTP,omitempty"`
+	SSL               *SSLConfig  `json:"SSL,omitempty"`
 }
 
 func sha1Sum(data []byte) []byte {
@@ -46,17 +57,17 @@ func hexDecode(s string) []byte {
 
 func decryptNavicat11(hexPassword string) (string, error) {
 	if hexPassword == "" {
-		return "无密码", nil
+		return i18n.T("无密码"), nil
 	}
 
 	encryptedData, err := hex.DecodeString(strings.ToLower(hexPassword))
 	if err != nil {
-		return "", fmt.Errorf("十六进制解码失败: %v", err)
+		return "", i18n.Errorf("十六进制解码失败: %v", err)
 	}
 
 	cipher, err := blowfish.NewCipher(blowfishKey)
 	if err != nil {
-		return "", fmt.Errorf("创建Blowfish密码器失败: %v", err)
+		return "", i18n.Errorf("创建Blowfish密码器失败: %v", err)
 	}
 
 	roundCount := len(encryptedData) / 8
@@ -93,46 +104,101 @@ func decryptNavicat11(hexPassword string) (string, error) {
 
 func decryptNavicat12(hexPassword string) (string, error) {
 	if hexPassword == "" {
-		return "无密码", nil
+		return i18n.T("无密码"), nil
 	}
 
 	encryptedData, err := hex.DecodeString(strings.ToLower(hexPassword))
 	if err != nil {
-		return "", fmt.Errorf("十六进制解码失败: %v", err)
+		return "", i18n.Errorf("十六进制解码失败: %v", err)
+	}
+
+	if len(encryptedData) == 0 || len(encryptedData)%aes.BlockSize != 0 {
+		return "", i18n.Errorf("密文长度不是%d的倍数", aes.BlockSize)
 	}
 
 	block, err := aes.NewCipher(aesKey)
 	if err != nil {
-		return "", fmt.Errorf("创建AES密码器失败: %v", err)
+		return "", i18n.Errorf("创建AES密码器失败: %v", err)
 	}
 
 	mode := cipher.NewCBCDecrypter(block, aesIV)
 	decryptedData := make([]byte, len(encryptedData))
 	mode.CryptBlocks(decryptedData, encryptedData)
 
+	if unpadded, ok := pkcs7Unpad(decryptedData); ok {
+		return string(unpadded), nil
+	}
 	return strings.TrimRight(string(decryptedData), "\x00"), nil
 }
 
-func DecryptPassword(encryptedPassword string, version int) string {
-	if encryptedPassword == "" {
-		return "无密码"
+func pkcs7Unpad(data []byte) ([]byte, bool) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return nil, false
 	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, false
+		}
+	}
+	return data[:len(data)-padding], true
+}
 
+// isPlausiblePassword 用错误的算法解密会得到乱码，以此判断加密版本
+func isPlausiblePassword(password string) bool {
+	if password == "" || !utf8.ValidString(password) {
+		return false
+	}
+	for _, r := range password {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrUnknownCipher 自动识别时AES和Blowfish都无法得到合理的结果
+var ErrUnknownCipher = status.New(status.ErrDecryptFailed, "无法识别加密版本")
+
+// DecryptWithVersion version为0时依次尝试Navicat12(AES)和Navicat11(Blowfish)，返回识别到的版本，
+// 注册表中即使是12以上的版本也仍然使用Blowfish，导出的NCX文件则使用AES
+func DecryptWithVersion(encryptedPassword string, version int) (string, int, error) {
 	var result string
 	var err error
 
-	if version == 11 {
+	switch {
+	case version == 0:
+		if result, err := decryptNavicat12(encryptedPassword); err == nil && isPlausiblePassword(result) {
+			return result, 12, nil
+		}
+		if result, err := decryptNavicat11(encryptedPassword); err == nil && isPlausiblePassword(result) {
+			return result, 11, nil
+		}
+		return "", 0, ErrUnknownCipher
+	case version == 11:
 		result, err = decryptNavicat11(encryptedPassword)
-	} else if version >= 12 {
+	case version >= 12:
 		result, err = decryptNavicat12(encryptedPassword)
-	} else {
-		return "[-] 不支持的版本"
+	default:
+		return "", version, status.Errorf(status.ErrUnsupportedVersion, "不支持的Navicat加密版本: %d", version)
 	}
 
 	if err != nil {
-		return fmt.Sprintf("[-] 解密失败: %v", err)
+		return "", version, status.Errorf(status.ErrDecryptFailed, "解密失败: %v", err)
 	}
+	return strings.TrimSpace(result), version, nil
+}
 
+// DecryptPassword 返回用于显示的密码，解密失败时返回"[-] "开头的提示
+func DecryptPassword(encryptedPassword string, version int) string {
+	if encryptedPassword == "" {
+		return i18n.T("无密码")
+	}
+
+	result, _, err := DecryptWithVersion(encryptedPassword, version)
+	if err != nil {
+		return "[-] " + err.Error()
+	}
 	return strings.TrimSpace(result)
 }
 
@@ -140,17 +206,13 @@ func ParseNCX(filePath string, version int) ([]string, error) {
 
 	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		return nil, i18n.Errorf("读取文件失败: %v", err)
 	}
 
+	// 保留所有属性，SSH、HTTP隧道、SSL等字段和注册表中的值名一致
 	type XMLConnection struct {
-		XMLName  xml.Name `xml:"Connection"`
-		ConnType string   `xml:"ConnType,attr"`
-		Host     string   `xml:"Host,attr"`
-		Port     string   `xml:"Port,attr"`
-		Database string   `xml:"Database,attr"`
-		UserName string   `xml:"UserName,attr"`
-		Password string   `xml:"Password,attr"`
+		XMLName xml.Name   `xml:"Connection"`
+		Attrs   []xml.Attr `xml:",any,attr"`
 	}
 
 	type XMLRoot struct {
@@ -159,56 +221,45 @@ func ParseNCX(filePath string, version int) ([]string, error) {
 	}
 	var root XMLRoot
 	if err := xml.Unmarshal(data, &root); err != nil {
-		return nil, fmt.Errorf("XML解析错误: %v", err)
+		return nil, i18n.Errorf("XML解析错误: %v", err)
 	}
 
 	var connections []string
-	for _, conn := range root.Connections {
-		dbType := conn.ConnType
-		if dbType == "" {
-			dbType = "未知"
-		}
-		host := conn.Host
-		if host == "" {
-			host = "未知"
-		}
-		port := conn.Port
-		if port == "" {
-			port = "未知"
+	for _, xmlConn := range root.Connections {
+		attrs := make(map[string]string)
+		for _, attr := range xmlConn.Attrs {
+			attrs[strings.ToLower(attr.Name.Local)] = attr.Value
 		}
-		database := conn.Database
-		if database == "" {
-			database = "未知"
+		get := func(name string) string {
+			return attrs[strings.ToLower(name)]
 		}
-		username := conn.UserName
-		if username == "" {
-			username = "未知"
-		}
-
-		password := DecryptPassword(conn.Password, version)
 
-		if !(host == "未知" && port == "未知" && database == "未知" && username == "未知" && (password == "无密码" || password == "")) {
-			connections = append(connections, fmt.Sprintf("[+] 数据库类型: %s, IP: %s, 端口: %s, 数据库: %s, 账号: %s, 密码: %s",
-				dbType, host, port, database, username, password))
+		conn := newConnection(ncxProductName(get("ConnType")), get("ConnectionName"), get, version)
+		if conn.Host == "" && conn.Port == "" && conn.Database == "" && conn.UserName == "" &&
+			conn.EncryptedPassword == "" && conn.SSH == nil && conn.HTTP == nil {
+			continue
 		}
+		recordCredentials(conn)
+		connections = append(connections, formatConnection(conn))
 	}
 
 	return connections, nil
 }
 
-func GetNavicatServers() ([]string, error) {
+// GetNavicatConnections 读取注册表 Software\PremiumSoft 下所有Navicat产品保存的连接
+func GetNavicatConnections() ([]Connection, error) {
 	baseKey := `Software\PremiumSoft`
-	var allConnections []string
+	var connections []Connection
 
-	key, err := registry.OpenKey(registry.CURRENT_USER, baseKey, registry.READ)
+	key, err := regsource.OpenKey(regsource.CurrentUser, baseKey)
 	if err != nil {
-		return nil, fmt.Errorf("打开注册表项失败: %v", err)
+		return nil, i18n.Errorf("打开注册表项失败: %w", err)
 	}
 	defer key.Close()
 
-	subKeys, err := key.ReadSubKeyNames(-1)
+	subKeys, err := key.SubKeyNames()
 	if err != nil {
-		return nil, fmt.Errorf("读取子键失败: %v", err)
+		return nil, i18n.Errorf("读取子键失败: %v", err)
 	}
 
 	for _, subKey := range subKeys {
@@ -216,111 +267,108 @@ func GetNavicatServers() ([]string, error) {
 			continue
 		}
 
-		serverPath := fmt.Sprintf("%s\\%s\\Servers", baseKey, subKey)
-		serverKey, err := registry.OpenKey(registry.CURRENT_USER, serverPath, registry.READ)
+		serverKey, err := key.OpenSubKey(subKey + `\Servers`)
 		if err != nil {
-
 			continue
 		}
 
-		serverNames, err := serverKey.ReadSubKeyNames(-1)
-
+		serverNames, err := serverKey.SubKeyNames()
 		if err != nil {
 			serverKey.Close()
 			continue
 		}
 
 		for _, serverName := range serverNames {
-			connection, err := getServerInfo(serverPath, serverName)
+			connection, err := getServerInfo(serverKey, productName(subKey), serverName)
 			if err == nil {
-				allConnections = append(allConnections, connection...)
+				connections = append(connections, connection)
 			}
 		}
 
 		serverKey.Close()
 	}
 
-	return allConnections, nil
+	return connections, nil
 }
 
-func getServerInfo(serverPath, serverName string) ([]string, error) {
-	fullPath := fmt.Sprintf("%s\\%s", serverPath, serverName)
-	key, err := registry.OpenKey(registry.CURRENT_USER, fullPath, registry.READ)
+func GetNavicatServers() ([]string, error) {
+	connections, err := GetNavicatConnections()
 	if err != nil {
-		return []string{}, err
+		return nil, err
 	}
-	defer key.Close()
 
-	valueNames, err := key.ReadValueNames(-1)
-	values := []string{}
+	var servers []string
+	for _, conn := range connections {
+		recordCredentials(conn)
+		servers = append(servers, formatConnection(conn))
+	}
+	return servers, nil
+}
+
+func getServerInfo(serverKey regsource.Key, product, serverName string) (Connection, error) {
+	key, err := serverKey.OpenSubKey(serverName)
 	if err != nil {
-		fmt.Printf("读取值名称失败: %v\n", err)
-	} else {
-		values = append(values, fmt.Sprintf("连接 %s 包含以下字段:\n", serverName))
-		for _, name := range valueNames {
-			value, _, err := key.GetStringValue(name)
-			if err == nil && value != "" {
-
-				if name == "Password" || name == "Pwd" {
-					decryptedValue := DecryptPassword(value, 11)
-					values = append(values, fmt.Sprintf("  %s: %s (解密后: %s)\n", name, value, decryptedValue))
-				} else {
-					values = append(values, fmt.Sprintf("  %s: %s\n", name, value))
-				}
-			} else if err == nil && value == "" {
-
-				continue
-			} else {
-
-				binValue, _, err := key.GetBinaryValue(name)
-				if err == nil && len(binValue) > 0 {
-					fmt.Printf("  %s: [二进制数据，长度: %d]\n", name, len(binValue))
-				} else {
-
-					intValue, _, err := key.GetIntegerValue(name)
-					if err == nil {
-						values = append(values, fmt.Sprintf("  %s: %d\n", name, intValue))
-					}
-				}
-			}
+		return Connection{}, err
+	}
+	defer key.Close()
+
+	get := func(name string) string {
+		if value, err := regsource.GetString(key, name); err == nil {
+			return value
+		}
+		if value, err := regsource.GetInteger(key, name); err == nil {
+			return strconv.FormatUint(value, 10)
 		}
+		return ""
 	}
 
-	return values, nil
+	return newConnection(product, serverName, get, 0), nil
 }
 
-func ScanNavicat(ncxFile string, fromReg bool, version int) (string, error) {
+// ScanNavicat 返回解析结果和连接数量，同时指定NCX文件和注册表时，
+// 注册表读取失败不影响已解析的NCX结果，两者一起返回
+func ScanNavicat(ncxFile string, fromReg bool, version int) (string, int, error) {
+	if version != 0 && version != 11 && version < 12 {
+		return "", 0, status.Errorf(status.ErrUnsupportedVersion, "不支持的Navicat加密版本: %d", version)
+	}
+
+	var resultBuilder strings.Builder
+	count := 0
 
 	if ncxFile != "" {
 		connections, err := ParseNCX(ncxFile, version)
 		if err != nil {
-			return "", fmt.Errorf("解析NCX文件失败: %v", err)
+			return "", 0, i18n.Errorf("解析NCX文件失败: %v", err)
 		}
 
 		if len(connections) > 0 {
-			resultBuilder.WriteString("[+] 成功解析指定文件，获取账密如下：\n")
-			resultBuilder.WriteString(strings.Join(connections, "\n"))
-			resultBuilder.WriteString("\n")
+			resultBuilder.WriteString(i18n.T("[+] 成功解析指定文件，获取账密如下：\n"))
+			resultBuilder.WriteString(strings.Join(connections, ""))
+			count += len(connections)
 		} else {
-			return "", fmt.Errorf("未解析到任何数据库连接信息，请检查 .ncx 文件格式！")
+			return "", 0, status.Errorf(status.ErrNotFound, "未解析到任何数据库连接信息，请检查 .ncx 文件格式！")
 		}
 	}
 
 	if fromReg {
 		connections, err := GetNavicatServers()
 		if err != nil {
-			return "", fmt.Errorf("从注册表获取Navicat连接失败: %v", err)
+			if regsource.IsMissing(err) {
+				return resultBuilder.String(), count, status.Errorf(status.ErrNotInstalled, "从注册表获取Navicat连接失败: %v", err)
+			}
+			return resultBuilder.String(), count, i18n.Errorf("从注册表获取Navicat连接失败: %v", err)
 		}
 
 		if len(connections) > 0 {
-			resultBuilder.WriteString("[+] 成功从注册表获取保存的 Navicat 连接\n")
+			resultBuilder.WriteString(i18n.T("[+] 成功从注册表获取保存的 Navicat 连接\n"))
 
-			resultBuilder.WriteString(fmt.Sprintf("[+] %s\n", strings.Join(connections, "")))
+			resultBuilder.WriteString(strings.Join(connections, ""))
+			count += len(connections)
 
 		} else {
-			return "", fmt.Errorf("未找到任何包含密码的 Navicat 连接")
+			return resultBuilder.String(), count, status.Errorf(status.ErrNotFound, "未找到任何包含密码的 Navicat 连接")
 		}
 	}
 
-	return resultBuilder.String(), nil
+	return resultBuilder.String(), count, nil
 }
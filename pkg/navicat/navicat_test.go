@@ -0,0 +1,61 @@
+package navicat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"e0e1-config/pkg/status"
+)
+
+// encryptNavicat12 按Navicat12的方式加密: AES-128-CBC + PKCS7，结果为大写十六进制
+func encryptNavicat12(password string) string {
+	padding := aes.BlockSize - len(password)%aes.BlockSize
+	data := append([]byte(password), []byte(strings.Repeat(string(rune(padding)), padding))...)
+	block, _ := aes.NewCipher(aesKey)
+	cipher.NewCBCEncrypter(block, aesIV).CryptBlocks(data, data)
+	return strings.ToUpper(hex.EncodeToString(data))
+}
+
+func TestDecryptWithVersion(t *testing.T) {
+	encrypted := encryptNavicat12("P@ssw0rd")
+
+	for _, version := range []int{0, 12, 16} {
+		password, detected, err := DecryptWithVersion(encrypted, version)
+		want := version
+		if version == 0 {
+			want = 12
+		}
+		if err != nil || password != "P@ssw0rd" || detected != want {
+			t.Errorf("DecryptWithVersion(%d) = %q, %d, %v", version, password, detected, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		encrypted string
+		version   int
+		kind      error
+	}{
+		{"unsupported version", encrypted, 10, status.ErrUnsupportedVersion},
+		{"bad hex", "zz", 12, status.ErrDecryptFailed},
+		{"bad length", "0011", 12, status.ErrDecryptFailed},
+		{"unknown cipher", "not hex", 0, ErrUnknownCipher},
+	}
+	for _, tt := range tests {
+		password, _, err := DecryptWithVersion(tt.encrypted, tt.version)
+		if !errors.Is(err, tt.kind) || password != "" {
+			t.Errorf("%s: DecryptWithVersion() = %q, %v, want %v", tt.name, password, err, tt.kind)
+		}
+	}
+
+	if got := DecryptPassword("0011", 12); !strings.HasPrefix(got, "[-] ") {
+		t.Errorf("DecryptPassword() = %q, want a \"[-] \" message", got)
+	}
+	if got := DecryptPassword(encrypted, 0); got != "P@ssw0rd" {
+		t.Errorf("DecryptPassword() = %q", got)
+	}
+}
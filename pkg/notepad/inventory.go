@@ -0,0 +1,44 @@
+package notepad
+
+import (
+	"e0e1-config/pkg/i18n"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// InventoryNotepad 只统计记事本TabState和Notepad++备份目录中的文件数量，不结束进程也不读取内容
+func InventoryNotepad() (string, error) {
+	var result strings.Builder
+
+	if tabStatePath, err := findNotepadTabStatePath(); err == nil {
+		result.WriteString(i18n.Sprintf("记事本TabState: %s\n  文件: %d 个\n", tabStatePath, countFiles(tabStatePath)))
+	}
+
+	if username, err := getUserName(); err == nil {
+		directoryPath := fmt.Sprintf("C:\\Users\\%s\\AppData\\Roaming\\Notepad++\\backup", username)
+		if count := countFiles(directoryPath); count > 0 {
+			result.WriteString(i18n.Sprintf("Notepad++备份: %s\n  文件: %d 个\n", directoryPath, count))
+		}
+	}
+
+	if result.Len() == 0 {
+		return "", i18n.Errorf("未找到记事本或Notepad++的缓存文件")
+	}
+	return result.String(), nil
+}
+
+func countFiles(dir string) int {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, file := range files {
+		if !file.IsDir() {
+			count++
+		}
+	}
+	return count
+}
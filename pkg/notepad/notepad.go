@@ -1,6 +1,8 @@
 package notepad
 
 import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,7 +11,9 @@ import (
 	"strings"
 )
 
-func GetNotepadContent() string {
+// GetNotepadContent 返回记事本和Notepad++中缓存的内容以及读取到的文件数量，
+// 两者都没有缓存目录时返回 ErrNotInstalled
+func GetNotepadContent() (string, int, error) {
 	var output strings.Builder
 	output.WriteString("\n----[  NOTEPAD  ]----\n\n")
 
@@ -19,16 +23,16 @@ func GetNotepadContent() string {
 
 	tabStatePath, err := findNotepadTabStatePath()
 	if err != nil {
-		output.WriteString(fmt.Sprintf("查找TabState路径失败: %v\n", err))
-		return output.String()
+		output.WriteString(i18n.Sprintf("查找TabState路径失败: %v\n", err))
+		return notepadPP(&output, 0, false)
 	}
 
-	output.WriteString(fmt.Sprintf("TabState路径: %s\n\n", tabStatePath))
+	output.WriteString(i18n.Sprintf("TabState路径: %s\n\n", tabStatePath))
 
 	files, err := ioutil.ReadDir(tabStatePath)
 	if err != nil {
-		output.WriteString(fmt.Sprintf("读取TabState目录失败: %v\n", err))
-		return output.String()
+		output.WriteString(i18n.Sprintf("读取TabState目录失败: %v\n", err))
+		return notepadPP(&output, 0, true)
 	}
 
 	fileCount := 0
@@ -37,19 +41,28 @@ func GetNotepadContent() string {
 			fileCount++
 			filePath := filepath.Join(tabStatePath, file.Name())
 			output.WriteString(fmt.Sprintf("--------------------------------\n"))
-			output.WriteString(fmt.Sprintf("处理文件: %s\n", file.Name()))
+			output.WriteString(i18n.Sprintf("处理文件: %s\n", file.Name()))
 
 			fileContent := dealFileType(filePath)
 			output.WriteString(fileContent)
 		}
 	}
 
-	output.WriteString(fmt.Sprintf("找到 %d 个文件\n", fileCount))
+	output.WriteString(i18n.Sprintf("找到 %d 个文件\n", fileCount))
 	output.WriteString("--------------------------------\n")
 
-	output.WriteString(GetNotepadPPContent())
+	return notepadPP(&output, fileCount, true)
+}
 
-	return output.String()
+// notepadPP 在记事本的结果后追加Notepad++的内容并合计文件数量
+func notepadPP(output *strings.Builder, count int, found bool) (string, int, error) {
+	content, ppCount, err := GetNotepadPPContent()
+	if err != nil && !found {
+		// 两者都不存在时不输出查找过程，原因由状态表给出
+		return "", 0, status.Errorf(status.ErrNotInstalled, "未找到记事本或Notepad++的缓存文件")
+	}
+	output.WriteString(content)
+	return output.String(), count + ppCount, nil
 }
 
 func checkAndKillProcess(processName string) error {
@@ -58,7 +71,7 @@ func checkAndKillProcess(processName string) error {
 	if err != nil {
 
 		if !strings.Contains(string(output), "没有运行的任务") {
-			return fmt.Errorf("终止进程失败: %v, %s", err, string(output))
+			return i18n.Errorf("终止进程失败: %v, %s", err, string(output))
 		}
 	}
 	return nil
@@ -67,13 +80,13 @@ func checkAndKillProcess(processName string) error {
 func findNotepadTabStatePath() (string, error) {
 	appDataLocalPath, err := os.UserCacheDir()
 	if err != nil {
-		return "", fmt.Errorf("获取AppData路径失败: %v", err)
+		return "", i18n.Errorf("获取AppData路径失败: %v", err)
 	}
 
 	packagesPath := filepath.Join(appDataLocalPath, "Packages")
 	entries, err := os.ReadDir(packagesPath)
 	if err != nil {
-		return "", fmt.Errorf("读取Packages目录失败: %v", err)
+		return "", i18n.Errorf("读取Packages目录失败: %v", err)
 	}
 
 	for _, entry := range entries {
@@ -85,7 +98,7 @@ func findNotepadTabStatePath() (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("未找到记事本TabState路径")
+	return "", i18n.Errorf("未找到记事本TabState路径")
 }
 
 func dealFileType(filePath string) string {
@@ -93,23 +106,23 @@ func dealFileType(filePath string) string {
 
 	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		output.WriteString(fmt.Sprintf("读取文件失败: %v\n", err))
+		output.WriteString(i18n.Sprintf("读取文件失败: %v\n", err))
 		return output.String()
 	}
 
 	if len(data) < 4 {
-		output.WriteString("文件数据不完整\n")
+		output.WriteString(i18n.T("文件数据不完整\n"))
 		return output.String()
 	}
 
 	fourthByte := data[3]
 
 	if fourthByte == 1 {
-		output.WriteString("状态: ( 已保存在本地的文件 √)\n")
+		output.WriteString(i18n.T("状态: ( 已保存在本地的文件 √)\n"))
 		savedFileContent := processSavedFile(data)
 		output.WriteString(savedFileContent)
 	} else {
-		output.WriteString("状态: ( 未保存本地的临时文件 √)\n")
+		output.WriteString(i18n.T("状态: ( 未保存本地的临时文件 √)\n"))
 		unsavedFileContent := processUnsavedFile(data)
 		output.WriteString(unsavedFileContent)
 	}
@@ -121,7 +134,7 @@ func processSavedFile(data []byte) string {
 	var output strings.Builder
 
 	if len(data) < 6 {
-		output.WriteString("文件数据不完整\n")
+		output.WriteString(i18n.T("文件数据不完整\n"))
 		return output.String()
 	}
 
@@ -132,13 +145,13 @@ func processSavedFile(data []byte) string {
 	filePathByteLength := filePathLength * 2
 
 	if len(data) < 5+filePathByteLength+2 {
-		output.WriteString("[-] 文件数据不完整，无法读取文件路径\n")
+		output.WriteString(i18n.T("[-] 文件数据不完整，无法读取文件路径\n"))
 		return output.String()
 	}
 
 	filePathBytes := data[5 : 5+filePathByteLength]
 	filePath := decodeText(filePathBytes)
-	output.WriteString(fmt.Sprintf("文件名: %s\n", filePath))
+	output.WriteString(i18n.Sprintf("文件名: %s\n", filePath))
 
 	headerCalc += filePathByteLength
 
@@ -149,7 +162,7 @@ func processSavedFile(data []byte) string {
 	var status bool
 
 	if len(data) < contentLengthPos+3 {
-		output.WriteString("文件数据不完整，无法读取内容长度\n")
+		output.WriteString(i18n.T("文件数据不完整，无法读取内容长度\n"))
 		return output.String()
 	}
 
@@ -169,7 +182,7 @@ func processSavedFile(data []byte) string {
 		headerCalc += 3
 	}
 
-	output.WriteString(fmt.Sprintf("内容长度: %d\n", contentLength))
+	output.WriteString(i18n.Sprintf("内容长度: %d\n", contentLength))
 
 	var paddingSize int
 	if status {
@@ -181,14 +194,14 @@ func processSavedFile(data []byte) string {
 	contentStartPos := headerCalc + paddingSize
 
 	if len(data) < contentStartPos+6 {
-		output.WriteString("文件数据不完整，无法读取内容\n")
+		output.WriteString(i18n.T("文件数据不完整，无法读取内容\n"))
 		return output.String()
 	}
 
 	contentEndPos := len(data) - 6
 
 	if contentStartPos >= contentEndPos {
-		output.WriteString("内容区域计算错误\n")
+		output.WriteString(i18n.T("内容区域计算错误\n"))
 		return output.String()
 	}
 
@@ -198,7 +211,7 @@ func processSavedFile(data []byte) string {
 
 	content = strings.ReplaceAll(content, "\r", "\r\n")
 
-	output.WriteString("内容:\n")
+	output.WriteString(i18n.T("内容:\n"))
 	output.WriteString(content)
 	output.WriteString("\n")
 
@@ -209,7 +222,7 @@ func processUnsavedFile(data []byte) string {
 	var output strings.Builder
 
 	if len(data) < 20 {
-		output.WriteString("[*] 文件数据不完整，长度不足\n")
+		output.WriteString(i18n.T("[*] 文件数据不完整，长度不足\n"))
 		return output.String()
 	}
 
@@ -218,14 +231,14 @@ func processUnsavedFile(data []byte) string {
 	count := len(data) - startPos - 5
 
 	if count <= 0 {
-		output.WriteString("文件数据不完整，无法读取内容\n")
+		output.WriteString(i18n.T("文件数据不完整，无法读取内容\n"))
 		return output.String()
 	}
 
 	contentBytes := data[startPos : len(data)-5]
 	content := string(contentBytes)
 
-	output.WriteString("内容:\n")
+	output.WriteString(i18n.T("内容:\n"))
 	output.WriteString(content)
 	output.WriteString("\n")
 
@@ -258,48 +271,50 @@ func containsPrintableChars(s string) bool {
 	return false
 }
 
-func GetNotepadPPContent() string {
+// GetNotepadPPContent 返回Notepad++备份目录中的内容和文件数量
+func GetNotepadPPContent() (string, int, error) {
 	var output strings.Builder
 	output.WriteString("\n----[  NOTEPAD++  ]----\n\n")
 
 	username, err := getUserName()
 	if err != nil {
-		output.WriteString(fmt.Sprintf("获取用户名失败: %v\n", err))
-		return output.String()
+		output.WriteString(i18n.Sprintf("获取用户名失败: %v\n", err))
+		return output.String(), 0, err
 	}
 
 	directoryPath := fmt.Sprintf("C:\\Users\\%s\\AppData\\Roaming\\Notepad++\\backup", username)
 
 	if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
-		output.WriteString(fmt.Sprintf("目录 %s 不存在\n", directoryPath))
-		return output.String()
+		return "", 0, status.Errorf(status.ErrNotInstalled, "目录 %s 不存在", directoryPath)
 	}
 
 	files, err := ioutil.ReadDir(directoryPath)
 	if err != nil {
-		output.WriteString(fmt.Sprintf("读取目录失败: %v\n", err))
-		return output.String()
+		output.WriteString(i18n.Sprintf("读取目录失败: %v\n", err))
+		return output.String(), 0, err
 	}
 
-	output.WriteString(fmt.Sprintf("总文件数: %d\n", len(files)))
+	output.WriteString(i18n.Sprintf("总文件数: %d\n", len(files)))
 
+	count := 0
 	for _, file := range files {
 		if !file.IsDir() {
 			filePath := filepath.Join(directoryPath, file.Name())
-			output.WriteString(fmt.Sprintf("读取文件: %s\n", filePath))
+			output.WriteString(i18n.Sprintf("读取文件: %s\n", filePath))
 
 			content, err := ioutil.ReadFile(filePath)
 			if err != nil {
-				output.WriteString(fmt.Sprintf("读取文件失败: %v\n", err))
+				output.WriteString(i18n.Sprintf("读取文件失败: %v\n", err))
 				continue
 			}
 
 			output.WriteString(string(content))
 			output.WriteString("\n--------------------------------\n")
+			count++
 		}
 	}
 
-	return output.String()
+	return output.String(), count, nil
 }
 
 func getUserName() (string, error) {
@@ -319,7 +334,7 @@ func getUserName() (string, error) {
 func extractAllContent(data []byte, output *strings.Builder) {
 
 	if len(data) < 4 {
-		output.WriteString("文件数据不完整\n")
+		output.WriteString(i18n.T("文件数据不完整\n"))
 		return
 	}
 
@@ -330,25 +345,25 @@ func extractAllContent(data []byte, output *strings.Builder) {
 	fourthByte := data[3]
 
 	if fourthByte == 1 {
-		output.WriteString("----( 已保存文件  √)----\n")
+		output.WriteString(i18n.T("----( 已保存文件  √)----\n"))
 	} else {
-		output.WriteString("----(未保存文件 ×)----\n")
+		output.WriteString(i18n.T("----(未保存文件 ×)----\n"))
 	}
 
 	if len(data) >= 5 {
 		filePathLength := int(data[4])
-		output.WriteString(fmt.Sprintf("文件路径长度: %d\n", filePathLength))
+		output.WriteString(i18n.Sprintf("文件路径长度: %d\n", filePathLength))
 
 		if len(data) >= 5+filePathLength*2 {
 			filePathBytes := data[5 : 5+filePathLength*2]
 			filePath := decodeText(filePathBytes)
-			output.WriteString(fmt.Sprintf("文件名: %s\n", filePath))
+			output.WriteString(i18n.Sprintf("文件名: %s\n", filePath))
 		}
 	}
 
 	possibleStartPositions := []int{12, 60, 100, 150}
 
-	output.WriteString("尝试提取内容:\n")
+	output.WriteString(i18n.T("尝试提取内容:\n"))
 
 	for _, startPos := range possibleStartPositions {
 		if len(data) > startPos+10 {
@@ -362,7 +377,7 @@ func extractAllContent(data []byte, output *strings.Builder) {
 			content := decodeText(contentBytes)
 
 			if len(strings.TrimSpace(content)) > 0 && containsPrintableChars(content) {
-				output.WriteString(fmt.Sprintf("--- 从位置 %d 开始的内容 ---\n", startPos))
+				output.WriteString(i18n.Sprintf("--- 从位置 %d 开始的内容 ---\n", startPos))
 				output.WriteString(content)
 				output.WriteString("\n")
 				break
@@ -373,7 +388,7 @@ func extractAllContent(data []byte, output *strings.Builder) {
 	if len(data) > 4 {
 		content := decodeText(data[4:])
 		if len(strings.TrimSpace(content)) > 0 && containsPrintableChars(content) {
-			output.WriteString("--- 整个文件内容 ---\n")
+			output.WriteString(i18n.T("--- 整个文件内容 ---\n"))
 			output.WriteString(content)
 			output.WriteString("\n")
 		}
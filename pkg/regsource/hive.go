@@ -0,0 +1,332 @@
+package regsource
+
+import (
+	"e0e1-config/pkg/i18n"
+	"encoding/binary"
+	"io/ioutil"
+	"strings"
+)
+
+// regf 格式：4096字节的基本块之后是hbin，所有cell偏移都相对于第一个hbin
+const (
+	hiveBaseBlockSize = 0x1000
+	hiveRootOffset    = 0x24
+	bigDataThreshold  = 16344
+	keyCompName       = 0x20
+	valueCompName     = 0x01
+	dataInline        = 0x80000000
+)
+
+// Hive 是从磁盘读取的 NTUSER.DAT / SYSTEM / SOFTWARE 等hive文件，未合并事务日志
+type Hive struct {
+	data []byte
+	root uint32
+}
+
+type hiveKey struct {
+	hive   *Hive
+	offset uint32
+	name   string
+}
+
+func OpenHive(path string) (*Hive, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHive(data)
+}
+
+func ParseHive(data []byte) (*Hive, error) {
+	if len(data) < hiveBaseBlockSize || string(data[:4]) != "regf" {
+		return nil, i18n.Errorf("不是有效的hive文件")
+	}
+
+	h := &Hive{data: data, root: binary.LittleEndian.Uint32(data[hiveRootOffset:])}
+	cell, err := h.cell(h.root)
+	if err != nil || len(cell) < 0x4C || string(cell[:2]) != "nk" {
+		return nil, i18n.Errorf("hive根键损坏")
+	}
+	return h, nil
+}
+
+func (h *Hive) Root() Key {
+	return &hiveKey{hive: h, offset: h.root}
+}
+
+// cell 返回cell的数据部分，cell头部是4字节的大小，已分配的cell大小为负数
+func (h *Hive) cell(offset uint32) ([]byte, error) {
+	pos := hiveBaseBlockSize + int(offset)
+	if offset == 0xFFFFFFFF || pos+4 > len(h.data) {
+		return nil, i18n.Errorf("cell偏移越界: 0x%x", offset)
+	}
+
+	size := int(int32(binary.LittleEndian.Uint32(h.data[pos:])))
+	if size < 0 {
+		size = -size
+	}
+	if size < 4 || pos+size > len(h.data) {
+		return nil, i18n.Errorf("cell大小错误: 0x%x", offset)
+	}
+	return h.data[pos+4 : pos+size], nil
+}
+
+func (k *hiveKey) nk() ([]byte, error) {
+	cell, err := k.hive.cell(k.offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(cell) < 0x4C || string(cell[:2]) != "nk" {
+		return nil, i18n.Errorf("键记录损坏: 0x%x", k.offset)
+	}
+	return cell, nil
+}
+
+func (h *Hive) keyName(nk []byte) string {
+	flags := binary.LittleEndian.Uint16(nk[0x02:])
+	length := int(binary.LittleEndian.Uint16(nk[0x48:]))
+	if 0x4C+length > len(nk) {
+		length = len(nk) - 0x4C
+	}
+	return decodeName(nk[0x4C:0x4C+length], flags&keyCompName != 0)
+}
+
+// decodeName 压缩名称是Latin-1，否则是UTF-16LE
+func decodeName(raw []byte, compressed bool) string {
+	if !compressed {
+		return decodeUTF16(raw)
+	}
+	runes := make([]rune, len(raw))
+	for i, b := range raw {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// subKeyOffsets 展开 lf/lh/li/ri 索引，返回所有子键的nk偏移
+func (h *Hive) subKeyOffsets(listOffset uint32, depth int) ([]uint32, error) {
+	if depth > 8 {
+		return nil, i18n.Errorf("子键索引嵌套过深")
+	}
+
+	list, err := h.cell(listOffset)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) < 4 {
+		return nil, i18n.Errorf("子键索引损坏")
+	}
+
+	count := int(binary.LittleEndian.Uint16(list[2:]))
+	var step int
+	switch string(list[:2]) {
+	case "lf", "lh":
+		step = 8
+	case "li", "ri":
+		step = 4
+	default:
+		return nil, i18n.Errorf("未知的子键索引类型: %q", list[:2])
+	}
+	if 4+count*step > len(list) {
+		return nil, i18n.Errorf("子键索引损坏")
+	}
+
+	var offsets []uint32
+	for i := 0; i < count; i++ {
+		offset := binary.LittleEndian.Uint32(list[4+i*step:])
+		if string(list[:2]) != "ri" {
+			offsets = append(offsets, offset)
+			continue
+		}
+		nested, err := h.subKeyOffsets(offset, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, nested...)
+	}
+	return offsets, nil
+}
+
+func (k *hiveKey) subKeys() ([]*hiveKey, error) {
+	nk, err := k.nk()
+	if err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(nk[0x14:]) == 0 {
+		return nil, nil
+	}
+
+	offsets, err := k.hive.subKeyOffsets(binary.LittleEndian.Uint32(nk[0x1C:]), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*hiveKey, 0, len(offsets))
+	for _, offset := range offsets {
+		sub := &hiveKey{hive: k.hive, offset: offset}
+		subNK, err := sub.nk()
+		if err != nil {
+			continue
+		}
+		sub.name = k.hive.keyName(subNK)
+		keys = append(keys, sub)
+	}
+	return keys, nil
+}
+
+func (k *hiveKey) SubKeyNames() ([]string, error) {
+	keys, err := k.subKeys()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.name)
+	}
+	return names, nil
+}
+
+func (k *hiveKey) values() ([][]byte, error) {
+	nk, err := k.nk()
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(binary.LittleEndian.Uint32(nk[0x24:]))
+	if count == 0 {
+		return nil, nil
+	}
+	list, err := k.hive.cell(binary.LittleEndian.Uint32(nk[0x28:]))
+	if err != nil {
+		return nil, err
+	}
+	if count*4 > len(list) {
+		return nil, i18n.Errorf("值列表损坏")
+	}
+
+	var records [][]byte
+	for i := 0; i < count; i++ {
+		vk, err := k.hive.cell(binary.LittleEndian.Uint32(list[i*4:]))
+		if err != nil || len(vk) < 0x14 || string(vk[:2]) != "vk" {
+			continue
+		}
+		records = append(records, vk)
+	}
+	return records, nil
+}
+
+func valueName(vk []byte) string {
+	length := int(binary.LittleEndian.Uint16(vk[0x02:]))
+	if 0x14+length > len(vk) {
+		length = len(vk) - 0x14
+	}
+	flags := binary.LittleEndian.Uint16(vk[0x10:])
+	return decodeName(vk[0x14:0x14+length], flags&valueCompName != 0)
+}
+
+func (k *hiveKey) ValueNames() ([]string, error) {
+	records, err := k.values()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(records))
+	for _, vk := range records {
+		names = append(names, valueName(vk))
+	}
+	return names, nil
+}
+
+func (k *hiveKey) Value(name string) (Value, error) {
+	records, err := k.values()
+	if err != nil {
+		return Value{}, err
+	}
+
+	for _, vk := range records {
+		if !strings.EqualFold(valueName(vk), name) {
+			continue
+		}
+		data, err := k.hive.valueData(vk)
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Type: binary.LittleEndian.Uint32(vk[0x0C:]), Data: data}, nil
+	}
+	return Value{}, ErrNotExist
+}
+
+// valueData 不超过4字节的数据直接存在vk记录里，超过16344字节的使用db记录分段存储
+func (h *Hive) valueData(vk []byte) ([]byte, error) {
+	size := binary.LittleEndian.Uint32(vk[0x04:])
+	if size&dataInline != 0 {
+		size &^= dataInline
+		if size > 4 {
+			size = 4
+		}
+		return vk[0x08 : 0x08+size], nil
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	cell, err := h.cell(binary.LittleEndian.Uint32(vk[0x08:]))
+	if err != nil {
+		return nil, err
+	}
+
+	if size > bigDataThreshold && len(cell) >= 8 && string(cell[:2]) == "db" {
+		count := int(binary.LittleEndian.Uint16(cell[2:]))
+		list, err := h.cell(binary.LittleEndian.Uint32(cell[4:]))
+		if err != nil || count*4 > len(list) {
+			return nil, i18n.Errorf("大数据记录损坏")
+		}
+
+		data := make([]byte, 0, size)
+		for i := 0; i < count && uint32(len(data)) < size; i++ {
+			segment, err := h.cell(binary.LittleEndian.Uint32(list[i*4:]))
+			if err != nil {
+				return nil, err
+			}
+			if len(segment) > bigDataThreshold {
+				segment = segment[:bigDataThreshold]
+			}
+			data = append(data, segment...)
+		}
+		if uint32(len(data)) > size {
+			data = data[:size]
+		}
+		return data, nil
+	}
+
+	if int(size) > len(cell) {
+		return nil, i18n.Errorf("值数据越界")
+	}
+	return cell[:size], nil
+}
+
+func (k *hiveKey) OpenSubKey(path string) (Key, error) {
+	key := k
+	for _, part := range splitPath(path) {
+		subs, err := key.subKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		var next *hiveKey
+		for _, sub := range subs {
+			if strings.EqualFold(sub.name, part) {
+				next = sub
+				break
+			}
+		}
+		if next == nil {
+			return nil, ErrNotExist
+		}
+		key = next
+	}
+	return key, nil
+}
+
+func (k *hiveKey) Close() error {
+	return nil
+}
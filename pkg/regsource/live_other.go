@@ -0,0 +1,14 @@
+//go:build !windows
+
+package regsource
+
+type liveSource struct{}
+
+// Live 在非Windows系统上没有本机注册表，所有操作都返回 ErrUnavailable
+func Live() RegistrySource {
+	return liveSource{}
+}
+
+func (liveSource) OpenKey(root Root, path string) (Key, error) {
+	return nil, ErrUnavailable
+}
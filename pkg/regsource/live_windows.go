@@ -0,0 +1,76 @@
+package regsource
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+type liveSource struct{}
+
+type liveKey struct {
+	key registry.Key
+}
+
+// Live 返回本机注册表
+func Live() RegistrySource {
+	return liveSource{}
+}
+
+func (liveSource) OpenKey(root Root, path string) (Key, error) {
+	rootKey := registry.CURRENT_USER
+	if root == LocalMachine {
+		rootKey = registry.LOCAL_MACHINE
+	}
+
+	key, err := registry.OpenKey(rootKey, path, registry.READ)
+	if err != nil {
+		return nil, liveError(err, path)
+	}
+	return liveKey{key: key}, nil
+}
+
+func (k liveKey) SubKeyNames() ([]string, error) {
+	return k.key.ReadSubKeyNames(-1)
+}
+
+func (k liveKey) ValueNames() ([]string, error) {
+	return k.key.ReadValueNames(-1)
+}
+
+func (k liveKey) Value(name string) (Value, error) {
+	n, valType, err := k.key.GetValue(name, nil)
+	if err != nil && err != registry.ErrShortBuffer {
+		return Value{}, liveError(err, name)
+	}
+
+	data := make([]byte, n)
+	if n > 0 {
+		n, valType, err = k.key.GetValue(name, data)
+		if err != nil {
+			return Value{}, err
+		}
+	}
+	return Value{Type: valType, Data: data[:n]}, nil
+}
+
+func (k liveKey) OpenSubKey(path string) (Key, error) {
+	key, err := registry.OpenKey(k.key, path, registry.READ)
+	if err != nil {
+		return nil, liveError(err, path)
+	}
+	return liveKey{key: key}, nil
+}
+
+func (k liveKey) Close() error {
+	return k.key.Close()
+}
+
+// liveError 把系统返回的“找不到”转换为 ErrNotExist，使 IsMissing 对本机注册表同样有效
+func liveError(err error, name string) error {
+	if errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotExist, name)
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package regsource
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+func TestLiveError(t *testing.T) {
+	if err := liveError(registry.ErrNotExist, `Software\Missing`); !IsMissing(err) || !errors.Is(err, ErrNotExist) {
+		t.Errorf("liveError(ErrNotExist) = %v, want ErrNotExist", err)
+	}
+	if err := liveError(registry.ErrShortBuffer, "x"); err != registry.ErrShortBuffer || IsMissing(err) {
+		t.Errorf("liveError(ErrShortBuffer) = %v, want unchanged", err)
+	}
+}
+
+func TestLiveMissingKey(t *testing.T) {
+	const missing = `Software\e0e1-config\regsource-test-missing`
+	if _, err := Live().OpenKey(CurrentUser, missing); !IsMissing(err) {
+		t.Errorf("OpenKey(%q): err = %v, want ErrNotExist", missing, err)
+	}
+
+	key, err := Live().OpenKey(CurrentUser, "Software")
+	if err != nil {
+		t.Skipf("HKCU\\Software unavailable: %v", err)
+	}
+	defer key.Close()
+	if _, err := key.OpenSubKey(`e0e1-config\regsource-test-missing`); !IsMissing(err) {
+		t.Errorf("OpenSubKey: err = %v, want ErrNotExist", err)
+	}
+	if _, err := key.Value("e0e1-config-missing-value"); !IsMissing(err) {
+		t.Errorf("Value: err = %v, want ErrNotExist", err)
+	}
+}
@@ -0,0 +1,287 @@
+package regsource
+
+import (
+	"bytes"
+	"e0e1-config/pkg/i18n"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// memKey 是 .reg 文件解析后的内存树
+type memKey struct {
+	name       string
+	subkeys    map[string]*memKey
+	keyOrder   []string
+	values     map[string]Value
+	valueNames map[string]string
+	valueOrder []string
+}
+
+func newMemKey(name string) *memKey {
+	return &memKey{
+		name:       name,
+		subkeys:    make(map[string]*memKey),
+		values:     make(map[string]Value),
+		valueNames: make(map[string]string),
+	}
+}
+
+func (k *memKey) child(name string, create bool) *memKey {
+	lower := strings.ToLower(name)
+	if sub, ok := k.subkeys[lower]; ok {
+		return sub
+	}
+	if !create {
+		return nil
+	}
+	sub := newMemKey(name)
+	k.subkeys[lower] = sub
+	k.keyOrder = append(k.keyOrder, lower)
+	return sub
+}
+
+func (k *memKey) setValue(name string, value Value) {
+	lower := strings.ToLower(name)
+	if _, ok := k.values[lower]; !ok {
+		k.valueOrder = append(k.valueOrder, lower)
+	}
+	k.values[lower] = value
+	k.valueNames[lower] = name
+}
+
+func (k *memKey) SubKeyNames() ([]string, error) {
+	names := make([]string, 0, len(k.keyOrder))
+	for _, lower := range k.keyOrder {
+		names = append(names, k.subkeys[lower].name)
+	}
+	return names, nil
+}
+
+func (k *memKey) ValueNames() ([]string, error) {
+	names := make([]string, 0, len(k.valueOrder))
+	for _, lower := range k.valueOrder {
+		names = append(names, k.valueNames[lower])
+	}
+	return names, nil
+}
+
+func (k *memKey) Value(name string) (Value, error) {
+	value, ok := k.values[strings.ToLower(name)]
+	if !ok {
+		return Value{}, ErrNotExist
+	}
+	return value, nil
+}
+
+func (k *memKey) OpenSubKey(path string) (Key, error) {
+	key := k
+	for _, part := range splitPath(path) {
+		if key = key.child(part, false); key == nil {
+			return nil, ErrNotExist
+		}
+	}
+	return key, nil
+}
+
+func (k *memKey) Close() error {
+	return nil
+}
+
+// RegFile 是导出的 .reg 文件，支持 REGEDIT4 和 Windows Registry Editor Version 5.00 两种格式
+type RegFile struct {
+	roots map[Root]*memKey
+}
+
+func (r *RegFile) OpenKey(root Root, path string) (Key, error) {
+	key, ok := r.roots[root]
+	if !ok {
+		return nil, ErrNotExist
+	}
+	return key.OpenSubKey(path)
+}
+
+func LoadRegFile(path string) (*RegFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegFile(data)
+}
+
+func ParseRegFile(data []byte) (*RegFile, error) {
+	lines := joinContinuations(strings.Split(decodeRegText(data), "\n"))
+	if len(lines) == 0 {
+		return nil, i18n.Errorf("文件为空")
+	}
+
+	header := strings.TrimSpace(lines[0])
+	if header != "REGEDIT4" && !strings.HasPrefix(header, "Windows Registry Editor") {
+		return nil, i18n.Errorf("不是有效的.reg文件")
+	}
+
+	reg := &RegFile{roots: make(map[Root]*memKey)}
+	var current *memKey
+
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = nil
+			section := line[1 : len(line)-1]
+			// [-HKEY_...] 表示删除该项，不需要处理
+			if strings.HasPrefix(section, "-") {
+				continue
+			}
+			root, rest, ok := ParseRoot(section)
+			if !ok {
+				continue
+			}
+			if reg.roots[root] == nil {
+				reg.roots[root] = newMemKey(root.String())
+			}
+			current = reg.roots[root]
+			for _, part := range splitPath(rest) {
+				current = current.child(part, true)
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		name, value, ok := parseRegValue(line)
+		if ok {
+			current.setValue(name, value)
+		}
+	}
+
+	return reg, nil
+}
+
+// decodeRegText regedit导出的文件默认是带BOM的UTF-16LE
+func decodeRegText(data []byte) string {
+	if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+		chars := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			chars = append(chars, binary.LittleEndian.Uint16(data[i:]))
+		}
+		data = []byte(string(utf16.Decode(chars)))
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	return strings.ReplaceAll(string(data), "\r", "")
+}
+
+// joinContinuations 合并以 \ 结尾的续行，长的hex值会被拆成多行，字符串值总是以引号结尾不会受影响
+func joinContinuations(lines []string) []string {
+	var result []string
+	var pending string
+	for _, line := range lines {
+		if pending != "" {
+			line = pending + strings.TrimLeft(line, " \t")
+			pending = ""
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if strings.HasSuffix(trimmed, `\`) {
+			pending = strings.TrimSuffix(trimmed, `\`)
+			continue
+		}
+		result = append(result, line)
+	}
+	if pending != "" {
+		result = append(result, pending)
+	}
+	return result
+}
+
+func parseRegValue(line string) (string, Value, bool) {
+	var name, rest string
+	if strings.HasPrefix(line, "@") {
+		rest = line[1:]
+	} else {
+		var ok bool
+		name, rest, ok = readQuoted(line)
+		if !ok {
+			return "", Value{}, false
+		}
+	}
+
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "=") {
+		return "", Value{}, false
+	}
+	rest = strings.TrimSpace(rest[1:])
+
+	switch {
+	case rest == "-":
+		return "", Value{}, false
+	case strings.HasPrefix(rest, `"`):
+		str, _, ok := readQuoted(rest)
+		if !ok {
+			return "", Value{}, false
+		}
+		return name, Value{Type: SZ, Data: encodeUTF16(str)}, true
+	case strings.HasPrefix(strings.ToLower(rest), "dword:"):
+		n, err := strconv.ParseUint(strings.TrimSpace(rest[6:]), 16, 32)
+		if err != nil {
+			return "", Value{}, false
+		}
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, uint32(n))
+		return name, Value{Type: DWORD, Data: data}, true
+	case strings.HasPrefix(strings.ToLower(rest), "hex"):
+		valType := uint32(BINARY)
+		rest = rest[3:]
+		if strings.HasPrefix(rest, "(") {
+			end := strings.Index(rest, ")")
+			if end < 0 {
+				return "", Value{}, false
+			}
+			n, err := strconv.ParseUint(rest[1:end], 16, 32)
+			if err != nil {
+				return "", Value{}, false
+			}
+			valType = uint32(n)
+			rest = rest[end+1:]
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return "", Value{}, false
+		}
+		data, err := hex.DecodeString(strings.NewReplacer(",", "", " ", "", "\t", "").Replace(rest[1:]))
+		if err != nil {
+			return "", Value{}, false
+		}
+		return name, Value{Type: valType, Data: data}, true
+	}
+
+	return "", Value{}, false
+}
+
+// readQuoted 读取带转义的双引号字符串，返回剩余部分
+func readQuoted(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", false
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
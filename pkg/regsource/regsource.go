@@ -0,0 +1,301 @@
+package regsource
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+type Root int
+
+const (
+	CurrentUser Root = iota
+	LocalMachine
+)
+
+func (r Root) String() string {
+	switch r {
+	case CurrentUser:
+		return "HKEY_CURRENT_USER"
+	case LocalMachine:
+		return "HKEY_LOCAL_MACHINE"
+	}
+	return fmt.Sprintf("Root(%d)", int(r))
+}
+
+// 与 golang.org/x/sys/windows/registry 中的值类型保持一致
+const (
+	NONE      = 0
+	SZ        = 1
+	EXPAND_SZ = 2
+	BINARY    = 3
+	DWORD     = 4
+	MULTI_SZ  = 7
+	QWORD     = 11
+)
+
+var (
+	ErrNotExist       = status.New(status.ErrNotFound, "注册表项不存在")
+	ErrUnexpectedType = i18n.New("注册表值类型不匹配")
+	ErrUnavailable    = status.New(status.ErrNotFound, "注册表仅在Windows上可用，请使用 -reg-file 或 -hive-* 指定离线数据")
+)
+
+// Value 是注册表值的原始数据，字符串类型统一按 UTF-16LE 存储，与系统注册表一致
+type Value struct {
+	Type uint32
+	Data []byte
+}
+
+type Key interface {
+	SubKeyNames() ([]string, error)
+	ValueNames() ([]string, error)
+	Value(name string) (Value, error)
+	OpenSubKey(path string) (Key, error)
+	Close() error
+}
+
+// RegistrySource 屏蔽了注册表的来源：本机注册表、导出的 .reg 文件或者拷贝出来的hive文件
+type RegistrySource interface {
+	OpenKey(root Root, path string) (Key, error)
+}
+
+var defaultSource RegistrySource = Live()
+
+func Default() RegistrySource {
+	return defaultSource
+}
+
+func SetDefault(source RegistrySource) {
+	defaultSource = source
+}
+
+func OpenKey(root Root, path string) (Key, error) {
+	return defaultSource.OpenKey(root, path)
+}
+
+// IsMissing 判断错误是否表示注册表项不存在或没有可用的注册表，调用方据此判断软件未安装
+func IsMissing(err error) bool {
+	return errors.Is(err, ErrNotExist) || errors.Is(err, ErrUnavailable)
+}
+
+func GetString(key Key, name string) (string, error) {
+	value, err := key.Value(name)
+	if err != nil {
+		return "", err
+	}
+	if value.Type != SZ && value.Type != EXPAND_SZ {
+		return "", ErrUnexpectedType
+	}
+	return decodeUTF16(value.Data), nil
+}
+
+func GetInteger(key Key, name string) (uint64, error) {
+	value, err := key.Value(name)
+	if err != nil {
+		return 0, err
+	}
+	switch value.Type {
+	case DWORD:
+		if len(value.Data) < 4 {
+			return 0, ErrUnexpectedType
+		}
+		return uint64(binary.LittleEndian.Uint32(value.Data)), nil
+	case QWORD:
+		if len(value.Data) < 8 {
+			return 0, ErrUnexpectedType
+		}
+		return binary.LittleEndian.Uint64(value.Data), nil
+	}
+	return 0, ErrUnexpectedType
+}
+
+func GetBinary(key Key, name string) ([]byte, error) {
+	value, err := key.Value(name)
+	if err != nil {
+		return nil, err
+	}
+	if value.Type != BINARY {
+		return nil, ErrUnexpectedType
+	}
+	return value.Data, nil
+}
+
+func decodeUTF16(data []byte) string {
+	chars := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		c := binary.LittleEndian.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	return string(utf16.Decode(chars))
+}
+
+func encodeUTF16(s string) []byte {
+	chars := utf16.Encode([]rune(s))
+	data := make([]byte, 0, len(chars)*2+2)
+	for _, c := range chars {
+		data = append(data, byte(c), byte(c>>8))
+	}
+	return append(data, 0, 0)
+}
+
+func splitPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, `\`) {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// ParseRoot 支持完整名称和缩写，HKEY_USERS\<SID> 按当前用户处理，返回去掉根之后的路径
+func ParseRoot(path string) (Root, string, bool) {
+	parts := splitPath(path)
+	if len(parts) == 0 {
+		return 0, "", false
+	}
+
+	rest := strings.Join(parts[1:], `\`)
+	switch strings.ToUpper(parts[0]) {
+	case "HKEY_CURRENT_USER", "HKCU":
+		return CurrentUser, rest, true
+	case "HKEY_LOCAL_MACHINE", "HKLM":
+		return LocalMachine, rest, true
+	case "HKEY_USERS", "HKU":
+		if len(parts) < 2 {
+			return 0, "", false
+		}
+		return CurrentUser, strings.Join(parts[2:], `\`), true
+	}
+	return 0, "", false
+}
+
+type mount struct {
+	root   Root
+	prefix []string
+	key    Key
+}
+
+// Mounts 把hive的根键挂载到 HKCU、HKLM\SYSTEM 这类位置上
+type Mounts struct {
+	mounts []mount
+}
+
+func (m *Mounts) Mount(root Root, prefix string, key Key) {
+	m.mounts = append(m.mounts, mount{root: root, prefix: splitPath(prefix), key: key})
+}
+
+func (m *Mounts) OpenKey(root Root, path string) (Key, error) {
+	parts := splitPath(path)
+
+	var best *mount
+	for i := range m.mounts {
+		candidate := &m.mounts[i]
+		if candidate.root != root || len(candidate.prefix) > len(parts) {
+			continue
+		}
+		matched := true
+		for j, part := range candidate.prefix {
+			if !strings.EqualFold(part, parts[j]) {
+				matched = false
+				break
+			}
+		}
+		if matched && (best == nil || len(candidate.prefix) > len(best.prefix)) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNotExist
+	}
+
+	rest := parts[len(best.prefix):]
+	if len(rest) == 0 {
+		return best.key, nil
+	}
+
+	key, err := best.key.OpenSubKey(strings.Join(rest, `\`))
+	if err != nil && strings.EqualFold(rest[0], "CurrentControlSet") {
+		return openCurrentControlSet(best.key, rest[1:])
+	}
+	return key, err
+}
+
+// openCurrentControlSet 离线的SYSTEM hive里没有CurrentControlSet这个链接，需要根据 Select\Current 找到实际的ControlSet
+func openCurrentControlSet(system Key, rest []string) (Key, error) {
+	selectKey, err := system.OpenSubKey("Select")
+	if err != nil {
+		return nil, err
+	}
+	defer selectKey.Close()
+
+	current, err := GetInteger(selectKey, "Current")
+	if err != nil {
+		return nil, err
+	}
+
+	path := append([]string{fmt.Sprintf("ControlSet%03d", current)}, rest...)
+	return system.OpenSubKey(strings.Join(path, `\`))
+}
+
+// Chain 依次尝试多个来源，返回第一个能打开的
+type Chain []RegistrySource
+
+func (c Chain) OpenKey(root Root, path string) (Key, error) {
+	err := ErrNotExist
+	for _, source := range c {
+		key, openErr := source.OpenKey(root, path)
+		if openErr == nil {
+			return key, nil
+		}
+		err = openErr
+	}
+	return nil, err
+}
+
+// Offline 根据命令行指定的 .reg 文件和hive文件构建离线注册表来源
+func Offline(regFiles []string, ntuser, system, software string) (RegistrySource, error) {
+	var chain Chain
+
+	for _, regFile := range regFiles {
+		source, err := LoadRegFile(regFile)
+		if err != nil {
+			return nil, i18n.Errorf("解析 %s 失败: %v", regFile, err)
+		}
+		chain = append(chain, source)
+	}
+
+	mounts := &Mounts{}
+	hives := []struct {
+		path   string
+		root   Root
+		prefix string
+	}{
+		{ntuser, CurrentUser, ""},
+		{system, LocalMachine, "SYSTEM"},
+		{software, LocalMachine, "SOFTWARE"},
+	}
+	for _, h := range hives {
+		if h.path == "" {
+			continue
+		}
+		hive, err := OpenHive(h.path)
+		if err != nil {
+			return nil, i18n.Errorf("解析 %s 失败: %v", h.path, err)
+		}
+		mounts.Mount(h.root, h.prefix, hive.Root())
+	}
+	if len(mounts.mounts) > 0 {
+		chain = append(chain, mounts)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,287 @@
+package regsource
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+const testRegFile = `Windows Registry Editor Version 5.00
+
+; comment
+[HKEY_CURRENT_USER\Software\Martin Prikryl\WinSCP 2\Sessions\My%20Server]
+"HostName"="10.0.0.1"
+"PublicKeyFile"="C:\\keys\\id \"prod\".ppk"
+"PortNumber"=dword:00000016
+@="default value"
+"Binary"=hex:01,02,0a,\
+  ff,10
+"Expand"=hex(2):25,00,41,00,25,00,00,00
+"Big"=hex(b):01,00,00,00,00,00,00,00
+"Removed"=-
+
+[-HKEY_CURRENT_USER\Software\Deleted]
+
+[HKEY_USERS\S-1-5-21-1-2-3-1001\Software\PremiumSoft\Navicat\Servers\db]
+"Host"="db.local"
+
+[HKEY_LOCAL_MACHINE\SYSTEM\Select]
+"Current"=dword:00000002
+
+[HKEY_LOCAL_MACHINE\SYSTEM\ControlSet002\Services\Test]
+"ImagePath"="test.exe"
+`
+
+func utf16File(s string) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, c := range utf16.Encode([]rune(strings.ReplaceAll(s, "\n", "\r\n"))) {
+		data = append(data, byte(c), byte(c>>8))
+	}
+	return data
+}
+
+func TestParseRegFile(t *testing.T) {
+	for name, data := range map[string][]byte{"utf8": []byte(testRegFile), "utf16": utf16File(testRegFile)} {
+		t.Run(name, func(t *testing.T) {
+			reg, err := ParseRegFile(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err := reg.OpenKey(CurrentUser, `software\martin prikryl\WinSCP 2\Sessions\My%20Server`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantStrings := map[string]string{
+				"HostName":      "10.0.0.1",
+				"PublicKeyFile": `C:\keys\id "prod".ppk`,
+				"":              "default value",
+				"Expand":        "%A%",
+			}
+			for name, want := range wantStrings {
+				if got, err := GetString(key, name); err != nil || got != want {
+					t.Errorf("GetString(%q) = %q, %v, want %q", name, got, err, want)
+				}
+			}
+			if got, err := GetInteger(key, "portnumber"); err != nil || got != 22 {
+				t.Errorf("GetInteger(PortNumber) = %d, %v", got, err)
+			}
+			if got, err := GetInteger(key, "Big"); err != nil || got != 1 {
+				t.Errorf("GetInteger(Big) = %d, %v", got, err)
+			}
+			if got, err := GetBinary(key, "Binary"); err != nil || !bytes.Equal(got, []byte{1, 2, 0x0a, 0xff, 0x10}) {
+				t.Errorf("GetBinary(Binary) = %x, %v", got, err)
+			}
+			if _, err := key.Value("Removed"); err != ErrNotExist {
+				t.Errorf("Removed value: err = %v, want %v", err, ErrNotExist)
+			}
+			if _, err := GetInteger(key, "HostName"); err != ErrUnexpectedType {
+				t.Errorf("GetInteger on string: err = %v, want %v", err, ErrUnexpectedType)
+			}
+
+			names, _ := key.ValueNames()
+			if want := []string{"HostName", "PublicKeyFile", "PortNumber", "", "Binary", "Expand", "Big"}; strings.Join(names, ",") != strings.Join(want, ",") {
+				t.Errorf("ValueNames() = %q, want %q", names, want)
+			}
+
+			if _, err := reg.OpenKey(CurrentUser, `Software\Deleted`); !IsMissing(err) {
+				t.Errorf("deleted key: err = %v", err)
+			}
+			navicat, err := reg.OpenKey(CurrentUser, `Software\PremiumSoft\Navicat\Servers\db`)
+			if err != nil {
+				t.Fatalf("HKEY_USERS\\<SID> should map to the current user: %v", err)
+			}
+			if host, _ := GetString(navicat, "Host"); host != "db.local" {
+				t.Errorf("Host = %q", host)
+			}
+		})
+	}
+}
+
+func TestParseRegFileInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":      "",
+		"not a .reg": "[HKEY_CURRENT_USER\\Software]\n",
+	} {
+		if _, err := ParseRegFile([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// 格式错误的值被忽略，不影响其它值
+	reg, err := ParseRegFile([]byte("REGEDIT4\n[HKEY_CURRENT_USER\\A]\n\"Bad\"=hex:zz\n\"Dword\"=dword:xyz\n\"Open=\"x\"\n\"Ok\"=\"1\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := reg.OpenKey(CurrentUser, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := key.ValueNames(); len(names) != 1 || names[0] != "Ok" {
+		t.Errorf("ValueNames() = %q, want [Ok]", names)
+	}
+}
+
+func TestParseRoot(t *testing.T) {
+	tests := []struct {
+		path string
+		root Root
+		rest string
+		ok   bool
+	}{
+		{`HKEY_CURRENT_USER\Software\A`, CurrentUser, `Software\A`, true},
+		{`HKCU\Software`, CurrentUser, "Software", true},
+		{`HKLM\SYSTEM\Select`, LocalMachine, `SYSTEM\Select`, true},
+		{`HKEY_USERS\S-1-5-21-1\Software`, CurrentUser, "Software", true},
+		{`HKEY_USERS`, 0, "", false},
+		{`HKEY_CLASSES_ROOT\x`, 0, "", false},
+		{``, 0, "", false},
+	}
+	for _, tt := range tests {
+		root, rest, ok := ParseRoot(tt.path)
+		if root != tt.root || rest != tt.rest || ok != tt.ok {
+			t.Errorf("ParseRoot(%q) = %v, %q, %v", tt.path, root, rest, ok)
+		}
+	}
+}
+
+// 离线SYSTEM hive中没有CurrentControlSet，需要通过 Select\Current 找到实际的ControlSet
+func TestMountsCurrentControlSet(t *testing.T) {
+	reg, err := ParseRegFile([]byte(testRegFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	system, err := reg.OpenKey(LocalMachine, "SYSTEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := &Mounts{}
+	mounts.Mount(LocalMachine, "SYSTEM", system)
+
+	key, err := mounts.OpenKey(LocalMachine, `SYSTEM\CurrentControlSet\Services\Test`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, _ := GetString(key, "ImagePath"); path != "test.exe" {
+		t.Errorf("ImagePath = %q", path)
+	}
+	if _, err := mounts.OpenKey(CurrentUser, "Software"); err != ErrNotExist {
+		t.Errorf("unmounted root: err = %v", err)
+	}
+
+	chain := Chain{&RegFile{roots: map[Root]*memKey{}}, mounts}
+	if _, err := chain.OpenKey(LocalMachine, `SYSTEM\Select`); err != nil {
+		t.Errorf("Chain should fall through to the second source: %v", err)
+	}
+}
+
+// hiveBuilder 按regf格式构造最小的hive文件
+type hiveBuilder struct {
+	bins []byte
+}
+
+func (b *hiveBuilder) cell(payload []byte) uint32 {
+	offset := uint32(len(b.bins))
+	size := (4 + len(payload) + 7) &^ 7
+	cell := make([]byte, size)
+	binary.LittleEndian.PutUint32(cell, uint32(-int32(size)))
+	copy(cell[4:], payload)
+	b.bins = append(b.bins, cell...)
+	return offset
+}
+
+func (b *hiveBuilder) value(name string, typ uint32, data []byte) uint32 {
+	vk := make([]byte, 0x14+len(name))
+	copy(vk, "vk")
+	binary.LittleEndian.PutUint16(vk[0x02:], uint16(len(name)))
+	binary.LittleEndian.PutUint32(vk[0x0C:], typ)
+	binary.LittleEndian.PutUint16(vk[0x10:], valueCompName)
+	copy(vk[0x14:], name)
+	if len(data) <= 4 {
+		binary.LittleEndian.PutUint32(vk[0x04:], uint32(len(data))|dataInline)
+		copy(vk[0x08:0x0C], data)
+	} else {
+		binary.LittleEndian.PutUint32(vk[0x04:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(vk[0x08:], b.cell(data))
+	}
+	return b.cell(vk)
+}
+
+func (b *hiveBuilder) key(name string, subkeys, values []uint32) uint32 {
+	nk := make([]byte, 0x4C+len(name))
+	copy(nk, "nk")
+	binary.LittleEndian.PutUint16(nk[0x02:], keyCompName)
+	if len(subkeys) > 0 {
+		lf := make([]byte, 4+8*len(subkeys))
+		copy(lf, "lf")
+		binary.LittleEndian.PutUint16(lf[2:], uint16(len(subkeys)))
+		for i, offset := range subkeys {
+			binary.LittleEndian.PutUint32(lf[4+8*i:], offset)
+		}
+		binary.LittleEndian.PutUint32(nk[0x14:], uint32(len(subkeys)))
+		binary.LittleEndian.PutUint32(nk[0x1C:], b.cell(lf))
+	}
+	if len(values) > 0 {
+		list := make([]byte, 4*len(values))
+		for i, offset := range values {
+			binary.LittleEndian.PutUint32(list[4*i:], offset)
+		}
+		binary.LittleEndian.PutUint32(nk[0x24:], uint32(len(values)))
+		binary.LittleEndian.PutUint32(nk[0x28:], b.cell(list))
+	}
+	binary.LittleEndian.PutUint16(nk[0x48:], uint16(len(name)))
+	copy(nk[0x4C:], name)
+	return b.cell(nk)
+}
+
+func (b *hiveBuilder) build(root uint32) []byte {
+	base := make([]byte, hiveBaseBlockSize)
+	copy(base, "regf")
+	binary.LittleEndian.PutUint32(base[hiveRootOffset:], root)
+	return append(base, b.bins...)
+}
+
+func TestParseHive(t *testing.T) {
+	var b hiveBuilder
+	port := make([]byte, 4)
+	binary.LittleEndian.PutUint32(port, 3389)
+	session := b.key("Session1", nil, []uint32{
+		b.value("HostName", SZ, encodeUTF16("10.0.0.1")),
+		b.value("PortNumber", DWORD, port),
+	})
+	software := b.key("Software", []uint32{session}, nil)
+	data := b.build(b.key("ROOT", []uint32{software}, nil))
+
+	hive, err := ParseHive(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := hive.Root().OpenSubKey(`software\SESSION1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host, err := GetString(key, "hostname"); err != nil || host != "10.0.0.1" {
+		t.Errorf("HostName = %q, %v", host, err)
+	}
+	if got, err := GetInteger(key, "PortNumber"); err != nil || got != 3389 {
+		t.Errorf("PortNumber = %d, %v", got, err)
+	}
+	if names, _ := key.ValueNames(); strings.Join(names, ",") != "HostName,PortNumber" {
+		t.Errorf("ValueNames() = %q", names)
+	}
+	if _, err := hive.Root().OpenSubKey(`Software\Missing`); err != ErrNotExist {
+		t.Errorf("missing key: err = %v", err)
+	}
+
+	if _, err := ParseHive([]byte("regf")); err == nil {
+		t.Error("expected error for truncated hive")
+	}
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[hiveRootOffset:], 0x7FFFFFF0)
+	if _, err := ParseHive(corrupt); err == nil {
+		t.Error("expected error for root offset out of range")
+	}
+}
@@ -3,9 +3,9 @@ package remotecontrol
 import (
 	"bufio"
 	"bytes"
-	"fmt"
-	"golang.org/x/sys/windows"
-	"golang.org/x/sys/windows/registry"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/regsource"
 	"io"
 	"os"
 	"os/exec"
@@ -14,23 +14,22 @@ import (
 	"strconv"
 	"strings"
 	"time"
-	"unsafe"
 )
 
 func ReadRegistryInfo(path, keyword string) map[string]string {
-	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.READ)
+	key, err := regsource.OpenKey(regsource.LocalMachine, path)
 	if err != nil {
 		return nil
 	}
-	defer func(key registry.Key) {
+	defer func(key regsource.Key) {
 		err := key.Close()
 		if err != nil {
-			fmt.Println(err)
+			logger.Debug(err.Error())
 		}
 	}(key)
 
 	var names []string
-	names, err = key.ReadValueNames(-1)
+	names, err = key.ValueNames()
 	if err != nil {
 		return nil
 	}
@@ -43,10 +42,10 @@ func ReadRegistryInfo(path, keyword string) map[string]string {
 	return nil
 }
 
-func getToDeskRegistryInfo(names []string, key registry.Key) map[string]string {
+func getToDeskRegistryInfo(names []string, key regsource.Key) map[string]string {
 	registryInfoMap := make(map[string]string)
 	for _, name := range names {
-		value, _, _ := key.GetStringValue(name)
+		value, _ := regsource.GetString(key, name)
 		if name == "ImagePath" {
 			re := regexp.MustCompile(`"([^"]*)"`)
 			matches := re.FindStringSubmatch(value)
@@ -60,10 +59,10 @@ func getToDeskRegistryInfo(names []string, key registry.Key) map[string]string {
 	return registryInfoMap
 }
 
-func getSunRegistryInfo(names []string, key registry.Key) map[string]string {
+func getSunRegistryInfo(names []string, key regsource.Key) map[string]string {
 	registryInfoMap := make(map[string]string)
 	for _, name := range names {
-		value, _, _ := key.GetStringValue(name)
+		value, _ := regsource.GetString(key, name)
 		if name == "ImagePath" {
 			re := regexp.MustCompile(`"(.*)"`)
 			matches := re.FindStringSubmatch(value)
@@ -78,20 +77,20 @@ func getSunRegistryInfo(names []string, key registry.Key) map[string]string {
 func ReadConfigFile(path, keyword string) map[string]string {
 	file, err := os.Open(path)
 	if err != nil {
-		fmt.Printf("打开配置文件错误: %v\n", err)
+		logger.Warn(i18n.Sprintf("打开配置文件错误: %v", err), "path", path)
 		return nil
 	}
 	defer func(file *os.File) {
 		err := file.Close()
 		if err != nil {
-			fmt.Println(err)
+			logger.Debug(err.Error())
 		}
 	}(file)
 
 	var data []byte
 	data, err = io.ReadAll(file)
 	if err != nil {
-		fmt.Printf("读取配置文件错误: %v\n", err)
+		logger.Warn(i18n.Sprintf("读取配置文件错误: %v", err), "path", path)
 	}
 
 	if keyword == KeywordsToDesk {
@@ -123,11 +122,11 @@ func getToDeskConfigInfo(data string) map[string]string {
 			if len(autoModeParts) > 1 {
 				autoMode := strings.TrimSpace(autoModeParts[1])
 				if autoMode == "0" {
-					configInfoMap["登录规则"] = "仅使用临时密码登录"
+					configInfoMap["登录规则"] = i18n.T("仅使用临时密码登录")
 				} else if autoMode == "1" {
-					configInfoMap["登录规则"] = "仅使用安全密码登录"
+					configInfoMap["登录规则"] = i18n.T("仅使用安全密码登录")
 				} else if autoMode == "2" {
-					configInfoMap["登录规则"] = "临时密码和安全密码均可登录"
+					configInfoMap["登录规则"] = i18n.T("临时密码和安全密码均可登录")
 				}
 			}
 		}
@@ -149,79 +148,6 @@ func getSunConfigInfo(data string) map[string]string {
 	return configInfoMap
 }
 
-func ReadMemoryInfo(keyword, processName string) map[string]string {
-	memoryInfoMap := make(map[string]string)
-	var passList []string
-	pid := uint32(getProcessPID(processName))
-	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION|windows.PROCESS_VM_READ, false, pid)
-	if err != nil {
-		fmt.Println("无法打开进程:", err)
-		return nil
-	}
-
-	defer func(handle windows.Handle) {
-		err := windows.CloseHandle(handle)
-		if err != nil {
-			fmt.Println(err)
-		}
-	}(hProcess)
-
-	memoryInfo := windows.MemoryBasicInformation{}
-	var address uintptr = 0
-	var regionSize uintptr
-	for {
-		err = windows.VirtualQueryEx(hProcess, address, &memoryInfo, unsafe.Sizeof(memoryInfo))
-		if err != nil {
-			if keyword == KeywordsSun {
-				return memoryInfoMap
-			}
-			fmt.Printf("无法查找内存: %v\n", err)
-			return nil
-		}
-		if memoryInfo.State == windows.MEM_COMMIT {
-			protect := memoryInfo.Protect
-			switch protect {
-			case windows.PAGE_READWRITE, 0x20000:
-				buffer := make([]byte, memoryInfo.RegionSize)
-				bytesRead := uintptr(0)
-				err = windows.ReadProcessMemory(hProcess, memoryInfo.BaseAddress, &buffer[0], memoryInfo.RegionSize, &bytesRead)
-				if err != nil {
-					fmt.Println("无法读取内存:", err)
-					return nil
-				}
-				if keyword == KeywordsToDesk {
-					passMap := getToDeskMemoryInfo(buffer)
-					if passMap != nil && len(passMap) > 0 {
-
-						for k, v := range passMap {
-							memoryInfoMap[k] = v
-						}
-
-						if len(passMap) == 2 || len(memoryInfoMap) > 0 {
-							return memoryInfoMap
-						}
-					}
-				} else if keyword == KeywordsSun {
-					passList, memoryInfoMap = getSunMemoryInfo(passList, memoryInfoMap, buffer)
-					passList = removeDuplicates(passList)
-					memoryInfoMap["验证码"] = strings.Join(passList, "\n")
-				}
-			}
-		}
-		regionSize = memoryInfo.RegionSize
-		if regionSize == 0 {
-			break
-		}
-		address = memoryInfo.BaseAddress + regionSize
-
-		if memoryInfo.RegionSize == 0 {
-			break
-		}
-	}
-
-	return memoryInfoMap
-}
-
 func getToDeskMemoryInfo(buffer []byte) map[string]string {
 	result := make(map[string]string)
 	nowTime := []byte(getNowTime())
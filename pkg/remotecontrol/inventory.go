@@ -0,0 +1,40 @@
+package remotecontrol
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"os"
+	"strings"
+)
+
+// InventoryRemoteControl 只检查安装、配置文件和运行状态，不读取配置内容和进程内存
+func InventoryRemoteControl(softwareType string) (string, error) {
+	sw, err := lookupSoftware(softwareType)
+	if err != nil {
+		return "", err
+	}
+
+	if !IsInstalled(sw.appKeyword) {
+		return "", status.Errorf(status.ErrNotInstalled, "%s 未安装", sw.name)
+	}
+
+	var result strings.Builder
+	result.WriteString(i18n.Sprintf("%s: 已安装\n", sw.name))
+
+	registryInfo := ReadRegistryInfo(sw.appKeyword, sw.keyword)
+	if configPath, ok := registryInfo["配置文件路径"]; ok {
+		if _, err := os.Stat(configPath); err == nil {
+			result.WriteString(i18n.Sprintf("  配置文件: %s\n", configPath))
+		} else {
+			result.WriteString(i18n.Sprintf("  配置文件: %s (不存在)\n", configPath))
+		}
+	}
+
+	if IsRunning(sw.processKeyword) {
+		result.WriteString(i18n.T("  状态: 正在运行，可读取进程内存\n"))
+	} else {
+		result.WriteString(i18n.T("  状态: 未运行\n"))
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package remotecontrol
+
+// ReadMemoryInfo 读取进程内存依赖Windows API，其他平台只能离线分析注册表和配置文件
+func ReadMemoryInfo(keyword, processName string) map[string]string {
+	return nil
+}
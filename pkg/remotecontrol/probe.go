@@ -2,7 +2,7 @@ package remotecontrol
 
 import (
 	"bytes"
-	"golang.org/x/sys/windows/registry"
+	"e0e1-config/pkg/regsource"
 	"os/exec"
 	"strings"
 )
@@ -19,9 +19,10 @@ func IsRunning(processKeywords string) bool {
 }
 
 func IsInstalled(appKeywords string) bool {
-	_, err := registry.OpenKey(registry.LOCAL_MACHINE, appKeywords, registry.READ)
+	key, err := regsource.OpenKey(regsource.LocalMachine, appKeywords)
 	if err != nil {
 		return false
 	}
+	key.Close()
 	return true
 }
@@ -1,77 +1,85 @@
 package remotecontrol
 
 import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
 	"fmt"
 	"strings"
 )
 
-func ScanRemoteControl(softwareType string) (string, error) {
-	var (
-		name           string
-		keyword        string
-		processKeyword string
-		appKeyword     string
-		result         strings.Builder
-	)
+type software struct {
+	name           string
+	keyword        string
+	processKeyword string
+	appKeyword     string
+}
 
+func lookupSoftware(softwareType string) (software, error) {
 	switch softwareType {
 	case "todesk":
-		name = ToDeskName
-		keyword = KeywordsToDesk
-		processKeyword = ProcessKeywordsToDesk
-		appKeyword = AppKeywordsToDesk
+		return software{i18n.T(ToDeskName), KeywordsToDesk, ProcessKeywordsToDesk, AppKeywordsToDesk}, nil
 	case "sunlogin":
-		name = SunName
-		keyword = KeywordsSun
-		processKeyword = ProcessKeywordsSun
-		appKeyword = AppKeywordsSun
-	default:
-		return "", fmt.Errorf("不支持的远程控制软件类型: %s", softwareType)
+		return software{i18n.T(SunName), KeywordsSun, ProcessKeywordsSun, AppKeywordsSun}, nil
 	}
+	return software{}, i18n.Errorf("不支持的远程控制软件类型: %s", softwareType)
+}
 
-	if !IsInstalled(appKeyword) {
-		return "", fmt.Errorf("%s 未安装", name)
+// ScanRemoteControl 返回读取到的信息和信息条目数量
+func ScanRemoteControl(softwareType string) (string, int, error) {
+	var result strings.Builder
+	count := 0
+
+	sw, err := lookupSoftware(softwareType)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if !IsInstalled(sw.appKeyword) {
+		return "", 0, status.Errorf(status.ErrNotInstalled, "%s 未安装", sw.name)
 	}
 
-	result.WriteString(fmt.Sprintf("===== %s 信息 =====\n", name))
+	result.WriteString(i18n.Sprintf("===== %s 信息 =====\n", sw.name))
 
-	registryInfo := ReadRegistryInfo(appKeyword, keyword)
+	registryInfo := ReadRegistryInfo(sw.appKeyword, sw.keyword)
 	if registryInfo != nil && len(registryInfo) > 0 {
-		result.WriteString("--- 注册表信息 ---\n")
+		result.WriteString(i18n.T("--- 注册表信息 ---\n"))
+		count += len(registryInfo)
 		for k, v := range registryInfo {
-			result.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+			result.WriteString(fmt.Sprintf("%s: %s\n", i18n.T(k), v))
 		}
 		result.WriteString("\n")
 	}
 
 	if registryInfo != nil {
 		if configPath, ok := registryInfo["配置文件路径"]; ok {
-			configInfo := ReadConfigFile(configPath, keyword)
+			configInfo := ReadConfigFile(configPath, sw.keyword)
 			if configInfo != nil && len(configInfo) > 0 {
-				result.WriteString("--- 配置文件信息 ---\n")
+				result.WriteString(i18n.T("--- 配置文件信息 ---\n"))
+				count += len(configInfo)
 				for k, v := range configInfo {
-					result.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+					result.WriteString(fmt.Sprintf("%s: %s\n", i18n.T(k), v))
 				}
 				result.WriteString("\n")
 			}
 		}
 	}
 
-	if IsRunning(processKeyword) {
-		result.WriteString("--- 运行状态 ---\n")
-		result.WriteString("状态: 正在运行\n\n")
+	if IsRunning(sw.processKeyword) {
+		result.WriteString(i18n.T("--- 运行状态 ---\n"))
+		result.WriteString(i18n.T("状态: 正在运行\n\n"))
 
-		memoryInfo := ReadMemoryInfo(keyword, processKeyword)
+		memoryInfo := ReadMemoryInfo(sw.keyword, sw.processKeyword)
 		if memoryInfo != nil && len(memoryInfo) > 0 {
-			result.WriteString("--- 内存信息 ---\n")
+			result.WriteString(i18n.T("--- 内存信息 ---\n"))
+			count += len(memoryInfo)
 			for k, v := range memoryInfo {
-				result.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+				result.WriteString(fmt.Sprintf("%s: %s\n", i18n.T(k), v))
 			}
 		}
 	} else {
-		result.WriteString("--- 运行状态 ---\n")
-		result.WriteString("状态: 未运行\n")
+		result.WriteString(i18n.T("--- 运行状态 ---\n"))
+		result.WriteString(i18n.T("状态: 未运行\n"))
 	}
 
-	return result.String(), nil
+	return result.String(), count, nil
 }
\ No newline at end of file
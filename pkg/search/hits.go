@@ -0,0 +1,71 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// contextLines 每条匹配前后保留的上下文行数
+const contextLines = 2
+
+// Hit 一条匹配到敏感信息的行，Matches 为正则捕获到的值，用于报告中的脱敏
+type Hit struct {
+	File    string   `json:"file"`
+	Line    int      `json:"line"`
+	Text    string   `json:"text"`
+	Matches []string `json:"matches"`
+	Before  []string `json:"before,omitempty"`
+	After   []string `json:"after,omitempty"`
+}
+
+var (
+	hitsMu sync.Mutex
+	hits   []Hit
+)
+
+func addHit(hit Hit) {
+	hitsMu.Lock()
+	defer hitsMu.Unlock()
+	hits = append(hits, hit)
+}
+
+// Hits 返回本次运行的全部匹配，按文件和行号排序
+func Hits() []Hit {
+	hitsMu.Lock()
+	result := append([]Hit(nil), hits...)
+	hitsMu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+// contextOf 返回第 i 行前后的非空行
+func contextOf(lines [][]byte, i int) (before, after []string) {
+	for j := i - 1; j >= 0 && len(before) < contextLines; j-- {
+		if text := trimLine(lines[j]); text != "" {
+			before = append([]string{text}, before...)
+		}
+	}
+	for j := i + 1; j < len(lines) && len(after) < contextLines; j++ {
+		if text := trimLine(lines[j]); text != "" {
+			after = append(after, text)
+		}
+	}
+	return before, after
+}
+
+// trimLine 上下文只用于帮助判断，过长的行截断显示
+func trimLine(line []byte) string {
+	text := strings.TrimSpace(string(line))
+	if utf8.RuneCountInString(text) > 200 {
+		text = string([]rune(text)[:200]) + "..."
+	}
+	return text
+}
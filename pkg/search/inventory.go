@@ -0,0 +1,74 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/search/guize"
+	"e0e1-config/pkg/status"
+)
+
+// InventorySearch 列出将要搜索的路径、文件类型和正则表达式，不读取任何文件
+func InventorySearch(options SearchOptions) (string, error) {
+	var result strings.Builder
+
+	root, err := filepath.Abs(options.Path)
+	if err != nil {
+		root = options.Path
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return "", status.Errorf(status.ErrNotFound, "路径 %s 不存在，请输入正确路径", options.Path)
+	}
+
+	patterns := options.UserRegexList
+	if !options.UserOnlyFlag {
+		patterns = append(append([]string(nil), guize.RegexList...), options.UserRegexList...)
+	}
+	if _, err := CompileRegexes(patterns); err != nil {
+		return "", i18n.Errorf("编译正则表达式失败: %v", err)
+	}
+
+	result.WriteString(i18n.Sprintf("搜索路径: %s\n", root))
+	result.WriteString(i18n.Sprintf("  文件类型: %s\n", strings.Join(searchExtensions(options), " ")))
+	result.WriteString(i18n.Sprintf("  文件大小限制: %d 字节，跳过目录: %s\n", options.SizeLimit, strings.Join(guize.DirNamesToSkip, ", ")))
+	result.WriteString(i18n.Sprintf("  正则表达式 %d 个:\n", len(patterns)))
+	for _, pattern := range patterns {
+		result.WriteString("    " + pattern + "\n")
+	}
+
+	return result.String(), nil
+}
+
+// searchExtensions 与 SearchConfigFiles 的判断一致，-exten-only 时只搜索自定义的扩展名
+func searchExtensions(options SearchOptions) []string {
+	types := make(map[string]string)
+	if !options.ExtenOnlyFlag {
+		for k, v := range guize.FileTypes {
+			types[k] = v
+		}
+	} else {
+		for k, v := range guize.CusFileTypes {
+			types[k] = v
+		}
+	}
+	UpdateFileTypes(types, "custom", options.CustomFileTypeList)
+
+	set := make(map[string]bool)
+	for _, list := range types {
+		for _, ext := range strings.Split(list, ",") {
+			if ext = strings.TrimSpace(ext); ext != "" {
+				set[ext] = true
+			}
+		}
+	}
+
+	extensions := make([]string, 0, len(set))
+	for ext := range set {
+		extensions = append(extensions, ext)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
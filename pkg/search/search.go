@@ -13,9 +13,12 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
 	"e0e1-config/pkg/search/guize"
 	"e0e1-config/pkg/search/guolv"
 	"e0e1-config/pkg/search/jiexi"
+	"e0e1-config/pkg/status"
 )
 
 func UpdateFileTypes(fileTypes map[string]string, key, value string) {
@@ -93,8 +96,10 @@ func SearchConfigFiles(path string, info os.FileInfo, allRegexes []*regexp.Regex
 	}
 
 	var matchedContents []string
+	var fileHits []Hit
 
-	for _, line := range bytes.Split(lines, []byte{'\n'}) {
+	allLines := bytes.Split(lines, []byte{'\n'})
+	for i, line := range allLines {
 
 		var blacklistBytes [][]byte
 		for _, item := range guize.Blacklist {
@@ -125,6 +130,8 @@ func SearchConfigFiles(path string, info os.FileInfo, allRegexes []*regexp.Regex
 
 		if utf8.RuneCountInString(matchedContent) <= charLimit {
 			matchedContents = append(matchedContents, matchedContent)
+			before, after := contextOf(allLines, i)
+			fileHits = append(fileHits, Hit{Line: i + 1, Text: lineStr, Matches: matches, Before: before, After: after})
 		}
 	}
 
@@ -145,13 +152,17 @@ func SearchConfigFiles(path string, info os.FileInfo, allRegexes []*regexp.Regex
 			return results, err
 		}
 		buffer.WriteString(fmt.Sprintf("File: %s\n", absPath))
+		for _, hit := range fileHits {
+			hit.File = absPath
+			addHit(hit)
+		}
 
 		for _, line := range lines {
 			for _, line := range strings.Split(line, "\n") {
 				for _, regex := range guize.TeZhengList {
 					re := regexp.MustCompile(regex)
 					if re.MatchString(line) {
-						fmt.Printf("\n%s\n", line)
+						logger.Info(line, "file", absPath)
 					}
 				}
 			}
@@ -179,7 +190,8 @@ type SearchOptions struct {
 	CharLimit          int
 }
 
-func Search(options SearchOptions) (string, error) {
+// Search 返回匹配结果和匹配条目数量
+func Search(options SearchOptions) (string, int, error) {
 	//获取cpu核心数
 	numCores := runtime.NumCPU()
 	maxWorkers := numCores / 2
@@ -188,7 +200,7 @@ func Search(options SearchOptions) (string, error) {
 	}
 
 	if _, err := os.Stat(options.Path); os.IsNotExist(err) {
-		return "", fmt.Errorf("路径 %s 不存在，请输入正确路径", options.Path)
+		return "", 0, status.Errorf(status.ErrNotFound, "路径 %s 不存在，请输入正确路径", options.Path)
 	}
 
 	var CompiledRegexes []*regexp.Regexp
@@ -202,11 +214,10 @@ func Search(options SearchOptions) (string, error) {
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("编译正则表达式失败: %v", err)
+		return "", 0, i18n.Errorf("编译正则表达式失败: %v", err)
 	}
 
-	fmt.Println("正在搜索文件，路径:", options.Path)
-	fmt.Println("这可能需要一些时间，请稍候...")
+	logger.Info(i18n.T("正在搜索文件，这可能需要一些时间，请稍候..."), "path", options.Path)
 
 	resultChan := make(chan []string)
 	errChan := make(chan error)
@@ -231,10 +242,11 @@ func Search(options SearchOptions) (string, error) {
 			}
 
 			if err != nil {
-				fmt.Println("获取绝对路径失败:", err)
+				logger.Warn(i18n.Sprintf("获取绝对路径失败: %v", err), "path", path)
 				return nil
 			}
 
+			logger.Trace(i18n.T("扫描文件"), "path", path)
 			res, err := SearchConfigFiles(path, info, CompiledRegexes, options.CustomFileTypeList, options.ExtenOnlyFlag, options.SizeLimit, options.CharLimit)
 			if err != nil {
 				errChan <- err
@@ -256,26 +268,24 @@ func Search(options SearchOptions) (string, error) {
 
 	start := time.Now()
 	numScannedFiles := 0
+	numMatches := 0
 	var resultSummary strings.Builder
 
 	for {
 		select {
 		case results, ok := <-resultChan:
 			if !ok {
-				end := time.Now()
-				summary := fmt.Sprintf("\n搜索完成，时间: %s。总搜索时间: %v。\n", end.Format(time.RFC3339), end.Sub(start))
-				fmt.Print(summary)
-				//resultSummary.WriteString(summary)
-				return resultSummary.String(), nil
+				logger.EndProgress()
+				logger.Info(i18n.T("搜索完成"), "files", numScannedFiles, "matches", numMatches, "duration", time.Since(start))
+				return resultSummary.String(), numMatches, nil
 			}
 			for _, result := range results {
 				resultSummary.WriteString(result + "\n")
 			}
+			numMatches += len(results)
 
 			numScannedFiles++
-			prefix := fmt.Sprintf("正在扫描有效文件... %d", numScannedFiles)
-			fmt.Printf("\r%s", prefix)
-			fmt.Print("\033[0K")
+			logger.Progress(i18n.Sprintf("正在扫描有效文件... %d", numScannedFiles))
 
 		case fastCodeHistory, ok := <-fastCodeHistoryChan:
 			if !ok {
@@ -285,7 +295,7 @@ func Search(options SearchOptions) (string, error) {
 
 		case err := <-errChan:
 			if err != nil {
-				fmt.Println("搜索文件时出错:", err)
+				logger.Warn(i18n.Sprintf("搜索文件时出错: %v", err))
 			}
 		}
 	}
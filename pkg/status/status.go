@@ -0,0 +1,174 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"e0e1-config/pkg/i18n"
+)
+
+// 模块返回的错误都应能用 errors.Is 匹配到下面其中一种，
+// 未匹配到的统一视为 Failed
+var (
+	ErrNotInstalled              = i18n.New("未安装")
+	ErrNotFound                  = i18n.New("未找到")
+	ErrAccessDenied              = i18n.New("权限不足")
+	ErrProtectedByMasterPassword = i18n.New("受主密码保护")
+	ErrUnsupportedVersion        = i18n.New("不支持的版本")
+	ErrDecryptFailed             = i18n.New("解密失败")
+)
+
+// Kind 模块的执行状态，取值是稳定的英文标识，显示时再翻译
+type Kind string
+
+const (
+	OK                        Kind = "ok"
+	NotInstalled              Kind = "not_installed"
+	NotFound                  Kind = "not_found"
+	AccessDenied              Kind = "access_denied"
+	ProtectedByMasterPassword Kind = "master_password"
+	UnsupportedVersion        Kind = "unsupported_version"
+	DecryptFailed             Kind = "decrypt_failed"
+	Failed                    Kind = "failed"
+)
+
+var kinds = []struct {
+	err   error
+	kind  Kind
+	label string
+}{
+	{ErrNotInstalled, NotInstalled, "未安装"},
+	{ErrNotFound, NotFound, "未找到"},
+	{ErrAccessDenied, AccessDenied, "权限不足"},
+	{ErrProtectedByMasterPassword, ProtectedByMasterPassword, "受主密码保护"},
+	{ErrUnsupportedVersion, UnsupportedVersion, "不支持的版本"},
+	{ErrDecryptFailed, DecryptFailed, "解密失败"},
+}
+
+// Classify 返回错误对应的状态，文件权限和文件不存在的系统错误也会被识别
+func Classify(err error) Kind {
+	if err == nil {
+		return OK
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return AccessDenied
+	case errors.Is(err, fs.ErrNotExist):
+		return NotFound
+	}
+	return Failed
+}
+
+func (k Kind) String() string {
+	switch k {
+	case OK:
+		return i18n.T("成功")
+	case Failed:
+		return i18n.T("失败")
+	}
+	for _, item := range kinds {
+		if item.kind == k {
+			return i18n.T(item.label)
+		}
+	}
+	return string(k)
+}
+
+// Errorf 与 fmt.Errorf 相同，返回的错误同时可以用 errors.Is 匹配到 kind
+func Errorf(kind error, format string, a ...interface{}) error {
+	return &kindError{kind: kind, err: i18n.Errorf(format, a...)}
+}
+
+// New 用于包级别的错误变量，错误信息在 Error() 时才翻译
+func New(kind error, msg string) error {
+	return &kindError{kind: kind, err: i18n.New(msg)}
+}
+
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// Record 一个模块的执行结果，Items 为 -1 表示该模块不统计条目数
+type Record struct {
+	Module   string
+	Kind     Kind
+	Items    int
+	Duration time.Duration
+	Err      error
+}
+
+// Table 生成运行结束后的状态汇总表
+func Table(records []Record) string {
+	rows := [][]string{{i18n.T("模块"), i18n.T("状态"), i18n.T("条目"), i18n.T("耗时"), i18n.T("说明")}}
+	for _, r := range records {
+		items := "-"
+		if r.Items >= 0 {
+			items = fmt.Sprint(r.Items)
+		}
+		message := ""
+		if r.Err != nil {
+			message = r.Err.Error()
+		}
+		rows = append(rows, []string{r.Module, r.Kind.String(), items, r.Duration.Round(time.Millisecond).String(), message})
+	}
+
+	return i18n.T("===== 执行状态 =====\n") + FormatRows(rows)
+}
+
+// FormatRows 按列对齐输出表格，第一行为表头
+func FormatRows(rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := displayWidth(cell); i < len(widths) && w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var result strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			result.WriteString(cell)
+			if i < len(row)-1 {
+				result.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// displayWidth 中文等全角字符在终端中占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x1100 && (r <= 0x115f || (r >= 0x2e80 && r <= 0xa4cf) || (r >= 0xac00 && r <= 0xd7a3) ||
+			(r >= 0xf900 && r <= 0xfaff) || (r >= 0xfe30 && r <= 0xfe4f) || (r >= 0xff00 && r <= 0xff60) || (r >= 0xffe0 && r <= 0xffe6)) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
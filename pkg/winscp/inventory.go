@@ -0,0 +1,57 @@
+package winscp
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InventoryWinSCP 统计注册表和winscp.ini中的会话数量，不解密
+func InventoryWinSCP(configPath string) (string, error) {
+	var result strings.Builder
+	found := false
+
+	if sessions, err := readRegistrySessions(); err == nil {
+		found = true
+		result.WriteString(i18n.Sprintf("注册表: HKEY_CURRENT_USER\\%s\n", registryPath))
+		result.WriteString(countSessions(sessions, readRegistrySecurity()))
+	}
+
+	if configPath == "" {
+		configPath = filepath.Join(os.Getenv("APPDATA"), "winscp.ini")
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		sessions, security, err := ParseINI(configPath)
+		if err == nil {
+			found = true
+			result.WriteString(i18n.Sprintf("配置文件: %s\n", configPath))
+			result.WriteString(countSessions(sessions, security))
+		}
+	}
+
+	if !found {
+		return "", status.Errorf(status.ErrNotInstalled, "未找到 WinSCP 连接信息")
+	}
+	return result.String(), nil
+}
+
+func countSessions(sessions []Session, security Security) string {
+	count, saved := 0, 0
+	for _, session := range sessions {
+		if session.Get("HostName") == "" {
+			continue
+		}
+		count++
+		if session.Get("Password") != "" {
+			saved++
+		}
+	}
+
+	line := i18n.Sprintf("  会话: %d 个，保存密码: %d 个\n", count, saved)
+	if security.UseMasterPassword {
+		line += i18n.T("  主密码: 已启用\n")
+	}
+	return line
+}
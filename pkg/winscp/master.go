@@ -0,0 +1,172 @@
+package winscp
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 启用主密码后WinSCP使用 Gladman fileenc(AES-256 CTR + HMAC-SHA1) 加密密码，
+// 存储格式为按普通算法编码的标志 FF、01，之后是 salt(16) + 密文 + MAC(10) 的十六进制，
+// 所以密文实际以 A35D 开头
+const (
+	PW_EXTERNAL       = 0x01
+	fcryptKeyLength   = 32
+	fcryptSaltLength  = 16
+	fcryptMacLength   = 10
+	fcryptPwdVerLen   = 2
+	fcryptIterations  = 1000
+	verifierDummySize = 16
+)
+
+var (
+	ErrMasterPasswordRequired = status.New(status.ErrProtectedByMasterPassword, "配置启用了主密码，需要使用 -winscp-master-password 指定")
+	ErrMasterPasswordWrong    = status.New(status.ErrDecryptFailed, "主密码校验失败")
+)
+
+var MasterPassword string
+
+func SetMasterPassword(password string) {
+	MasterPassword = password
+}
+
+// Security 对应 Configuration\Security 下的主密码配置
+type Security struct {
+	UseMasterPassword bool
+	Verifier          string
+}
+
+// IsMasterPasswordEncrypted 判断密文是否为主密码模式下的AES格式，
+// 前两个字符按普通算法解码后依次为 FF、01(普通模式下第二个为 00)
+func IsMasterPasswordEncrypted(encrypted string) bool {
+	_, ok := externalPayload(encrypted)
+	return ok
+}
+
+// externalPayload 去掉 FF 01 标志，返回 salt + 密文 + MAC 的十六进制
+func externalPayload(encrypted string) (string, bool) {
+	encrypted = strings.ToUpper(encrypted)
+	if len(encrypted) < 4 || !validEncryptedPassword(encrypted[:4]) {
+		return "", false
+	}
+	flag, rest := DecryptNextCharacterWinSCP(encrypted)
+	if flag != PW_FLAG {
+		return "", false
+	}
+	flag, rest = DecryptNextCharacterWinSCP(rest)
+	if flag != PW_EXTERNAL {
+		return "", false
+	}
+	return rest, true
+}
+
+// VerifyMasterPassword 校验 MasterPasswordVerifier，格式为 dummy(16) + salt(16) + Enc(dummy) + MAC(10)
+func VerifyMasterPassword(verifierHex, password string) bool {
+	verifier, err := hex.DecodeString(verifierHex)
+	if err != nil || len(verifier) < verifierDummySize+fcryptSaltLength+fcryptMacLength {
+		return false
+	}
+
+	dummy := verifier[:verifierDummySize]
+	plain, err := aes256DecryptWithMAC(verifier[verifierDummySize:], password)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(plain, dummy)
+}
+
+func DecryptMasterPassword(encrypted, password string) (string, error) {
+	if password == "" {
+		return "", ErrMasterPasswordRequired
+	}
+
+	payload, ok := externalPayload(encrypted)
+	if !ok {
+		return "", i18n.Errorf("不是主密码加密的密文")
+	}
+	data, err := hex.DecodeString(payload)
+	if err != nil {
+		return "", i18n.Errorf("十六进制解码失败: %v", err)
+	}
+
+	plain, err := aes256DecryptWithMAC(data, password)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func aes256DecryptWithMAC(data []byte, password string) ([]byte, error) {
+	if len(data) < fcryptSaltLength+fcryptMacLength {
+		return nil, i18n.Errorf("密文长度不足")
+	}
+
+	salt := data[:fcryptSaltLength]
+	cipherText := data[fcryptSaltLength : len(data)-fcryptMacLength]
+	mac := data[len(data)-fcryptMacLength:]
+
+	keys := pbkdf2.Key([]byte(password), salt, fcryptIterations, 2*fcryptKeyLength+fcryptPwdVerLen, sha1.New)
+
+	h := hmac.New(sha1.New, keys[fcryptKeyLength:2*fcryptKeyLength])
+	h.Write(cipherText)
+	if !hmac.Equal(h.Sum(nil)[:fcryptMacLength], mac) {
+		return nil, ErrMasterPasswordWrong
+	}
+
+	return fcryptCTR(keys[:fcryptKeyLength], cipherText)
+}
+
+// fcryptCTR fileenc的CTR模式：nonce前8字节是从1开始的小端计数器
+func fcryptCTR(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aes.BlockSize)
+	stream := make([]byte, aes.BlockSize)
+	out := make([]byte, len(data))
+
+	for i := range data {
+		if i%aes.BlockSize == 0 {
+			for j := 0; j < 8; j++ {
+				nonce[j]++
+				if nonce[j] != 0 {
+					break
+				}
+			}
+			block.Encrypt(stream, nonce)
+		}
+		out[i] = data[i] ^ stream[i%aes.BlockSize]
+	}
+
+	return out, nil
+}
+
+// decryptPassword 根据密文格式选择普通算法或主密码算法，无法解密时返回提示信息且ok为false
+func decryptPassword(host, userName, encrypted string, security Security) (password string, ok bool) {
+	if !IsMasterPasswordEncrypted(encrypted) {
+		password = DecryptWinSCPPassword(host, userName, encrypted)
+		return password, password != ""
+	}
+
+	if MasterPassword == "" {
+		return i18n.Sprintf("[主密码保护] %v", ErrMasterPasswordRequired), false
+	}
+	if security.Verifier != "" && !VerifyMasterPassword(security.Verifier, MasterPassword) {
+		return i18n.Sprintf("[主密码保护] %v", ErrMasterPasswordWrong), false
+	}
+
+	password, err := DecryptMasterPassword(encrypted, MasterPassword)
+	if err != nil {
+		return i18n.Sprintf("[主密码保护] 解密失败: %v", err), false
+	}
+	return password, true
+}
@@ -0,0 +1,79 @@
+package winscp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// encryptExternal 按WinSCP主密码模式的格式加密，用于构造测试数据
+func encryptExternal(t *testing.T, plain, password string) string {
+	t.Helper()
+	salt := []byte("0123456789abcdef")
+	keys := pbkdf2.Key([]byte(password), salt, fcryptIterations, 2*fcryptKeyLength+fcryptPwdVerLen, sha1.New)
+	cipherText, err := fcryptCTR(keys[:fcryptKeyLength], []byte(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := hmac.New(sha1.New, keys[fcryptKeyLength:2*fcryptKeyLength])
+	h.Write(cipherText)
+
+	data := append(append(append([]byte(nil), salt...), cipherText...), h.Sum(nil)[:fcryptMacLength]...)
+	return "A35D" + strings.ToUpper(hex.EncodeToString(data))
+}
+
+func TestIsMasterPasswordEncrypted(t *testing.T) {
+	tests := []struct {
+		name      string
+		encrypted string
+		want      bool
+	}{
+		{"external", "A35D" + strings.Repeat("00", 26), true},
+		{"lowercase", "a35d" + strings.Repeat("00", 26), true},
+		{"internal flag", "A35C" + strings.Repeat("00", 26), false},
+		{"simple", "A3", false},
+		{"old wrong prefix", "FF01" + strings.Repeat("00", 26), false},
+		{"not hex", "A3XZ", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := IsMasterPasswordEncrypted(tt.encrypted); got != tt.want {
+			t.Errorf("%s: IsMasterPasswordEncrypted(%q) = %v, want %v", tt.name, tt.encrypted, got, tt.want)
+		}
+	}
+}
+
+func TestDecryptMasterPassword(t *testing.T) {
+	encrypted := encryptExternal(t, "s3cret-password", "master")
+
+	got, err := DecryptMasterPassword(encrypted, "master")
+	if err != nil || got != "s3cret-password" {
+		t.Fatalf("DecryptMasterPassword() = %q, %v", got, err)
+	}
+	if _, err := DecryptMasterPassword(encrypted, "wrong"); err != ErrMasterPasswordWrong {
+		t.Errorf("wrong master password: err = %v, want %v", err, ErrMasterPasswordWrong)
+	}
+	if _, err := DecryptMasterPassword(encrypted, ""); err != ErrMasterPasswordRequired {
+		t.Errorf("empty master password: err = %v, want %v", err, ErrMasterPasswordRequired)
+	}
+}
+
+func TestDecryptPasswordMasterMode(t *testing.T) {
+	defer SetMasterPassword("")
+	encrypted := encryptExternal(t, "s3cret-password", "master")
+
+	SetMasterPassword("")
+	if _, ok := decryptPassword("host", "user", encrypted, Security{}); ok {
+		t.Error("decrypted without master password")
+	}
+
+	SetMasterPassword("master")
+	got, ok := decryptPassword("host", "user", encrypted, Security{})
+	if !ok || got != "s3cret-password" {
+		t.Errorf("decryptPassword() = %q, %v", got, ok)
+	}
+}
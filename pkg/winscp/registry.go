@@ -0,0 +1,62 @@
+package winscp
+
+import (
+	"strconv"
+
+	"e0e1-config/pkg/regsource"
+)
+
+func readRegistrySessions() ([]Session, error) {
+	key, err := regsource.OpenKey(regsource.CurrentUser, registryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+
+	subKeys, err := key.SubKeyNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, subKeyName := range subKeys {
+		subKey, err := key.OpenSubKey(subKeyName)
+		if err != nil {
+			continue
+		}
+
+		session := Session{Name: unescapeValue(subKeyName), Values: make(map[string]string)}
+		valueNames, _ := subKey.ValueNames()
+		for _, name := range valueNames {
+			if value, err := regsource.GetString(subKey, name); err == nil {
+				session.Values[name] = value
+			} else if value, err := regsource.GetInteger(subKey, name); err == nil {
+				session.Values[name] = strconv.FormatUint(value, 10)
+			}
+		}
+		subKey.Close()
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func readRegistrySecurity() Security {
+	var security Security
+
+	key, err := regsource.OpenKey(regsource.CurrentUser, securityRegistryPath)
+	if err != nil {
+		return security
+	}
+	defer key.Close()
+
+	if value, err := regsource.GetInteger(key, "UseMasterPassword"); err == nil {
+		security.UseMasterPassword = value == 1
+	}
+	if value, err := regsource.GetString(key, "MasterPasswordVerifier"); err == nil {
+		security.Verifier = value
+	}
+
+	return security
+}
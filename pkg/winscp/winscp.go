@@ -1,12 +1,15 @@
 package winscp
 
 import (
+	"bufio"
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
-
-	"golang.org/x/sys/windows/registry"
 )
 
 const (
@@ -14,6 +17,12 @@ const (
 	PW_FLAG  = 0xFF
 )
 
+const (
+	registryPath         = `Software\Martin Prikryl\WinSCP 2\Sessions`
+	securityRegistryPath = `Software\Martin Prikryl\WinSCP 2\Configuration\Security`
+	securitySection      = `Configuration\Security`
+)
+
 func DecryptNextCharacterWinSCP(passwd string) (flag rune, remainingPass string) {
 	bases := "0123456789ABCDEF"
 
@@ -29,11 +38,19 @@ func DecryptWinSCPPassword(host, userName, passWord string) string {
 	var clearpwd strings.Builder
 	var length rune
 	unicodeKey := userName + host
+
+	if !validEncryptedPassword(passWord) {
+		return ""
+	}
+
 	flag, remainingPass := DecryptNextCharacterWinSCP(passWord)
 
 	storedFlag := flag
 
 	if storedFlag == PW_FLAG {
+		if len(remainingPass) < 6 {
+			return ""
+		}
 		flag, remainingPass = DecryptNextCharacterWinSCP(remainingPass)
 		flag, remainingPass = DecryptNextCharacterWinSCP(remainingPass)
 		length = flag
@@ -41,7 +58,13 @@ func DecryptWinSCPPassword(host, userName, passWord string) string {
 		length = flag
 	}
 
+	if len(remainingPass) < 2 {
+		return ""
+	}
 	flag, remainingPass = DecryptNextCharacterWinSCP(remainingPass)
+	if len(remainingPass) < int(flag)*2+int(length)*2 {
+		return ""
+	}
 	remainingPass = remainingPass[int(flag)*2:]
 
 	for i := 0; i < int(length); i++ {
@@ -58,45 +81,246 @@ func DecryptWinSCPPassword(host, userName, passWord string) string {
 	return clearpwd.String()
 }
 
-func ScanWinSCP(configPath string) (string, error) {
+// validEncryptedPassword 密文是偶数长度的大写十六进制串，否则 DecryptNextCharacterWinSCP 会越界
+func validEncryptedPassword(passWord string) bool {
+	if len(passWord) < 2 || len(passWord)%2 != 0 {
+		return false
+	}
+	for _, c := range passWord {
+		if !strings.ContainsRune("0123456789ABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Session 是一个WinSCP会话的原始配置项，注册表和winscp.ini读出来的值都转成字符串保存，
+// 这样两种来源可以共用同一套输出逻辑
+type Session struct {
+	Name   string
+	Values map[string]string
+}
+
+func (s Session) Get(name string) string {
+	return s.Values[name]
+}
+
+var fsProtocols = map[string]string{
+	"0": "SCP",
+	"1": "SFTP",
+	"2": "SFTP",
+	"5": "FTP",
+	"6": "WebDAV",
+	"7": "S3",
+}
+
+var proxyMethods = map[string]string{
+	"0": "无",
+	"1": "SOCKS4",
+	"2": "SOCKS5",
+	"3": "HTTP",
+	"4": "Telnet",
+	"5": "Local",
+}
+
+func fsProtocol(value string) string {
+	if value == "" {
+		return "SFTP"
+	}
+	if name, ok := fsProtocols[value]; ok {
+		return name
+	}
+	return i18n.Sprintf("未知(%s)", value)
+}
+
+// unescapeValue WinSCP 在ini中对会话名和部分值做了 %XX 转义
+func unescapeValue(value string) string {
+	unescaped, err := url.PathUnescape(value)
+	if err != nil {
+		return value
+	}
+	return unescaped
+}
+
+// ParseINI 解析winscp.ini中的 [Sessions\...] 和 [Configuration\Security] 小节，
+// 不依赖注册表，可以处理任意系统上拷贝出来的配置文件
+func ParseINI(configPath string) ([]Session, Security, error) {
+	var security Security
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		return nil, security, err
+	}
+	defer file.Close()
+
+	var sessions []Session
+	var current *Session
+	inSecurity := false
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := line[1 : len(line)-1]
+			current = nil
+			inSecurity = section == securitySection
+			if strings.HasPrefix(section, `Sessions\`) {
+				sessions = append(sessions, Session{
+					Name:   unescapeValue(strings.TrimPrefix(section, `Sessions\`)),
+					Values: make(map[string]string),
+				})
+				current = &sessions[len(sessions)-1]
+			}
+			continue
+		}
+
+		index := strings.Index(line, "=")
+		if index <= 0 {
+			continue
+		}
+		name, value := line[:index], unescapeValue(line[index+1:])
+
+		if inSecurity {
+			switch name {
+			case "UseMasterPassword":
+				security.UseMasterPassword = value == "1"
+			case "MasterPasswordVerifier":
+				security.Verifier = value
+			}
+			continue
+		}
+
+		if current != nil {
+			current.Values[name] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, security, err
+	}
+
+	return sessions, security, nil
+}
+
+func formatSession(session Session, security Security) string {
 	var result strings.Builder
-	var foundData bool
 
-	registryPath := `Software\Martin Prikryl\WinSCP 2\Sessions`
-	key, err := registry.OpenKey(registry.CURRENT_USER, registryPath, registry.READ)
-	if err == nil {
-		defer key.Close()
-
-		result.WriteString("=== WinSCP 注册表信息 ===\n")
-		result.WriteString(fmt.Sprintf("注册表位置: HKEY_CURRENT_USER\\%s\n\n", registryPath))
-
-		subKeys, err := key.ReadSubKeyNames(0)
-		if err == nil {
-			for _, subKeyName := range subKeys {
-				subKey, err := registry.OpenKey(registry.CURRENT_USER, registryPath+"\\"+subKeyName, registry.READ)
-				if err == nil {
-					defer subKey.Close()
-
-					hostname, _, err := subKey.GetStringValue("HostName")
-					if err == nil && hostname != "" {
-						username, _, _ := subKey.GetStringValue("UserName")
-						password, _, err := subKey.GetStringValue("Password")
-						portNumer, _, _ := subKey.GetIntegerValue("PortNumber")
-						if err == nil {
-							foundData = true
-							result.WriteString(fmt.Sprintf("会话名称: %s\n", subKeyName))
-							result.WriteString(fmt.Sprintf("主机名: %s\n", hostname))
-							result.WriteString(fmt.Sprintf("Port: %v\n", portNumer))
-							result.WriteString(fmt.Sprintf("用户名: %s\n", username))
-							result.WriteString(fmt.Sprintf("加密密码: %s\n", password))
-							result.WriteString(fmt.Sprintf("解密密码: %s\n\n", DecryptWinSCPPassword(hostname, username, password)))
-						}
-					}
-				}
+	hostname := session.Get("HostName")
+	username := session.Get("UserName")
+	password := session.Get("Password")
+
+	result.WriteString(i18n.Sprintf("会话名称: %s\n", session.Name))
+	result.WriteString(i18n.Sprintf("主机名: %s\n", hostname))
+	result.WriteString(fmt.Sprintf("Port: %s\n", session.Get("PortNumber")))
+	result.WriteString(i18n.Sprintf("协议: %s\n", fsProtocol(session.Get("FSProtocol"))))
+	result.WriteString(i18n.Sprintf("用户名: %s\n", username))
+	if password != "" {
+		result.WriteString(i18n.Sprintf("加密密码: %s\n", password))
+		decrypted, ok := decryptPassword(hostname, username, password, security)
+		result.WriteString(i18n.Sprintf("解密密码: %s\n", decrypted))
+		if ok {
+			credential.Add(credential.Credential{Source: "WinSCP", Name: session.Name, Host: hostname, Port: session.Get("PortNumber"), User: username, Password: decrypted})
+		}
+	}
+	if keyFile := session.Get("PublicKeyFile"); keyFile != "" {
+		result.WriteString(i18n.Sprintf("私钥文件: %s\n", keyFile))
+	}
+
+	if method := session.Get("ProxyMethod"); method != "" && method != "0" {
+		proxyHost := session.Get("ProxyHost")
+		proxyUser := session.Get("ProxyUsername")
+		methodName, ok := proxyMethods[method]
+		if ok {
+			methodName = i18n.T(methodName)
+		} else {
+			methodName = i18n.Sprintf("未知(%s)", method)
+		}
+		result.WriteString(i18n.Sprintf("代理: %s %s:%s\n", methodName, proxyHost, session.Get("ProxyPort")))
+		if proxyUser != "" {
+			result.WriteString(i18n.Sprintf("代理用户名: %s\n", proxyUser))
+		}
+		if encrypted := session.Get("ProxyPasswordEnc"); encrypted != "" {
+			decrypted, ok := decryptPassword(proxyHost, proxyUser, encrypted, security)
+			result.WriteString(i18n.Sprintf("代理密码: %s\n", decrypted))
+			if ok {
+				credential.Add(credential.Credential{Source: "WinSCP", Name: session.Name + i18n.T("(代理)"), Host: proxyHost, Port: session.Get("ProxyPort"), User: proxyUser, Password: decrypted})
 			}
+		} else if plain := session.Get("ProxyPassword"); plain != "" {
+			result.WriteString(i18n.Sprintf("代理密码: %s\n", plain))
 		}
+	}
+
+	if session.Get("Tunnel") == "1" {
+		tunnelHost := session.Get("TunnelHostName")
+		tunnelUser := session.Get("TunnelUserName")
+		result.WriteString(i18n.Sprintf("SSH隧道: %s:%s\n", tunnelHost, session.Get("TunnelPortNumber")))
+		result.WriteString(i18n.Sprintf("隧道用户名: %s\n", tunnelUser))
+		if encrypted := session.Get("TunnelPasswordEnc"); encrypted != "" {
+			decrypted, ok := decryptPassword(tunnelHost, tunnelUser, encrypted, security)
+			result.WriteString(i18n.Sprintf("隧道密码: %s\n", decrypted))
+			if ok {
+				credential.Add(credential.Credential{Source: "WinSCP", Name: session.Name + i18n.T("(SSH隧道)"), Host: tunnelHost, Port: session.Get("TunnelPortNumber"), User: tunnelUser, Password: decrypted})
+			}
+		}
+		if keyFile := session.Get("TunnelPublicKeyFile"); keyFile != "" {
+			result.WriteString(i18n.Sprintf("隧道私钥文件: %s\n", keyFile))
+		}
+	}
+
+	result.WriteString("\n")
+	return result.String()
+}
+
+// formatSessions 返回会话的显示内容和数量，启用了主密码但无法解密时同时返回对应的错误
+func formatSessions(sessions []Session, security Security) (string, int, error) {
+	var result strings.Builder
+	var err error
+	count := 0
+	if security.UseMasterPassword {
+		if MasterPassword == "" {
+			err = ErrMasterPasswordRequired
+			result.WriteString(i18n.T("主密码: 已启用，未提供主密码，会话密码无法解密\n\n"))
+		} else if security.Verifier != "" && !VerifyMasterPassword(security.Verifier, MasterPassword) {
+			err = ErrMasterPasswordWrong
+			result.WriteString(i18n.T("主密码: 已启用，提供的主密码校验失败\n\n"))
+		} else {
+			result.WriteString(i18n.T("主密码: 已启用，使用提供的主密码解密\n\n"))
+		}
+	}
+	for _, session := range sessions {
+		if session.Get("HostName") == "" {
+			continue
+		}
+		count++
+		result.WriteString(formatSession(session, security))
+	}
+	return result.String(), count, err
+}
+
+// ScanWinSCP 返回注册表和配置文件中的会话以及会话数量
+func ScanWinSCP(configPath string) (string, int, error) {
+	var result strings.Builder
+	count := 0
+	installed := false
+	var firstErr error
+
+	sessions, err := readRegistrySessions()
+	if err == nil {
+		installed = true
+		result.WriteString(i18n.T("=== WinSCP 注册表信息 ===\n"))
+		result.WriteString(i18n.Sprintf("注册表位置: HKEY_CURRENT_USER\\%s\n\n", registryPath))
+
+		output, n, err := formatSessions(sessions, readRegistrySecurity())
+		result.WriteString(output)
+		count += n
+		firstErr = err
 	} else {
-		result.WriteString(fmt.Sprintf("未找到 WinSCP 注册表位置: HKEY_CURRENT_USER\\%s\n", registryPath))
+		result.WriteString(i18n.Sprintf("未找到 WinSCP 注册表位置: HKEY_CURRENT_USER\\%s\n", registryPath))
 	}
 
 	if configPath == "" {
@@ -106,16 +330,30 @@ func ScanWinSCP(configPath string) (string, error) {
 	}
 
 	if _, err := os.Stat(configPath); err == nil {
+		installed = true
+
+		result.WriteString(i18n.T("=== WinSCP 配置文件信息 ===\n"))
+		result.WriteString(i18n.Sprintf("配置文件位置: %s\n\n", configPath))
 
-		result.WriteString("=== WinSCP 配置文件信息 ===\n")
-		result.WriteString(fmt.Sprintf("配置文件位置: %s\n", configPath))
-		result.WriteString("配置文件解析功能待实现\n")
-		foundData = true
+		sessions, security, err := ParseINI(configPath)
+		if err != nil {
+			result.WriteString(i18n.Sprintf("配置文件解析失败: %v\n", err))
+		} else {
+			output, n, err := formatSessions(sessions, security)
+			result.WriteString(output)
+			count += n
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
 	}
 
-	if !foundData {
-		return "", fmt.Errorf("未找到 WinSCP 连接信息")
+	if !installed {
+		return "", 0, status.Errorf(status.ErrNotInstalled, "未找到 WinSCP 连接信息")
+	}
+	if count == 0 {
+		return "", 0, status.Errorf(status.ErrNotFound, "未找到 WinSCP 连接信息")
 	}
 
-	return result.String(), nil
+	return result.String(), count, firstErr
 }
@@ -0,0 +1,105 @@
+package winscp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// encryptSimple 按WinSCP未启用主密码时的算法加密，密钥为 用户名+主机名
+func encryptSimple(host, user, password string) string {
+	encryptChar := func(c byte) string { return fmt.Sprintf("%02X", (^c)^PW_MAGIC) }
+	plain := user + host + password
+
+	var sb strings.Builder
+	sb.WriteString(encryptChar(PW_FLAG))
+	sb.WriteString(encryptChar(0))
+	sb.WriteString(encryptChar(byte(len(plain))))
+	sb.WriteString(encryptChar(2))
+	sb.WriteString(encryptChar('x') + encryptChar('y'))
+	for i := 0; i < len(plain); i++ {
+		sb.WriteString(encryptChar(plain[i]))
+	}
+	return sb.String()
+}
+
+func TestDecryptWinSCPPassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		user      string
+		encrypted string
+		want      string
+	}{
+		{"round trip", "10.0.0.1", "root", encryptSimple("10.0.0.1", "root", "P@ssw0rd"), "P@ssw0rd"},
+		{"wrong key", "10.0.0.2", "root", encryptSimple("10.0.0.1", "root", "P@ssw0rd"), ""},
+		{"odd length", "h", "u", "A35", ""},
+		{"lowercase", "h", "u", "a35c", ""},
+		{"truncated", "h", "u", "A35C", ""},
+	}
+	for _, tt := range tests {
+		if got := DecryptWinSCPPassword(tt.host, tt.user, tt.encrypted); got != tt.want {
+			t.Errorf("%s: DecryptWinSCPPassword() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseINI(t *testing.T) {
+	ini := "\ufeff[Configuration\\Security]\r\n" +
+		"UseMasterPassword=1\r\n" +
+		"MasterPasswordVerifier=ABCD\r\n" +
+		"; comment\r\n" +
+		"[Sessions\\My%20Folder/prod%20server]\r\n" +
+		"HostName=10.0.0.1\r\n" +
+		"UserName=root\r\n" +
+		"Password=" + encryptSimple("10.0.0.1", "root", "P@ssw0rd") + "\r\n" +
+		"PublicKeyFile=C:%5Ckeys%5Cid.ppk\r\n" +
+		"\r\n" +
+		"[Sessions\\Default%20Settings]\r\n" +
+		"PortNumber=2222\r\n" +
+		"invalid line\r\n" +
+		"[Configuration\\Interface]\r\n" +
+		"HostName=ignored\r\n"
+
+	path := filepath.Join(t.TempDir(), "winscp.ini")
+	if err := os.WriteFile(path, []byte(ini), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, security, err := ParseINI(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !security.UseMasterPassword || security.Verifier != "ABCD" {
+		t.Errorf("security = %+v", security)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2: %+v", len(sessions), sessions)
+	}
+
+	s := sessions[0]
+	if s.Name != "My Folder/prod server" || s.Get("HostName") != "10.0.0.1" || s.Get("PublicKeyFile") != `C:\keys\id.ppk` {
+		t.Errorf("session = %+v", s)
+	}
+	if got := DecryptWinSCPPassword(s.Get("HostName"), s.Get("UserName"), s.Get("Password")); got != "P@ssw0rd" {
+		t.Errorf("password = %q", got)
+	}
+	if sessions[1].Name != "Default Settings" || sessions[1].Get("PortNumber") != "2222" || sessions[1].Get("HostName") != "" {
+		t.Errorf("default session = %+v", sessions[1])
+	}
+
+	if _, _, err := ParseINI(filepath.Join(t.TempDir(), "missing.ini")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestFSProtocol(t *testing.T) {
+	tests := map[string]string{"": "SFTP", "0": "SCP", "5": "FTP", "7": "S3"}
+	for value, want := range tests {
+		if got := fsProtocol(value); got != want {
+			t.Errorf("fsProtocol(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package xshell
+
+import (
+	"e0e1-config/pkg/i18n"
+	"strings"
+)
+
+func InventoryXshell(customPath string) (string, error) {
+	return inventorySessions(customPath, "Xshell", ".xsh")
+}
+
+func InventoryXftp(customPath string) (string, error) {
+	return inventorySessions(customPath, "Xftp", ".xfp")
+}
+
+// inventorySessions 与 scanSessions 使用相同的路径发现逻辑，只统计会话和密钥数量，不解密
+func inventorySessions(customPath, product, ext string) (string, error) {
+	var result strings.Builder
+
+	userDataPaths := []string{customPath}
+	if customPath == "" {
+		var err error
+		userDataPaths, err = getUserDataPath()
+		if err != nil {
+			return "", i18n.Errorf("获取%s用户数据路径失败: %v", product, err)
+		}
+	}
+
+	found := false
+	for _, userDataPath := range userDataPaths {
+		sessionsPath, pathList, err := enumSessionPath(userDataPath, product, ext)
+		if err != nil {
+			continue
+		}
+		found = true
+
+		saved := 0
+		for _, path := range pathList {
+			if xsh, err := sessionParser(path, sessionsPath); err == nil && xsh.EncryptPw != "" {
+				saved++
+			}
+		}
+		_, userKeys := listUserKeys(sessionsPath)
+
+		result.WriteString(i18n.Sprintf("会话目录: %s\n", sessionsPath))
+		result.WriteString(i18n.Sprintf("  会话: %d 个，保存密码: %d 个，UserKeys: %d 个\n", len(pathList), saved, len(userKeys)))
+		if checkMasterPw(userDataPath) == nil && enableMasterPasswd {
+			result.WriteString(i18n.T("  主密码: 已启用\n"))
+		}
+	}
+
+	if !found {
+		return "", i18n.Errorf("未找到%s会话目录", product)
+	}
+	return result.String(), nil
+}
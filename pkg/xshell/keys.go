@@ -0,0 +1,147 @@
+package xshell
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"e0e1-config/pkg/i18n"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type sessionVersion struct {
+	Major int
+	Minor int
+}
+
+func (v sessionVersion) less(other sessionVersion) bool {
+	return v.Major < other.Major || (v.Major == other.Major && v.Minor < other.Minor)
+}
+
+// parseVersion 解析会话文件中的 Version=7.1 之类的版本号
+func parseVersion(s string) (sessionVersion, bool) {
+	parts := strings.SplitN(strings.TrimSpace(s), ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return sessionVersion{}, false
+	}
+
+	version := sessionVersion{Major: major}
+	if len(parts) > 1 {
+		minor, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return sessionVersion{}, false
+		}
+		version.Minor = minor
+	}
+	return version, true
+}
+
+// keyStrategy 描述某个版本范围内的RC4密钥派生方式，[Since, Before) 为适用的版本区间，
+// Before为零值表示之后的所有版本
+type keyStrategy struct {
+	Name     string
+	Since    sessionVersion
+	Before   sessionVersion
+	Checksum bool
+	Key      func(user UserSID) []byte
+}
+
+var keyStrategies = []keyStrategy{
+	{
+		Name:   "固定密钥",
+		Before: sessionVersion{5, 1},
+		Key: func(user UserSID) []byte {
+			key := md5.Sum([]byte("!X@s#h$e%l^l&"))
+			return key[:]
+		},
+	},
+	{
+		Name:     "SHA256(SID)",
+		Since:    sessionVersion{5, 1},
+		Before:   sessionVersion{5, 3},
+		Checksum: true,
+		Key: func(user UserSID) []byte {
+			key := sha256.Sum256([]byte(user.SID))
+			return key[:]
+		},
+	},
+	{
+		Name:     "SHA256(用户名+SID)",
+		Since:    sessionVersion{5, 3},
+		Before:   sessionVersion{7, 1},
+		Checksum: true,
+		Key: func(user UserSID) []byte {
+			key := sha256.Sum256([]byte(user.Name + user.SID))
+			return key[:]
+		},
+	},
+	{
+		Name:     "SHA256(反转(反转用户名+SID))",
+		Since:    sessionVersion{7, 1},
+		Checksum: true,
+		Key: func(user UserSID) []byte {
+			key := sha256.Sum256([]byte(reverseString(reverseString(user.Name) + user.SID)))
+			return key[:]
+		},
+	},
+}
+
+func (s keyStrategy) matches(v sessionVersion) bool {
+	if v.less(s.Since) {
+		return false
+	}
+	return s.Before == (sessionVersion{}) || v.less(s.Before)
+}
+
+func (s keyStrategy) decrypt(data []byte, user UserSID) (string, error) {
+	if s.Checksum {
+		return decryptWithChecksum(s.Key(user), data)
+	}
+
+	plain, err := rc4Decrypt(s.Key(user), data)
+	if err != nil {
+		return "", err
+	}
+	if !isPlausiblePassword(string(plain)) {
+		return "", ErrChecksumMismatch
+	}
+	return string(plain), nil
+}
+
+// isPlausiblePassword 早期版本没有SHA256校验，只能通过明文是否可打印来判断
+func isPlausiblePassword(password string) bool {
+	if password == "" || !utf8.ValidString(password) {
+		return false
+	}
+	for _, r := range password {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// decryptWithStrategies 先使用版本对应的策略，失败或版本无法识别时依次尝试所有策略
+func decryptWithStrategies(data []byte, versionString string, user UserSID) (string, string, error) {
+	version, ok := parseVersion(versionString)
+	if ok {
+		for _, strategy := range keyStrategies {
+			if !strategy.matches(version) {
+				continue
+			}
+			if password, err := strategy.decrypt(data, user); err == nil {
+				return password, i18n.T(strategy.Name), nil
+			}
+		}
+	}
+
+	for _, strategy := range keyStrategies {
+		if password, err := strategy.decrypt(data, user); err == nil {
+			return password, i18n.T(strategy.Name), nil
+		}
+	}
+
+	return "", "", i18n.Errorf("所有密钥派生方式均校验失败(版本: %s)，请检查用户名和SID", versionString)
+}
@@ -0,0 +1,91 @@
+package xshell
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/status"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+var (
+	ErrMasterPasswordRequired = status.New(status.ErrProtectedByMasterPassword, "主密码已启用，需要使用 -xshell-master-password 指定")
+	ErrMasterPasswordWrong    = status.New(status.ErrDecryptFailed, "主密码校验失败")
+	ErrChecksumMismatch       = status.New(status.ErrDecryptFailed, "解密结果校验失败")
+)
+
+var (
+	MasterPassword string
+	// 离线解密时通过 -xshell-user / -xshell-sid 指定目标用户
+	UserName string
+	SID      string
+)
+
+func SetMasterPassword(password string) {
+	MasterPassword = password
+}
+
+func SetUser(userName, sid string) {
+	UserName = userName
+	SID = sid
+}
+
+// VerifyMasterPassword 校验 MasterPassword.mpw 中的 HashMasterPasswd，
+// 兼容直接保存SHA256以及与会话密码相同的 RC4(data)+SHA256(data) 两种格式
+func VerifyMasterPassword(hash, password string) bool {
+	key := sha256.Sum256([]byte(password))
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(hash))
+	if err != nil {
+		data, err = hex.DecodeString(strings.TrimSpace(hash))
+		if err != nil {
+			return false
+		}
+	}
+
+	if len(data) == sha256.Size {
+		return subtle.ConstantTimeCompare(data, key[:]) == 1
+	}
+	if len(data) > sha256.Size {
+		_, err := decryptWithChecksum(key[:], data)
+		return err == nil
+	}
+	return false
+}
+
+// decryptWithChecksum 5.1以后的密文格式为 RC4(明文) + SHA256(明文)
+func decryptWithChecksum(key, data []byte) (string, error) {
+	if len(data) <= sha256.Size {
+		return "", i18n.Errorf("加密数据长度不足")
+	}
+
+	passData := data[:len(data)-sha256.Size]
+	checksum := data[len(data)-sha256.Size:]
+
+	plain, err := rc4Decrypt(key, passData)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(plain)
+	if subtle.ConstantTimeCompare(sum[:], checksum) != 1 {
+		return "", ErrChecksumMismatch
+	}
+	return string(plain), nil
+}
+
+// masterDecrypt 启用主密码后RC4密钥为 SHA256(主密码)，与用户名和SID无关
+func masterDecrypt(data []byte, masterPassword string) (string, error) {
+	if masterPassword == "" {
+		return "", ErrMasterPasswordRequired
+	}
+
+	key := sha256.Sum256([]byte(masterPassword))
+	password, err := decryptWithChecksum(key[:], data)
+	if err == ErrChecksumMismatch {
+		return "", ErrMasterPasswordWrong
+	}
+	return password, err
+}
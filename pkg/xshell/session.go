@@ -0,0 +1,409 @@
+package xshell
+
+import (
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/regsource"
+	"e0e1-config/pkg/status"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+type Xsh struct {
+	Path            string
+	Folder          string
+	Protocol        string
+	Host            string
+	Port            string
+	UserName        string
+	Password        string
+	EncryptPw       string
+	AuthMethod      string
+	UserKey         string
+	Passphrase      string
+	Proxy           string
+	ForwardingRules []string
+	Description     string
+	Version         string
+}
+
+// authMethods 对应 [CONNECTION:AUTHENTICATION] 中的 Method
+var authMethods = map[string]string{
+	"0": "密码",
+	"1": "公钥",
+	"2": "键盘交互",
+	"3": "GSSAPI",
+	"4": "PKCS#11",
+}
+
+func authMethodName(method string) string {
+	if name, ok := authMethods[method]; ok {
+		return i18n.T(name)
+	}
+	return method
+}
+
+// sessionFile 会话文件是INI格式，section和key均不区分大小写
+type sessionFile struct {
+	sections map[string]map[string]string
+	order    []string
+}
+
+// get 先在指定的section中查找，找不到时按文件顺序在所有section中查找
+func (f sessionFile) get(key string, sections ...string) string {
+	key = strings.ToLower(key)
+	for _, section := range sections {
+		if value, ok := f.sections[strings.ToLower(section)][key]; ok {
+			return value
+		}
+	}
+	for _, section := range f.order {
+		if value, ok := f.sections[section][key]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// decodeText 会话文件和 MasterPassword.mpw 可能是UTF-16LE编码
+func decodeText(content []byte) string {
+	isUTF16 := false
+	if len(content) >= 2 && content[0] == 0xFF && content[1] == 0xFE {
+		isUTF16 = true
+		content = content[2:]
+	} else if len(content) >= 4 && content[0] != 0 && content[1] == 0 && content[2] != 0 && content[3] == 0 {
+		isUTF16 = true
+	}
+
+	if !isUTF16 {
+		return strings.TrimPrefix(string(content), "\xEF\xBB\xBF")
+	}
+
+	utf16Chars := make([]uint16, len(content)/2)
+	for i := 0; i+1 < len(content); i += 2 {
+		utf16Chars[i/2] = uint16(content[i]) | (uint16(content[i+1]) << 8)
+	}
+	return string(utf16.Decode(utf16Chars))
+}
+
+func parseSessionFile(path string) (sessionFile, error) {
+	file := sessionFile{sections: make(map[string]map[string]string)}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return file, i18n.Errorf("读取文件失败: %v", err)
+	}
+
+	section := ""
+	file.sections[section] = make(map[string]string)
+	file.order = append(file.order, section)
+
+	for _, line := range strings.Split(decodeText(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line[1 : len(line)-1])
+			if _, ok := file.sections[section]; !ok {
+				file.sections[section] = make(map[string]string)
+				file.order = append(file.order, section)
+			}
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			continue
+		}
+		file.sections[section][strings.ToLower(line[:idx])] = line[idx+1:]
+	}
+
+	return file, nil
+}
+
+// sessionParser 解析 .xsh / .xfp 会话文件，两者的字段名基本一致，只是section不同
+func sessionParser(path, sessionsPath string) (Xsh, error) {
+	var xsh Xsh
+
+	file, err := parseSessionFile(path)
+	if err != nil {
+		return xsh, err
+	}
+
+	const (
+		connection = "CONNECTION"
+		auth       = "CONNECTION:AUTHENTICATION"
+	)
+
+	xsh.Path = path
+	if folder, err := filepath.Rel(sessionsPath, filepath.Dir(path)); err == nil && folder != "." {
+		xsh.Folder = filepath.ToSlash(folder)
+	}
+	xsh.Protocol = file.get("Protocol", connection)
+	xsh.Host = file.get("Host", connection)
+	xsh.Port = file.get("Port", connection)
+	xsh.Description = file.get("Description", connection)
+	xsh.Version = file.get("Version", connection, "SessionInfo")
+	xsh.UserName = file.get("UserName", auth)
+	xsh.EncryptPw = file.get("Password", auth)
+	xsh.AuthMethod = file.get("Method", auth)
+	if xsh.AuthMethod == "" {
+		xsh.AuthMethod = file.get("AuthMethod", auth)
+	}
+	xsh.UserKey = file.get("UserKey", auth)
+	xsh.Passphrase = file.get("Passphrase", auth)
+	xsh.Proxy = file.get("Proxy", "CONNECTION:PROXY", "Proxy")
+
+	// 端口转发规则保存为 FwdReq_0、FwdReq_1 ...
+	for _, section := range file.order {
+		var keys []string
+		for key := range file.sections[section] {
+			if strings.HasPrefix(key, "fwdreq_") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if rule := file.sections[section][key]; rule != "" {
+				xsh.ForwardingRules = append(xsh.ForwardingRules, rule)
+			}
+		}
+	}
+
+	if xsh.Version == "" {
+		xsh.Version = "7.0"
+	}
+
+	return xsh, nil
+}
+
+// isSupportedVersion 注册表和用户数据目录中的主版本号，5及之后的版本目录结构相同
+func isSupportedVersion(version string) bool {
+	v, ok := parseVersion(version)
+	return ok && v.Major >= 5
+}
+
+// isVersionDir 用户数据路径是否以版本号目录结尾，如 ...\NetSarang Computer\8
+func isVersionDir(userDataPath string) bool {
+	userDataPath = strings.TrimRight(userDataPath, `\/`)
+	return isSupportedVersion(userDataPath[strings.LastIndexAny(userDataPath, `\/`)+1:])
+}
+
+// enumSessionPath 返回Sessions目录以及其中所有的会话文件，product为Xshell或Xftp
+func enumSessionPath(userDataPath, product, ext string) (string, []string, error) {
+	var pathList []string
+	sessionsPath := userDataPath
+
+	if isVersionDir(userDataPath) {
+		sessionsPath = filepath.Join(userDataPath, product, "Sessions")
+	}
+
+	if _, err := os.Stat(sessionsPath); os.IsNotExist(err) {
+		return sessionsPath, nil, status.Errorf(status.ErrNotFound, "会话目录不存在: %s", sessionsPath)
+	}
+
+	err := filepath.Walk(sessionsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ext) {
+			pathList = append(pathList, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return sessionsPath, nil, i18n.Errorf("遍历目录失败: %v", err)
+	}
+
+	return sessionsPath, pathList, nil
+}
+
+// listUserKeys 列出UserKeys目录下的密钥文件，key为不带扩展名的密钥名称
+func listUserKeys(sessionsPath string) (string, map[string]string) {
+	keysPath := filepath.Join(filepath.Dir(sessionsPath), "UserKeys")
+	keys := make(map[string]string)
+
+	entries, err := os.ReadDir(keysPath)
+	if err != nil {
+		return keysPath, keys
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		keys[strings.ToLower(name)] = filepath.Join(keysPath, entry.Name())
+	}
+	return keysPath, keys
+}
+
+// formatSession 返回会话的显示内容，密码无法解密时同时返回对应的错误，
+// passwordErr 不为空时(主密码错误、缺少SID)不尝试解密，只在密码字段中显示该错误
+func formatSession(product string, xsh Xsh, userSID UserSID, userKeys map[string]string, passwordErr error) (string, error) {
+	var result strings.Builder
+	var decryptErr error
+
+	result.WriteString(i18n.Sprintf("  会话路径: %s\n", xsh.Path))
+	if xsh.Folder != "" {
+		result.WriteString(i18n.Sprintf("  文件夹: %s\n", xsh.Folder))
+	}
+	if xsh.Protocol != "" {
+		result.WriteString(i18n.Sprintf("  协议: %s\n", xsh.Protocol))
+	}
+	result.WriteString(i18n.Sprintf("  主机: %s\n", xsh.Host))
+	result.WriteString(fmt.Sprintf("  Port: %s\n", xsh.Port))
+	result.WriteString(i18n.Sprintf("  用户名: %s\n", xsh.UserName))
+	if xsh.AuthMethod != "" {
+		result.WriteString(i18n.Sprintf("  认证方式: %s\n", authMethodName(xsh.AuthMethod)))
+	}
+
+	if xsh.EncryptPw != "" && passwordErr != nil {
+		decryptErr = passwordErr
+		result.WriteString(i18n.Sprintf("  密码: 解密失败(%v)\n", passwordErr))
+	} else if xsh.EncryptPw != "" {
+		password, strategy, err := xdecrypt(xsh, userSID)
+		if err != nil {
+			decryptErr = err
+			result.WriteString(i18n.Sprintf("  密码: 解密失败(%v)\n", err))
+		} else {
+			result.WriteString(i18n.Sprintf("  密码: %s\n", password))
+			result.WriteString(i18n.Sprintf("  密钥派生: %s\n", strategy))
+			credential.Add(credential.Credential{Source: product, Name: xsh.Path, Host: xsh.Host, Port: xsh.Port, User: xsh.UserName, Password: password})
+		}
+	} else {
+		result.WriteString(i18n.T("  密码: 未保存\n"))
+	}
+
+	if xsh.UserKey != "" {
+		if keyPath, ok := userKeys[strings.ToLower(xsh.UserKey)]; ok {
+			result.WriteString(i18n.Sprintf("  用户密钥: %s (文件: %s)\n", xsh.UserKey, keyPath))
+		} else {
+			result.WriteString(i18n.Sprintf("  用户密钥: %s (UserKeys目录中未找到)\n", xsh.UserKey))
+		}
+	}
+	if xsh.Passphrase != "" && passwordErr != nil {
+		result.WriteString(i18n.Sprintf("  密钥口令: 解密失败(%v)\n", passwordErr))
+	} else if xsh.Passphrase != "" {
+		passphrase, _, err := xdecrypt(Xsh{EncryptPw: xsh.Passphrase, Version: xsh.Version}, userSID)
+		if err != nil {
+			result.WriteString(i18n.Sprintf("  密钥口令: 解密失败(%v)\n", err))
+		} else {
+			result.WriteString(i18n.Sprintf("  密钥口令: %s\n", passphrase))
+		}
+	}
+
+	if xsh.Proxy != "" {
+		result.WriteString(i18n.Sprintf("  代理: %s\n", xsh.Proxy))
+	}
+	for _, rule := range xsh.ForwardingRules {
+		result.WriteString(i18n.Sprintf("  端口转发: %s\n", rule))
+	}
+	if xsh.Description != "" {
+		result.WriteString(i18n.Sprintf("  描述: %s\n", xsh.Description))
+	}
+	result.WriteString(i18n.Sprintf("  版本: %s\n", xsh.Version))
+	result.WriteString("\n")
+
+	return result.String(), decryptErr
+}
+
+// scanSessions ScanXshell 和 ScanXftp 共用的扫描流程
+func scanSessions(customPath, product, ext string) (string, int, error) {
+	var resultBuilder strings.Builder
+
+	var userDataPaths []string
+	var err error
+
+	if customPath != "" {
+		userDataPaths = []string{customPath}
+	} else {
+		userDataPaths, err = getUserDataPath()
+		if err != nil {
+			if regsource.IsMissing(err) {
+				return "", 0, status.Errorf(status.ErrNotInstalled, "获取%s用户数据路径失败: %v", product, err)
+			}
+			return "", 0, i18n.Errorf("获取%s用户数据路径失败: %v", product, err)
+		}
+	}
+
+	if len(userDataPaths) == 0 {
+		return "", 0, status.Errorf(status.ErrNotInstalled, "未找到%s用户数据路径", product)
+	}
+
+	// 缺少SID时仍然列出会话，只是无法解密未启用主密码的会话中的密码
+	userSID, sidErr := getUserSID()
+	if sidErr != nil {
+		sidErr = i18n.Errorf("获取用户SID失败: %v，离线解密请使用 -xshell-user 和 -xshell-sid 指定", sidErr)
+	}
+
+	count := 0
+	var firstErr error
+
+	for _, userDataPath := range userDataPaths {
+		passwordErr := checkMasterPw(userDataPath)
+		if passwordErr != nil {
+			logger.Warn(i18n.Sprintf("检查主密码失败: %v", passwordErr), "path", userDataPath)
+			if firstErr == nil {
+				firstErr = passwordErr
+			}
+		} else if !enableMasterPasswd && sidErr != nil {
+			passwordErr = sidErr
+		}
+
+		sessionsPath, pathList, err := enumSessionPath(userDataPath, product, ext)
+		if err != nil {
+			logger.Warn(i18n.Sprintf("枚举%s文件失败: %v", strings.ToUpper(strings.TrimPrefix(ext, ".")), err), "path", userDataPath)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		keysPath, userKeys := listUserKeys(sessionsPath)
+		usedKeys := make(map[string]bool)
+
+		for _, path := range pathList {
+			xsh, err := sessionParser(path, sessionsPath)
+			if err != nil {
+				logger.Warn(i18n.Sprintf("解析%s文件失败: %v", strings.ToUpper(strings.TrimPrefix(ext, ".")), err), "path", path)
+				continue
+			}
+			usedKeys[strings.ToLower(xsh.UserKey)] = true
+			formatted, err := formatSession(product, xsh, userSID, userKeys, passwordErr)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			resultBuilder.WriteString(formatted)
+			count++
+		}
+
+		if len(userKeys) > 0 {
+			resultBuilder.WriteString(i18n.Sprintf("  UserKeys目录: %s\n", keysPath))
+			var names []string
+			for name := range userKeys {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if usedKeys[name] {
+					resultBuilder.WriteString(fmt.Sprintf("    %s\n", userKeys[name]))
+				} else {
+					resultBuilder.WriteString(i18n.Sprintf("    %s (未被会话引用)\n", userKeys[name]))
+				}
+			}
+			resultBuilder.WriteString("\n")
+		}
+	}
+
+	if count == 0 && firstErr == nil {
+		return "", 0, status.Errorf(status.ErrNotFound, "未找到%s会话文件", product)
+	}
+
+	return resultBuilder.String(), count, firstErr
+}
@@ -0,0 +1,79 @@
+package xshell
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsVersionDir(t *testing.T) {
+	tests := map[string]bool{
+		`C:\Users\a\Documents\NetSarang Computer\5`:      true,
+		`C:\Users\a\Documents\NetSarang Computer\7\`:     true,
+		`C:\Users\a\Documents\NetSarang Computer\8`:      true,
+		`/mnt/c/Users/a/Documents/NetSarang Computer/10`: true,
+		`C:\Users\a\Documents\NetSarang Computer\4`:      false,
+		`C:\Users\a\Documents\NetSarang\Xshell`:          false,
+		`D:\backup\xshell18`:                             false,
+		`8`:                                              true,
+	}
+	for path, want := range tests {
+		if got := isVersionDir(path); got != want {
+			t.Errorf("isVersionDir(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	for version, want := range map[string]bool{"5": true, "7.1": true, "8": true, "12": true, "4": false, "Common": false, "": false} {
+		if got := isSupportedVersion(version); got != want {
+			t.Errorf("isSupportedVersion(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 无法解密密码时(缺少SID、主密码错误)仍然列出所有会话，错误只出现在密码字段
+func TestScanSessionsWithoutPassword(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "7")
+	sessions := filepath.Join(dir, "Xshell", "Sessions")
+	writeTestFile(t, filepath.Join(sessions, "prod.xsh"), "[CONNECTION]\nHost=10.0.0.1\nPort=22\n[CONNECTION:AUTHENTICATION]\nUserName=root\nPassword=AAAA\n")
+	writeTestFile(t, filepath.Join(sessions, "keys", "key.xsh"), "[CONNECTION]\nHost=10.0.0.2\n[CONNECTION:AUTHENTICATION]\nUserName=deploy\nMethod=1\nUserKey=id_rsa\n")
+
+	defer func(user, sid, master string) { UserName, SID, MasterPassword = user, sid, master }(UserName, SID, MasterPassword)
+	UserName, SID, MasterPassword = "", "", ""
+	t.Setenv("USERNAME", "")
+
+	check := func(name string) string {
+		t.Helper()
+		output, count, err := scanSessions(dir, "Xshell", ".xsh")
+		if count != 2 || err == nil {
+			t.Fatalf("%s: scanSessions() count = %d, err = %v", name, count, err)
+		}
+		for _, want := range []string{"10.0.0.1", "10.0.0.2", "deploy", "id_rsa"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: output missing %q:\n%s", name, want, output)
+			}
+		}
+		return output
+	}
+
+	check("missing SID")
+
+	writeTestFile(t, filepath.Join(dir, "common", "MasterPassword.mpw"), "EnblMasterPasswd=1\nHashMasterPasswd="+strings.Repeat("00", 32)+"\n")
+	MasterPassword = "wrong"
+	_, _, err := scanSessions(dir, "Xshell", ".xsh")
+	if !errors.Is(err, ErrMasterPasswordWrong) {
+		t.Errorf("wrong master password: err = %v", err)
+	}
+	check("wrong master password")
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"e0e1-config/pkg/credential"
+	"e0e1-config/pkg/htmlreport"
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/search"
+	"e0e1-config/pkg/status"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// report 子命令未指定 -output 时的输出文件
+const (
+	defaultReportFile     = "e0e1-report.txt"
+	defaultHTMLReportFile = "e0e1-report.html"
+)
+
+// reportFormats report 子命令支持的报告格式
+var reportFormats = []string{"text", "html"}
+
+// runReport report 子命令: 先清点再收集，结果写入同一个报告文件
+func runReport(args []string) {
+	var opts collectOptions
+	var format string
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	opts.registerFlags(fs)
+	fs.StringVar(&format, "format", "", "报告格式 (text, html)，默认根据 -output 的扩展名判断")
+	fs.Usage = func() {
+		i18n.Printf("用法: e0e1-config report [选项] [模块...]\n模块: %s，默认全部\n\n选项:\n", strings.Join(modules, ", "))
+		fs.PrintDefaults()
+	}
+
+	if positional := parseFlags(fs, "report", args); len(positional) > 0 {
+		opts.Modules = strings.Join(positional, ",")
+	}
+	if err := opts.validate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+	format, err := reportFormat(format, opts.Output)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(2)
+	}
+	if err := opts.setupRegistry(); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if opts.Output == "" {
+		opts.Output = defaultReportFile
+		if format == "html" {
+			opts.Output = defaultHTMLReportFile
+		}
+	}
+
+	inventory := runInventory(opts)
+	result, records := collect(opts)
+	if format == "html" {
+		writeHTMLReport(opts, inventory, result, records)
+	} else {
+		table := status.Table(records)
+		writeResult(inventory+"\n"+result+"\n"+table, opts.Output)
+	}
+	printStatus(records)
+}
+
+// reportFormat 未指定 -format 时，-output 以 .html/.htm 结尾则生成HTML报告
+func reportFormat(format, output string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(output)) {
+		case ".html", ".htm":
+			return "html", nil
+		}
+		return "text", nil
+	}
+	for _, f := range reportFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", i18n.Errorf("不支持的报告格式: %s，可选: %s", format, strings.Join(reportFormats, ", "))
+}
+
+// writeHTMLReport 用各模块记录的结构化结果生成单文件HTML报告
+func writeHTMLReport(opts collectOptions, inventory, result string, records []status.Record) {
+	host, _ := os.Hostname()
+	analysis := credential.Analyze(credential.All())
+	data := htmlreport.Data{
+		Generated: time.Now(),
+		Host:      host,
+		Records:   records,
+		Analysis:  analysis,
+		Strength:  credential.Assess(analysis.Credentials),
+		Hits:      search.Hits(),
+		Inventory: inventory,
+		Result:    result,
+	}
+	if err := htmlreport.Write(opts.Output, data); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	logger.Info(i18n.Sprintf("报告已保存到: %s", opts.Output))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"e0e1-config/pkg/i18n"
+	"e0e1-config/pkg/logger"
+	"e0e1-config/pkg/status"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// job 一个待执行的模块，group 相同的模块共享包级状态，只能依次执行
+type job struct {
+	group  string
+	name   string
+	header string
+	scan   func() (string, int, error)
+}
+
+type jobResult struct {
+	output string
+	record status.Record
+}
+
+// collector 收集要执行的模块，wait 时并发执行，结果按添加顺序输出
+type collector struct {
+	jobs []job
+}
+
+func (c *collector) add(name, header string, scan func() (string, int, error)) {
+	c.addShared("", name, header, scan)
+}
+
+// addShared 添加需要和同组模块依次执行的模块，group 为空表示可以独立执行
+func (c *collector) addShared(group, name, header string, scan func() (string, int, error)) {
+	c.jobs = append(c.jobs, job{group: group, name: name, header: header, scan: scan})
+}
+
+// wait 最多同时执行 limit 个模块，返回的结果和状态与并发数无关，始终按添加顺序排列
+func (c *collector) wait(limit int) (string, []status.Record) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	// 同一组的模块放在同一个任务中依次执行
+	var units [][]int
+	groups := make(map[string]int)
+	for i, j := range c.jobs {
+		if j.group == "" {
+			units = append(units, []int{i})
+			continue
+		}
+		if u, ok := groups[j.group]; ok {
+			units[u] = append(units[u], i)
+			continue
+		}
+		groups[j.group] = len(units)
+		units = append(units, []int{i})
+	}
+
+	results := make([]jobResult, len(c.jobs))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, unit := range units {
+		wg.Add(1)
+		go func(unit []int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			for _, i := range unit {
+				results[i] = runJob(c.jobs[i])
+			}
+		}(unit)
+	}
+	wg.Wait()
+
+	var result strings.Builder
+	records := make([]status.Record, 0, len(results))
+	for i, r := range results {
+		records = append(records, r.record)
+		if r.output != "" {
+			result.WriteString(c.jobs[i].header)
+			result.WriteString(r.output)
+			result.WriteString("\n")
+		}
+	}
+	return result.String(), records
+}
+
+// runJob 执行一个模块，模块返回错误时仍然保留已获取的部分结果
+func runJob(j job) jobResult {
+	logger.Info(i18n.Sprintf("正在扫描%s...", j.name), "module", j.name)
+	start := time.Now()
+	output, count, err := j.scan()
+	record := status.Record{
+		Module:   j.name,
+		Kind:     status.Classify(err),
+		Items:    count,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if err != nil {
+		// 未安装和未找到是正常情况，-q 时不输出
+		level := slog.LevelWarn
+		if record.Kind == status.NotInstalled || record.Kind == status.NotFound {
+			level = slog.LevelInfo
+		}
+		logger.Log(level, i18n.Sprintf("%s扫描失败: %v", j.name, err), "module", j.name, "status", string(record.Kind))
+	}
+	return jobResult{output: output, record: record}
+}